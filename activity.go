@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"connectrpc.com/connect"
+)
+
+const (
+	// activityCommitsPerModule is how many of each module's latest commits
+	// the activity feed considers; activityLimit is how many it shows in
+	// all, newest first.
+	activityCommitsPerModule = 20
+	activityLimit            = 200
+	// activityConcurrency bounds how many modules' commits are listed at
+	// once, so a large owner doesn't fire off hundreds of requests.
+	activityConcurrency = 8
+)
+
+// activityMsg carries owner's latest commits across all of its modules,
+// newest first. failed is how many modules' commits couldn't be listed.
+type activityMsg struct {
+	owner   string
+	entries []*activityEntry
+	failed  int
+}
+
+type activityErrMsg struct{ err error }
+
+func (e activityErrMsg) Error() string { return e.err.Error() }
+
+// activityEntry is a commit in the activity feed, with the module it's
+// from.
+type activityEntry struct {
+	module *modulev1.Module
+	commit
+}
+
+// FilterValue implements [list.Item]. The module name is included so the
+// feed can be narrowed to a module, as are the commit's author and labels
+// (see [commitFilter]).
+func (a *activityEntry) FilterValue() string {
+	return a.module.Name + " " + a.commit.FilterValue()
+}
+
+// Title implements [list.DefaultItem].
+func (a *activityEntry) Title() string {
+	return a.module.Name + " " + a.commit.Title()
+}
+
+// listActivity lists owner's latest commits across all of its modules: every
+// module, then the latest commits of each, activityConcurrency modules at a
+// time, with their authors and labels. Each request gets its own
+// rpcTimeout, so an owner with many modules doesn't time out as a whole.
+//
+// A module whose commits can't be listed is left out and counted rather
+// than failing the feed, and authors and labels are niceties, as they are in
+// the commit list.
+func (c *client) listActivity(remote, owner string) tea.Cmd {
+	return func() tea.Msg {
+		var modules []*modulev1.Module
+		pageToken := ""
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+			response, err := c.listModulesPage(ctx, owner, pageToken)
+			cancel()
+			if err != nil {
+				return activityErrMsg{fmt.Errorf("listing modules: %w", err)}
+			}
+			modules = append(modules, response.Msg.Modules...)
+			if response.Msg.NextPageToken == "" {
+				break
+			}
+			pageToken = response.Msg.NextPageToken
+		}
+
+		var (
+			mu      sync.Mutex
+			entries []*activityEntry
+			failed  int
+			wg      sync.WaitGroup
+		)
+		sem := make(chan struct{}, activityConcurrency)
+		for _, module := range modules {
+			wg.Go(func() {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+				defer cancel()
+				response, err := c.commitServiceClient.ListCommits(ctx, connect.NewRequest(&modulev1.ListCommitsRequest{
+					PageSize: activityCommitsPerModule,
+					ResourceRef: &modulev1.ResourceRef{
+						Value: &modulev1.ResourceRef_Name_{
+							Name: &modulev1.ResourceRef_Name{Owner: owner, Module: module.Name},
+						},
+					},
+				}))
+				if err != nil {
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					return
+				}
+				var labels map[string][]string
+				if all, err := c.allLabels(ctx, owner, module.Name, labelArchiveFilterUnarchived); err == nil {
+					labels = labelsByCommit(all)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, commit := range response.Msg.Commits {
+					entries = append(entries, newActivityEntry(remote, owner, module, commit, labels[commit.Id]))
+				}
+			})
+		}
+		wg.Wait()
+
+		slices.SortStableFunc(entries, func(a, b *activityEntry) int {
+			return b.underlying.CreateTime.AsTime().Compare(a.underlying.CreateTime.AsTime())
+		})
+		entries = entries[:min(len(entries), activityLimit)]
+
+		commits := make([]*modulev1.Commit, len(entries))
+		for i, entry := range entries {
+			commits[i] = entry.underlying
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		authors, _ := c.resolveUserNames(ctx, uniqueUserIDs(commits))
+		for _, entry := range entries {
+			entry.author = authors[entry.underlying.CreatedByUserId]
+		}
+		return activityMsg{owner: owner, entries: entries, failed: failed}
+	}
+}
+
+func newActivityEntry(remote, owner string, module *modulev1.Module, c *modulev1.Commit, labels []string) *activityEntry {
+	return &activityEntry{
+		module: module,
+		commit: commit{
+			underlying: c,
+			remote:     remote,
+			owner:      owner,
+			moduleName: module.Name,
+			labels:     labels,
+		},
+	}
+}
+
+// activityItems turns the feed's entries into list items.
+func activityItems(entries []*activityEntry) []list.Item {
+	items := make([]list.Item, len(entries))
+	for i, entry := range entries {
+		items[i] = entry
+	}
+	return items
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"go.vanburen.xyz/ok"
+)
+
+// TestActivity verifies A lists the latest commits across the owner's
+// modules, newest first and annotated, and enter opens one in its module.
+func TestActivity(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	updated, _ := m.Update(c.listModules(m.currentOwner)())
+	m = updated.(model)
+	press := func(code rune, text string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+		return cmd
+	}
+
+	cmd := press('A', "A")
+	ok.Equal(t, m.state, modelStateLoadingActivity)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingActivity)
+
+	// Each of the two modules has the same three commits.
+	items := m.activityList.Items()
+	ok.Equal(t, len(items), 6)
+	modules := map[string]bool{}
+	for i, item := range items {
+		entry := item.(*activityEntry)
+		modules[entry.module.Name] = true
+		if i > 0 {
+			prev := items[i-1].(*activityEntry).underlying.CreateTime.AsTime()
+			ok.True(t, !entry.underlying.CreateTime.AsTime().After(prev), ok.Sprintf("entry %d is newer than the one before it", i))
+		}
+	}
+	ok.Equal(t, len(modules), 2)
+	newest := items[0].(*activityEntry)
+	ok.True(t, strings.HasSuffix(newest.Title(), " abc123def456 [main]"), ok.Sprintf("unexpected title %q", newest.Title()))
+	ok.True(t, strings.HasPrefix(newest.Title(), newest.module.Name), ok.Sprintf("unexpected title %q", newest.Title()))
+	ok.True(t, strings.Contains(newest.Description(), "alice"), ok.Sprintf("unexpected description %q", newest.Description()))
+
+	press(tea.KeyEnter, "")
+	ok.Equal(t, m.state, modelStateLoadingCommitFileContents)
+	ok.Equal(t, m.currentModule, newest.module.Name)
+	ok.Equal(t, m.currentCommitID, "abc123def456")
+
+	// Esc goes back to the modules.
+	m.state = modelStateBrowsingActivity
+	press(tea.KeyEscape, "")
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+
+	// A feed for an owner since left is dropped.
+	updated, _ = m.Update(activityMsg{owner: "other"})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+}
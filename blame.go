@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// blameMaxCommits caps how many older commits a blame compiles. Each is a
+// full compileDocs of that commit, so walking a long history would take
+// minutes and churn docsCache; symbols unchanged for longer than this are
+// shown as "at or before" the oldest commit walked. It's kept below
+// docsCacheMaxEntries so blaming again hits the cache.
+const blameMaxCommits = 12
+
+// symbolBlame is the commit a field or enum value got its current
+// definition in.
+type symbolBlame struct {
+	commit *modulev1.Commit
+	// bounded is set when the walk stopped before finding the commit that
+	// changed the symbol, so it only last changed at or before commit.
+	bounded bool
+}
+
+// String renders the blame as it appears after the symbol in the docs.
+func (b symbolBlame) String() string {
+	s := fmt.Sprintf("%s · %s", b.commit.Id[:min(12, len(b.commit.Id))], relativeTime(b.commit.CreateTime.AsTime()))
+	if b.bounded {
+		s = "≤ " + s
+	}
+	return s
+}
+
+// symbolBlames maps each field and enum value of a docsPackage, by full
+// name, to its blame.
+type symbolBlames map[protoreflect.FullName]symbolBlame
+
+// annotation renders d's blame for the end of its line in the docs, or ""
+// if it has none.
+func (s symbolBlames) annotation(d protoreflect.Descriptor, dimStyle lipgloss.Style) string {
+	blame, ok := s[d.FullName()]
+	if !ok {
+		return ""
+	}
+	return "  " + dimStyle.Render(blame.String())
+}
+
+// blameSymbols returns every field, extension and enum value rendered on
+// pkg's docs page.
+func blameSymbols(pkg *docsPackage) []protoreflect.Descriptor {
+	var symbols []protoreflect.Descriptor
+	addEnum := func(enum protoreflect.EnumDescriptor) {
+		for i := range enum.Values().Len() {
+			symbols = append(symbols, enum.Values().Get(i))
+		}
+	}
+	var addMessage func(protoreflect.MessageDescriptor)
+	addMessage = func(msg protoreflect.MessageDescriptor) {
+		for i := range msg.Fields().Len() {
+			symbols = append(symbols, msg.Fields().Get(i))
+		}
+		for i := range msg.Extensions().Len() {
+			symbols = append(symbols, msg.Extensions().Get(i))
+		}
+		for i := range msg.Enums().Len() {
+			addEnum(msg.Enums().Get(i))
+		}
+		for i := range msg.Messages().Len() {
+			if nested := msg.Messages().Get(i); !nested.IsMapEntry() {
+				addMessage(nested)
+			}
+		}
+	}
+	for _, msg := range pkg.messages {
+		addMessage(msg)
+	}
+	for _, enum := range pkg.enums {
+		addEnum(enum)
+	}
+	for _, ext := range pkg.extensions {
+		symbols = append(symbols, ext)
+	}
+	return symbols
+}
+
+// blameSignature summarizes what a field or enum value means on the wire and
+// in JSON, so a blame attributes it to the commit that last changed that.
+// Comments and options are left out: editing a field's documentation
+// doesn't make it a different field.
+func blameSignature(d protoreflect.Descriptor) string {
+	switch d := d.(type) {
+	case protoreflect.FieldDescriptor:
+		sig := fmt.Sprintf("%d %v %v %s %s %v", d.Number(), d.Cardinality(), d.Kind(), fieldTypeSignature(d), d.JSONName(), d.HasPresence())
+		if d.IsMap() {
+			sig += " map<" + fieldTypeSignature(d.MapKey()) + "," + fieldTypeSignature(d.MapValue()) + ">"
+		}
+		if oneof := d.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			sig += " oneof " + string(oneof.Name())
+		}
+		if d.IsExtension() {
+			sig += " extends " + string(d.ContainingMessage().FullName())
+		}
+		if d.HasDefault() {
+			sig += fmt.Sprintf(" default %v", d.Default().Interface())
+		}
+		return sig
+	case protoreflect.EnumValueDescriptor:
+		return fmt.Sprintf("%d", d.Number())
+	}
+	return ""
+}
+
+// fieldTypeSignature is the part of a field's blameSignature naming its
+// type: its kind, and for messages and enums the type's full name.
+func fieldTypeSignature(f protoreflect.FieldDescriptor) string {
+	switch {
+	case f.Message() != nil:
+		return string(f.Message().FullName())
+	case f.Enum() != nil:
+		return string(f.Enum().FullName())
+	default:
+		return f.Kind().String()
+	}
+}
+
+// blameWalk attributes a docsPackage's fields and enum values to commits by
+// comparing their blameSignatures against each older commit in turn: a
+// symbol is blamed on the oldest commit of the unbroken run, back from the
+// current one, in which its signature is unchanged.
+type blameWalk struct {
+	pkg *docsPackage
+	// ctx is what the walk's steps run under, canceled by resetBlame.
+	ctx context.Context
+	// signatures holds the current signatures of the symbols not yet
+	// blamed, and candidates the oldest commit walked with each.
+	signatures map[protoreflect.FullName]string
+	candidates map[protoreflect.FullName]*modulev1.Commit
+	blames     symbolBlames
+	walked     int
+}
+
+func newBlameWalk(ctx context.Context, pkg *docsPackage, current *modulev1.Commit) *blameWalk {
+	w := &blameWalk{
+		pkg:        pkg,
+		ctx:        ctx,
+		signatures: make(map[protoreflect.FullName]string),
+		candidates: make(map[protoreflect.FullName]*modulev1.Commit),
+		blames:     make(symbolBlames),
+	}
+	for _, symbol := range blameSymbols(pkg) {
+		w.signatures[symbol.FullName()] = blameSignature(symbol)
+		w.candidates[symbol.FullName()] = current
+	}
+	return w
+}
+
+// step compares the symbols not yet blamed against files, as compiled at
+// commit, the next older commit: each that's missing or different there is
+// blamed on its candidate, and the rest become candidates of commit.
+func (w *blameWalk) step(commit *modulev1.Commit, files *protoregistry.Files) {
+	w.walked++
+	for name, sig := range w.signatures {
+		if d, err := files.FindDescriptorByName(name); err == nil && blameSignature(d) == sig {
+			w.candidates[name] = commit
+			continue
+		}
+		w.blames[name] = symbolBlame{commit: w.candidates[name]}
+		delete(w.signatures, name)
+	}
+}
+
+// done reports whether every symbol has been blamed.
+func (w *blameWalk) done() bool {
+	return len(w.signatures) == 0
+}
+
+// finish blames the symbols still unresolved on their candidates: the
+// module's first commit if the walk reached it, or else (bounded) the
+// oldest commit it got to.
+func (w *blameWalk) finish(bounded bool) symbolBlames {
+	for name := range w.signatures {
+		w.blames[name] = symbolBlame{commit: w.candidates[name], bounded: bounded}
+	}
+	clear(w.signatures)
+	return w.blames
+}
+
+// blameStepMsg carries the next commit older than the one being blamed,
+// compiled, or a nil commit once the module's history is exhausted.
+type blameStepMsg struct {
+	// id identifies the blame, so steps of one that has been canceled or
+	// replaced are dropped.
+	id            int
+	commit        *modulev1.Commit
+	files         *protoregistry.Files
+	pending       []*modulev1.Commit
+	nextPageToken string
+}
+
+type blameErrMsg struct {
+	id  int
+	err error
+}
+
+func (e blameErrMsg) Error() string { return e.err.Error() }
+
+// blameStep compiles the next commit of owner/module older than commitID,
+// via compiledDocs (and so docsCache). Unless located, it first pages
+// through the module's commits, newest first, to find commitID; after that,
+// pending and pageToken carry on from wherever the last step left off.
+func (c *client) blameStep(ctx context.Context, id int, owner, module, commitID string, located bool, pending []*modulev1.Commit, pageToken string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, compileDocsTimeout)
+		defer cancel()
+		for !located {
+			response, err := c.listCommitsPage(ctx, owner, module, pageToken)
+			if err != nil {
+				return blameErrMsg{id, fmt.Errorf("getting commits: %w", err)}
+			}
+			pending, pageToken = response.Msg.Commits, response.Msg.NextPageToken
+			if i := slices.IndexFunc(pending, func(commit *modulev1.Commit) bool { return commit.Id == commitID }); i >= 0 {
+				pending = pending[i+1:]
+				located = true
+			} else if pageToken == "" {
+				return blameErrMsg{id, fmt.Errorf("commit %s isn't in %s/%s's history", commitID, owner, module)}
+			}
+		}
+		msg := blameStepMsg{id: id}
+		if len(pending) == 0 {
+			if pageToken == "" {
+				return msg
+			}
+			response, err := c.listCommitsPage(ctx, owner, module, pageToken)
+			if err != nil {
+				return blameErrMsg{id, fmt.Errorf("getting commits: %w", err)}
+			}
+			pending, pageToken = response.Msg.Commits, response.Msg.NextPageToken
+			if len(pending) == 0 {
+				return msg
+			}
+		}
+		msg.commit, msg.pending, msg.nextPageToken = pending[0], pending[1:], pageToken
+		response, err := c.downloadServiceClient.Download(ctx, connect.NewRequest(&modulev1.DownloadRequest{
+			Values: []*modulev1.DownloadRequest_Value{{
+				ResourceRef: &modulev1.ResourceRef{
+					Value: &modulev1.ResourceRef_Id{Id: msg.commit.Id},
+				},
+				FileTypes: []modulev1.FileType{modulev1.FileType_FILE_TYPE_PROTO},
+			}},
+		}))
+		if err != nil {
+			return blameErrMsg{id, fmt.Errorf("getting commit %s: %w", msg.commit.Id, err)}
+		}
+		if len(response.Msg.Contents) != 1 {
+			return blameErrMsg{id, fmt.Errorf("requested 1 commit contents, got %v", len(response.Msg.Contents))}
+		}
+		entry, err := c.compiledDocs(ctx, msg.commit.Id, response.Msg.Contents[0].Files)
+		if err != nil {
+			return blameErrMsg{id, fmt.Errorf("compiling commit %s: %w", msg.commit.Id, err)}
+		}
+		msg.files = entry.files
+		return msg
+	}
+}
+
+// toggleBlame starts blaming the docs package on screen, or if it's already
+// blamed (or being blamed), takes the blame away again.
+func (m *model) toggleBlame() tea.Cmd {
+	pkg, ok := m.docsList.SelectedItem().(*docsPackage)
+	if !ok || m.currentCommit == nil {
+		return nil
+	}
+	if pkg.blame != nil || (m.blame != nil && m.blame.pkg == pkg) {
+		m.resetBlame()
+		pkg.blame = nil
+		m.renderDocsPackage()
+		return nil
+	}
+	m.resetBlame()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.blameCancel = cancel
+	m.blame = newBlameWalk(ctx, pkg, m.currentCommit)
+	if m.blame.done() {
+		// Nothing to blame: the package has only services.
+		m.finishBlame(false)
+		return nil
+	}
+	return m.client.blameStep(ctx, m.blameID, m.currentOwner, m.currentModule, m.currentCommitID, false, nil, "")
+}
+
+// continueBlame records a step of the blame and takes the next, unless
+// every symbol is blamed or the walk has reached blameMaxCommits.
+func (m *model) continueBlame(msg blameStepMsg) tea.Cmd {
+	if msg.commit == nil {
+		m.finishBlame(false)
+		return nil
+	}
+	m.blame.step(msg.commit, msg.files)
+	switch {
+	case m.blame.done():
+		m.finishBlame(false)
+		return nil
+	case m.blame.walked >= blameMaxCommits:
+		m.finishBlame(true)
+		return nil
+	}
+	return m.client.blameStep(m.blame.ctx, msg.id, m.currentOwner, m.currentModule, m.currentCommitID, true, msg.pending, msg.nextPageToken)
+}
+
+// finishBlame hands the blame's results to its package, re-rendering it if
+// it's the one on screen.
+func (m *model) finishBlame(bounded bool) {
+	walk := m.blame
+	walk.pkg.blame = walk.finish(bounded)
+	m.blame = nil
+	if m.blameCancel != nil {
+		m.blameCancel()
+		m.blameCancel = nil
+	}
+	if pkg, ok := m.docsList.SelectedItem().(*docsPackage); ok && pkg == walk.pkg {
+		m.renderDocsPackage()
+	}
+}
+
+// resetBlame cancels the blame in progress, if any, and drops its steps
+// still in flight. Finished blames stay with their packages.
+func (m *model) resetBlame() {
+	m.blameID++
+	m.blame = nil
+	m.blameErr = nil
+	if m.blameCancel != nil {
+		m.blameCancel()
+		m.blameCancel = nil
+	}
+}
+
+// renderDocsPackage re-renders the docs package on screen in place, e.g.
+// once it's been blamed. Search matches are byte offsets into the old
+// rendering, so they're dropped.
+func (m *model) renderDocsPackage() {
+	pkg, ok := m.docsList.SelectedItem().(*docsPackage)
+	if !ok {
+		return
+	}
+	m.resetDocsSearch()
+	m.docsViewport.SetContent(renderPackage(pkg, m.isDark))
+}
+
+// blameStatusView renders the blame's progress, or why it stopped short,
+// for the docs search row while the search isn't open.
+func (m model) blameStatusView() string {
+	switch {
+	case m.blame != nil:
+		return m.spinner.View() + fmt.Sprintf(" Blaming %s · compared %d of up to %d older commits", m.blame.pkg.name, m.blame.walked, blameMaxCommits)
+	case m.blameErr != nil:
+		return lipgloss.NewStyle().Foreground(colorError).Render("Error blaming: " + m.blameErr.Error())
+	}
+	return ""
+}
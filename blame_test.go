@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// blameTestRegistry builds a blame.proto with a message Msg of the given
+// fields and an enum Status with the given values, in package blame.
+func blameTestRegistry(t *testing.T, fields []*descriptorpb.FieldDescriptorProto, values []*descriptorpb.EnumValueDescriptorProto) *protoregistry.Files {
+	t.Helper()
+	return buildTestRegistry(t, &descriptorpb.FileDescriptorProto{
+		Name:        new("blame.proto"),
+		Syntax:      new("proto3"),
+		Package:     new("blame"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: new("Msg"), Field: fields}},
+		EnumType:    []*descriptorpb.EnumDescriptorProto{{Name: new("Status"), Value: values}},
+	})
+}
+
+func blameTestField(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{Name: new(name), Number: new(number), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: typ.Enum()}
+}
+
+func blameTestValue(name string, number int32) *descriptorpb.EnumValueDescriptorProto {
+	return &descriptorpb.EnumValueDescriptorProto{Name: new(name), Number: new(number)}
+}
+
+func TestBlameWalk(t *testing.T) {
+	t.Parallel()
+
+	stringType, int32Type := descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_INT32
+	current := blameTestRegistry(t,
+		[]*descriptorpb.FieldDescriptorProto{blameTestField("id", 1, stringType), blameTestField("count", 2, int32Type), blameTestField("note", 3, stringType)},
+		[]*descriptorpb.EnumValueDescriptorProto{blameTestValue("STATUS_UNSPECIFIED", 0), blameTestValue("STATUS_OK", 1)},
+	)
+	// One commit back, count was a string; two back, note didn't exist and
+	// STATUS_OK was 2. Comments and options never matter.
+	older := blameTestRegistry(t,
+		[]*descriptorpb.FieldDescriptorProto{blameTestField("id", 1, stringType), blameTestField("count", 2, stringType), blameTestField("note", 3, stringType)},
+		[]*descriptorpb.EnumValueDescriptorProto{blameTestValue("STATUS_UNSPECIFIED", 0), blameTestValue("STATUS_OK", 1)},
+	)
+	oldest := blameTestRegistry(t,
+		[]*descriptorpb.FieldDescriptorProto{blameTestField("id", 1, stringType), blameTestField("count", 2, stringType)},
+		[]*descriptorpb.EnumValueDescriptorProto{blameTestValue("STATUS_UNSPECIFIED", 0), blameTestValue("STATUS_OK", 2)},
+	)
+	commit := func(id string) *modulev1.Commit {
+		return &modulev1.Commit{Id: id, CreateTime: timestamppb.New(time.Now().Add(-time.Hour))}
+	}
+	c0, c1, c2 := commit("c0"), commit("c1"), commit("c2")
+
+	pkg := packagesFromDocs(current, map[string]bool{"blame.proto": true})[0].(*docsPackage)
+	walk := newBlameWalk(t.Context(), pkg, c0)
+	walk.step(c1, older)
+	ok.True(t, !walk.done())
+	walk.step(c2, oldest)
+	ok.True(t, !walk.done(), ok.Sprintf("id and STATUS_UNSPECIFIED are unchanged since c2"))
+	blames := walk.finish(false)
+
+	ok.Equal(t, blames["blame.Msg.count"], symbolBlame{commit: c0})
+	ok.Equal(t, blames["blame.Msg.note"], symbolBlame{commit: c1})
+	ok.Equal(t, blames["blame.STATUS_OK"], symbolBlame{commit: c1})
+	ok.Equal(t, blames["blame.Msg.id"], symbolBlame{commit: c2})
+	ok.Equal(t, blames["blame.STATUS_UNSPECIFIED"], symbolBlame{commit: c2})
+
+	// A walk that stops short can only bound the unchanged symbols.
+	walk = newBlameWalk(t.Context(), pkg, c0)
+	walk.step(c1, older)
+	blames = walk.finish(true)
+	ok.Equal(t, blames["blame.Msg.count"], symbolBlame{commit: c0})
+	ok.Equal(t, blames["blame.Msg.id"], symbolBlame{commit: c1, bounded: true})
+}
+
+func TestRenderPackage_Blame(t *testing.T) {
+	t.Parallel()
+
+	files := blameTestRegistry(t,
+		[]*descriptorpb.FieldDescriptorProto{blameTestField("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+		[]*descriptorpb.EnumValueDescriptorProto{blameTestValue("STATUS_UNSPECIFIED", 0)},
+	)
+	pkg := packagesFromDocs(files, map[string]bool{"blame.proto": true})[0].(*docsPackage)
+	out := ansi.Strip(renderPackage(pkg, false))
+	ok.True(t, !strings.Contains(out, "ago"), ok.Sprintf("unblamed package has blame: %q", out))
+
+	created := timestamppb.New(time.Now().Add(-3 * 24 * time.Hour))
+	pkg.blame = symbolBlames{
+		"blame.Msg.id":             {commit: &modulev1.Commit{Id: "abc123def4567890", CreateTime: created}},
+		"blame.STATUS_UNSPECIFIED": {commit: &modulev1.Commit{Id: "def456", CreateTime: created}, bounded: true},
+	}
+	out = ansi.Strip(renderPackage(pkg, false))
+	ok.True(t, strings.Contains(out, "string id = 1  abc123def456 · 3d ago\n"), ok.Sprintf("field blame missing: %q", out))
+	ok.True(t, strings.Contains(out, "STATUS_UNSPECIFIED = 0  ≤ def456 · 3d ago"), ok.Sprintf("enum value blame missing: %q", out))
+}
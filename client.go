@@ -10,6 +10,7 @@ import (
 
 	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/module/v1/modulev1connect"
 	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/owner/v1/ownerv1connect"
+	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/plugin/v1beta1/pluginv1beta1connect"
 	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
 	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
 	tea "charm.land/bubbletea/v2"
@@ -55,6 +56,9 @@ const docsCacheMaxEntries = 16
 type docsCacheEntry struct {
 	files   *protoregistry.Files
 	skipped []string
+	// descriptorSet is the marshaled FileDescriptorSet files was built
+	// from, kept to write out (see descriptors.go).
+	descriptorSet []byte
 }
 
 type client struct {
@@ -65,6 +69,17 @@ type client struct {
 	labelServiceClient    modulev1connect.LabelServiceClient
 	graphServiceClient    modulev1connect.GraphServiceClient
 	ownerServiceClient    ownerv1connect.OwnerServiceClient
+	// The plugin services, for browsing an owner's plugins (see
+	// plugins.go).
+	pluginServiceClient       pluginv1beta1connect.PluginServiceClient
+	pluginCommitServiceClient pluginv1beta1connect.CommitServiceClient
+	pluginLabelServiceClient  pluginv1beta1connect.LabelServiceClient
+
+	// httpClient, address and token are kept for the requests that aren't
+	// RPCs: the generated SDK registries (see sdk.go).
+	httpClient connect.HTTPClient
+	address    string
+	token      string
 
 	// docsCache holds compiled docs keyed by commit ID. Commits are
 	// immutable on the BSR, so a cached entry never needs invalidating --
@@ -82,27 +97,64 @@ func newClient(httpClient connect.HTTPClient, remote, token string) *client {
 	)
 	address := "https://" + remote
 	return &client{
-		moduleServiceClient:   modulev1connect.NewModuleServiceClient(httpClient, address, options),
-		commitServiceClient:   modulev1connect.NewCommitServiceClient(httpClient, address, options),
-		downloadServiceClient: modulev1connect.NewDownloadServiceClient(httpClient, address, options),
-		resourceServiceClient: modulev1connect.NewResourceServiceClient(httpClient, address, options),
-		labelServiceClient:    modulev1connect.NewLabelServiceClient(httpClient, address, options),
-		graphServiceClient:    modulev1connect.NewGraphServiceClient(httpClient, address, options),
-		ownerServiceClient:    ownerv1connect.NewOwnerServiceClient(httpClient, address, options),
+		moduleServiceClient:       modulev1connect.NewModuleServiceClient(httpClient, address, options),
+		commitServiceClient:       modulev1connect.NewCommitServiceClient(httpClient, address, options),
+		downloadServiceClient:     modulev1connect.NewDownloadServiceClient(httpClient, address, options),
+		resourceServiceClient:     modulev1connect.NewResourceServiceClient(httpClient, address, options),
+		labelServiceClient:        modulev1connect.NewLabelServiceClient(httpClient, address, options),
+		graphServiceClient:        modulev1connect.NewGraphServiceClient(httpClient, address, options),
+		ownerServiceClient:        ownerv1connect.NewOwnerServiceClient(httpClient, address, options),
+		pluginServiceClient:       pluginv1beta1connect.NewPluginServiceClient(httpClient, address, options),
+		pluginCommitServiceClient: pluginv1beta1connect.NewCommitServiceClient(httpClient, address, options),
+		pluginLabelServiceClient:  pluginv1beta1connect.NewLabelServiceClient(httpClient, address, options),
+		httpClient:                httpClient,
+		address:                   address,
+		token:                     token,
 	}
 }
 
-type modulesMsg []*modulev1.Module
+// modulesMsg carries the first page of an owner's modules.
+type modulesMsg struct {
+	modules       []*modulev1.Module
+	nextPageToken string
+}
+
+// moreModulesMsg carries the pages of owner's modules from pageToken on,
+// so a response for another owner, or for pages already appended, can be
+// dropped.
+type moreModulesMsg struct {
+	owner         string
+	pageToken     string
+	modules       []*modulev1.Module
+	nextPageToken string
+}
+
+// moreModulesErrMsg reports that listing owner's modules from pageToken on
+// failed, carrying the pages fetched before the one that failed, whose
+// token is left in nextPageToken to be tried again.
+type moreModulesErrMsg struct {
+	moreModulesMsg
+	err error
+}
+
+func (e moreModulesErrMsg) Error() string { return e.err.Error() }
 
-type labelsMsg []*modulev1.Label
+// labelsMsg carries a module's labels along with the archive filter they
+// were listed with, so a response for a filter the user has since toggled
+// away from can be dropped.
+type labelsMsg struct {
+	labels        []*modulev1.Label
+	archiveFilter labelArchiveFilter
+}
 
 // docsMsg carries the compiled registry along with the full names of any
 // messages silently skipped while building it (currently: legacy MessageSet
 // messages -- see stripMessageSets), so the caller can let the user know
-// something was omitted.
+// something was omitted, and the descriptor set it was built from.
 type docsMsg struct {
-	files   *protoregistry.Files
-	skipped []string
+	files         *protoregistry.Files
+	skipped       []string
+	descriptorSet []byte
 }
 
 // docsErrMsg is compileDocs' own error type, distinct from the generic
@@ -118,38 +170,62 @@ type docsErrMsg struct{ err error }
 
 func (e docsErrMsg) Error() string { return e.err.Error() }
 
+// listModules lists the first page of currentOwner's modules; the rest are
+// fetched as they're scrolled to (see listMoreModules).
 func (c *client) listModules(currentOwner string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
 		defer cancel()
-		var allModules []*modulev1.Module
-		pageToken := ""
-		for {
-			request := connect.NewRequest(&modulev1.ListModulesRequest{
-				PageSize:  pageSize,
-				PageToken: pageToken,
-				OwnerRefs: []*ownerv1.OwnerRef{
-					{
-						Value: &ownerv1.OwnerRef_Name{
-							Name: currentOwner,
-						},
-					},
-				},
-			})
-			response, err := c.moduleServiceClient.ListModules(ctx, request)
+		response, err := c.listModulesPage(ctx, currentOwner, "")
+		if err != nil {
+			return errMsg{fmt.Errorf("listing modules: %w", err)}
+		}
+		return modulesMsg{
+			modules:       response.Msg.Modules,
+			nextPageToken: response.Msg.NextPageToken,
+		}
+	}
+}
+
+// listMoreModules lists the page of currentOwner's modules at pageToken, or
+// with all set, that page and every one after it -- each page within its own
+// rpcTimeout, so an owner with very many modules can still be listed in full.
+func (c *client) listMoreModules(currentOwner, pageToken string, all bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := moreModulesMsg{owner: currentOwner, pageToken: pageToken, nextPageToken: pageToken}
+		for msg.nextPageToken != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+			response, err := c.listModulesPage(ctx, currentOwner, msg.nextPageToken)
+			cancel()
 			if err != nil {
-				return errMsg{fmt.Errorf("listing modules: %w", err)}
+				return moreModulesErrMsg{msg, fmt.Errorf("getting more modules: %w", err)}
 			}
-			allModules = append(allModules, response.Msg.Modules...)
-			if response.Msg.NextPageToken == "" {
+			msg.modules = append(msg.modules, response.Msg.Modules...)
+			msg.nextPageToken = response.Msg.NextPageToken
+			if !all {
 				break
 			}
-			pageToken = response.Msg.NextPageToken
 		}
-		return modulesMsg(allModules)
+		return msg
 	}
 }
 
+// listModulesPage lists the page of an owner's modules at pageToken (the
+// first page if it's empty).
+func (c *client) listModulesPage(ctx context.Context, owner, pageToken string) (*connect.Response[modulev1.ListModulesResponse], error) {
+	return c.moduleServiceClient.ListModules(ctx, connect.NewRequest(&modulev1.ListModulesRequest{
+		PageSize:  pageSize,
+		PageToken: pageToken,
+		OwnerRefs: []*ownerv1.OwnerRef{
+			{
+				Value: &ownerv1.OwnerRef_Name{
+					Name: owner,
+				},
+			},
+		},
+	}))
+}
+
 type commitsMsg struct {
 	commits       []*modulev1.Commit
 	nextPageToken string
@@ -161,18 +237,7 @@ func (c *client) listCommits(currentOwner, currentModule string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
 		defer cancel()
-		request := connect.NewRequest(&modulev1.ListCommitsRequest{
-			PageSize: pageSize,
-			ResourceRef: &modulev1.ResourceRef{
-				Value: &modulev1.ResourceRef_Name_{
-					Name: &modulev1.ResourceRef_Name{
-						Owner:  currentOwner,
-						Module: currentModule,
-					},
-				},
-			},
-		})
-		response, err := c.commitServiceClient.ListCommits(ctx, request)
+		response, err := c.listCommitsPage(ctx, currentOwner, currentModule, "")
 		if err != nil {
 			return errMsg{fmt.Errorf("getting commits: %w", err)}
 		}
@@ -187,19 +252,7 @@ func (c *client) listMoreCommits(currentOwner, currentModule, pageToken string)
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
 		defer cancel()
-		request := connect.NewRequest(&modulev1.ListCommitsRequest{
-			PageSize:  pageSize,
-			PageToken: pageToken,
-			ResourceRef: &modulev1.ResourceRef{
-				Value: &modulev1.ResourceRef_Name_{
-					Name: &modulev1.ResourceRef_Name{
-						Owner:  currentOwner,
-						Module: currentModule,
-					},
-				},
-			},
-		})
-		response, err := c.commitServiceClient.ListCommits(ctx, request)
+		response, err := c.listCommitsPage(ctx, currentOwner, currentModule, pageToken)
 		if err != nil {
 			return errMsg{fmt.Errorf("getting more commits: %w", err)}
 		}
@@ -210,6 +263,23 @@ func (c *client) listMoreCommits(currentOwner, currentModule, pageToken string)
 	}
 }
 
+// listCommitsPage lists the page of a module's commits at pageToken (the
+// first page if it's empty), newest first.
+func (c *client) listCommitsPage(ctx context.Context, owner, module, pageToken string) (*connect.Response[modulev1.ListCommitsResponse], error) {
+	return c.commitServiceClient.ListCommits(ctx, connect.NewRequest(&modulev1.ListCommitsRequest{
+		PageSize:  pageSize,
+		PageToken: pageToken,
+		ResourceRef: &modulev1.ResourceRef{
+			Value: &modulev1.ResourceRef_Name_{
+				Name: &modulev1.ResourceRef_Name{
+					Owner:  owner,
+					Module: module,
+				},
+			},
+		},
+	}))
+}
+
 type contentsMsg *modulev1.DownloadResponse_Content
 
 func (c *client) getCommitContent(commitID string) tea.Cmd {
@@ -272,41 +342,208 @@ func (c *client) getResource(resourceName *modulev1.ResourceRef_Name) tea.Cmd {
 	}
 }
 
-func (c *client) listLabels(owner, module string) tea.Cmd {
+// resolveCommit returns the commit a reference resolves to: the commit
+// itself, the one a label points to, or the one the module's default label
+// points to.
+func (c *client) resolveCommit(ctx context.Context, reference *modulev1.ResourceRef_Name) (*modulev1.Commit, error) {
+	response, err := c.commitServiceClient.GetCommits(ctx, connect.NewRequest(&modulev1.GetCommitsRequest{
+		ResourceRefs: []*modulev1.ResourceRef{{
+			Value: &modulev1.ResourceRef_Name_{Name: reference},
+		}},
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("resolving commit: %w", err)
+	}
+	if len(response.Msg.Commits) != 1 {
+		return nil, fmt.Errorf("requested 1 commit, got %v", len(response.Msg.Commits))
+	}
+	return response.Msg.Commits[0], nil
+}
+
+func (c *client) listLabels(owner, module string, archiveFilter labelArchiveFilter) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
 		defer cancel()
-		var allLabels []*modulev1.Label
-		pageToken := ""
-		for {
-			request := connect.NewRequest(&modulev1.ListLabelsRequest{
-				PageSize:  pageSize,
-				PageToken: pageToken,
-				ResourceRef: &modulev1.ResourceRef{
-					Value: &modulev1.ResourceRef_Name_{
-						Name: &modulev1.ResourceRef_Name{
-							Owner:  owner,
-							Module: module,
-						},
+		labels, err := c.allLabels(ctx, owner, module, archiveFilter)
+		if err != nil {
+			return errMsg{err}
+		}
+		return labelsMsg{labels: labels, archiveFilter: archiveFilter}
+	}
+}
+
+// allLabels lists every one of a module's labels matching archiveFilter,
+// following pagination.
+func (c *client) allLabels(ctx context.Context, owner, module string, archiveFilter labelArchiveFilter) ([]*modulev1.Label, error) {
+	var allLabels []*modulev1.Label
+	pageToken := ""
+	for {
+		request := connect.NewRequest(&modulev1.ListLabelsRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+			ResourceRef: &modulev1.ResourceRef{
+				Value: &modulev1.ResourceRef_Name_{
+					Name: &modulev1.ResourceRef_Name{
+						Owner:  owner,
+						Module: module,
 					},
 				},
-				ArchiveFilter: modulev1.ListLabelsRequest_ARCHIVE_FILTER_UNARCHIVED_ONLY,
-			})
-			response, err := c.labelServiceClient.ListLabels(ctx, request)
-			if err != nil {
-				return errMsg{fmt.Errorf("listing labels: %w", err)}
-			}
-			allLabels = append(allLabels, response.Msg.Labels...)
-			if response.Msg.NextPageToken == "" {
-				break
-			}
-			pageToken = response.Msg.NextPageToken
+			},
+			ArchiveFilter: archiveFilter.proto(),
+		})
+		response, err := c.labelServiceClient.ListLabels(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("listing labels: %w", err)
 		}
-		return labelsMsg(allLabels)
+		allLabels = append(allLabels, response.Msg.Labels...)
+		if response.Msg.NextPageToken == "" {
+			return allLabels, nil
+		}
+		pageToken = response.Msg.NextPageToken
 	}
 }
 
-func (c *client) fetchLabelSuggestions(owner, module string) tea.Cmd {
+// commitDetailsMsg carries what the commit header shows beyond the commit
+// itself: who pushed it, and which labels point at it now.
+type commitDetailsMsg struct {
+	commitID string
+	// author is the name of the user who pushed the commit, if known.
+	author string
+	labels []string
+}
+
+type commitDetailsErrMsg struct {
+	commitID string
+	err      error
+}
+
+func (e commitDetailsErrMsg) Error() string { return e.err.Error() }
+
+func (c *client) getCommitDetails(commit *modulev1.Commit) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		// Listing a commit's labels returns the ones pointing at it.
+		response, err := c.labelServiceClient.ListLabels(ctx, connect.NewRequest(&modulev1.ListLabelsRequest{
+			PageSize: pageSize,
+			ResourceRef: &modulev1.ResourceRef{
+				Value: &modulev1.ResourceRef_Id{Id: commit.Id},
+			},
+			ArchiveFilter: modulev1.ListLabelsRequest_ARCHIVE_FILTER_UNARCHIVED_ONLY,
+		}))
+		if err != nil {
+			return commitDetailsErrMsg{commit.Id, fmt.Errorf("listing commit labels: %w", err)}
+		}
+		labels := make([]string, len(response.Msg.Labels))
+		for i, label := range response.Msg.Labels {
+			labels[i] = label.Name
+		}
+		slices.Sort(labels)
+		// As with label history, the author's name is a nicety.
+		authors, _ := c.resolveUserNames(ctx, uniqueUserIDs([]*modulev1.Commit{commit}))
+		return commitDetailsMsg{
+			commitID: commit.Id,
+			author:   authors[commit.CreatedByUserId],
+			labels:   labels,
+		}
+	}
+}
+
+// labelHistoryMsg is a page of a label's history: the commits it has
+// pointed to, newest first, along with the names of the users who pushed
+// them (by user ID).
+type labelHistoryMsg struct {
+	label         string
+	commits       []*modulev1.Commit
+	authors       map[string]string
+	nextPageToken string
+}
+
+type moreLabelHistoryMsg labelHistoryMsg
+
+// listLabelHistory fetches a page of label's history, starting from
+// pageToken (empty for the first page, which comes back as a
+// labelHistoryMsg; later ones come back as a moreLabelHistoryMsg).
+func (c *client) listLabelHistory(owner, module, label, pageToken string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		request := connect.NewRequest(&modulev1.ListLabelHistoryRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+			LabelRef: &modulev1.LabelRef{
+				Value: &modulev1.LabelRef_Name_{
+					Name: &modulev1.LabelRef_Name{
+						Owner:  owner,
+						Module: module,
+						Label:  label,
+					},
+				},
+			},
+		})
+		response, err := c.labelServiceClient.ListLabelHistory(ctx, request)
+		if err != nil {
+			return errMsg{fmt.Errorf("listing label history: %w", err)}
+		}
+		commits := make([]*modulev1.Commit, len(response.Msg.Values))
+		for i, value := range response.Msg.Values {
+			commits[i] = value.Commit
+		}
+		// Author names are a nicety; the history is still useful without
+		// them, so a failure to resolve them isn't fatal.
+		authors, _ := c.resolveUserNames(ctx, uniqueUserIDs(commits))
+		msg := labelHistoryMsg{
+			label:         label,
+			commits:       commits,
+			authors:       authors,
+			nextPageToken: response.Msg.NextPageToken,
+		}
+		if pageToken != "" {
+			return moreLabelHistoryMsg(msg)
+		}
+		return msg
+	}
+}
+
+// resolveUserNames resolves user IDs (e.g. a commit's CreatedByUserId) to
+// user names in one batched call.
+func (c *client) resolveUserNames(ctx context.Context, userIDs []string) (map[string]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	ownerRefs := make([]*ownerv1.OwnerRef, len(userIDs))
+	for i, id := range userIDs {
+		ownerRefs[i] = &ownerv1.OwnerRef{Value: &ownerv1.OwnerRef_Id{Id: id}}
+	}
+	response, err := c.ownerServiceClient.GetOwners(ctx, connect.NewRequest(&ownerv1.GetOwnersRequest{
+		OwnerRefs: ownerRefs,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("resolving users: %w", err)
+	}
+	names := make(map[string]string, len(response.Msg.Owners))
+	for _, owner := range response.Msg.Owners {
+		names[ownerIDOf(owner)] = ownerName(owner)
+	}
+	return names, nil
+}
+
+// uniqueUserIDs returns the distinct, non-empty created_by_user_ids of
+// commits, in first-seen order. A commit's user ID is empty if the user who
+// pushed it no longer exists.
+func uniqueUserIDs(commits []*modulev1.Commit) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, c := range commits {
+		if c.CreatedByUserId != "" && !seen[c.CreatedByUserId] {
+			seen[c.CreatedByUserId] = true
+			ids = append(ids, c.CreatedByUserId)
+		}
+	}
+	return ids
+}
+
+func (c *client) fetchLabelSuggestions(owner, module string, archiveFilter labelArchiveFilter) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
 		defer cancel()
@@ -320,7 +557,7 @@ func (c *client) fetchLabelSuggestions(owner, module string) tea.Cmd {
 					},
 				},
 			},
-			ArchiveFilter: modulev1.ListLabelsRequest_ARCHIVE_FILTER_UNARCHIVED_ONLY,
+			ArchiveFilter: archiveFilter.proto(),
 		})
 		response, err := c.labelServiceClient.ListLabels(ctx, request)
 		if err != nil {
@@ -367,121 +604,140 @@ func (c *client) fetchModuleSuggestions(owner string) tea.Cmd {
 // it running in the background for the rest of compileDocsTimeout regardless.
 func (c *client) compileDocs(ctx context.Context, commitID string, currentFiles []*modulev1.File) tea.Cmd {
 	return func() tea.Msg {
-		c.docsCacheMu.Lock()
-		cached, ok := c.docsCache[commitID]
-		c.docsCacheMu.Unlock()
-		if ok {
-			return docsMsg(cached)
-		}
-
-		// 1. Get the full transitive dependency graph.
-		graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
-			ResourceRefs: []*modulev1.ResourceRef{{
-				Value: &modulev1.ResourceRef_Id{Id: commitID},
-			}},
-		}))
+		entry, err := c.compiledDocs(ctx, commitID, currentFiles)
 		if err != nil {
-			return docsErrMsg{fmt.Errorf("getting dependency graph: %w", err)}
+			return docsErrMsg{err}
 		}
+		return docsMsg(entry)
+	}
+}
 
-		// 2. Collect dep commit IDs (everything in the graph except the current commit).
-		var depCommitIDs []string
-		for _, commit := range graphResp.Msg.Graph.Commits {
-			if commit.Id != commitID {
-				depCommitIDs = append(depCommitIDs, commit.Id)
-			}
-		}
+// compiledDocs is compileDocs' pipeline, for callers that want the result
+// rather than a message: commitID's files compiled against its
+// dependencies, from docsCache if they've been compiled before.
+func (c *client) compiledDocs(ctx context.Context, commitID string, currentFiles []*modulev1.File) (docsCacheEntry, error) {
+	c.docsCacheMu.Lock()
+	cached, ok := c.docsCache[commitID]
+	c.docsCacheMu.Unlock()
+	if ok {
+		return cached, nil
+	}
 
-		// 3. Seed the source map from the current module's proto files.
-		fileMap := source.NewMap(nil)
-		for _, f := range currentFiles {
-			if strings.HasSuffix(f.Path, ".proto") {
-				fileMap.Add(f.Path, string(f.Content))
-			}
-		}
+	// 1. Get the full transitive dependency graph.
+	graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
+		ResourceRefs: []*modulev1.ResourceRef{{
+			Value: &modulev1.ResourceRef_Id{Id: commitID},
+		}},
+	}))
+	if err != nil {
+		return docsCacheEntry{}, fmt.Errorf("getting dependency graph: %w", err)
+	}
 
-		// 4. Batch-download all dep proto files in a single request.
-		if len(depCommitIDs) > 0 {
-			values := make([]*modulev1.DownloadRequest_Value, len(depCommitIDs))
-			for i, id := range depCommitIDs {
-				values[i] = &modulev1.DownloadRequest_Value{
-					ResourceRef: &modulev1.ResourceRef{
-						Value: &modulev1.ResourceRef_Id{Id: id},
-					},
-					FileTypes: []modulev1.FileType{modulev1.FileType_FILE_TYPE_PROTO},
-				}
-			}
-			dlResp, err := c.downloadServiceClient.Download(ctx, connect.NewRequest(&modulev1.DownloadRequest{
-				Values: values,
-			}))
-			if err != nil {
-				return docsErrMsg{fmt.Errorf("downloading dependencies: %w", err)}
-			}
-			for _, content := range dlResp.Msg.Contents {
-				for _, f := range content.Files {
-					if strings.HasSuffix(f.Path, ".proto") {
-						fileMap.Add(f.Path, string(f.Content))
-					}
-				}
-			}
+	// 2. Collect dep commit IDs (everything in the graph except the current commit).
+	var depCommitIDs []string
+	for _, commit := range graphResp.Msg.Graph.Commits {
+		if commit.Id != commitID {
+			depCommitIDs = append(depCommitIDs, commit.Id)
 		}
+	}
 
-		// 5. Build the opener: WKTs first, then module files.
-		opener := &source.Openers{source.WKTs(), fileMap}
-
-		// 6. Compile main module proto files using the experimental incremental compiler.
-		session := &ir.Session{}
-		executor := incremental.New()
-		irQueries := make([]incremental.Query[*ir.File], 0, len(currentFiles))
-		for _, f := range currentFiles {
-			if strings.HasSuffix(f.Path, ".proto") {
-				irQueries = append(irQueries, queries.IR{
-					Opener:  opener,
-					Session: session,
-					Path:    f.Path,
-				})
+	// 3. Batch-download all dep proto files in a single request.
+	var depFiles []*modulev1.File
+	if len(depCommitIDs) > 0 {
+		values := make([]*modulev1.DownloadRequest_Value, len(depCommitIDs))
+		for i, id := range depCommitIDs {
+			values[i] = &modulev1.DownloadRequest_Value{
+				ResourceRef: &modulev1.ResourceRef{
+					Value: &modulev1.ResourceRef_Id{Id: id},
+				},
+				FileTypes: []modulev1.FileType{modulev1.FileType_FILE_TYPE_PROTO},
 			}
 		}
-		irResults, _, err := incremental.Run(ctx, executor, irQueries...)
+		dlResp, err := c.downloadServiceClient.Download(ctx, connect.NewRequest(&modulev1.DownloadRequest{
+			Values: values,
+		}))
 		if err != nil {
-			return docsErrMsg{fmt.Errorf("compiling protos: %w", err)}
+			return docsCacheEntry{}, fmt.Errorf("downloading dependencies: %w", err)
 		}
-		irFiles := make([]*ir.File, 0, len(irResults))
-		for _, r := range irResults {
-			if r.Fatal != nil {
-				return docsErrMsg{fmt.Errorf("compiling protos: %w", r.Fatal)}
-			}
-			irFiles = append(irFiles, r.Value)
+		for _, content := range dlResp.Msg.Contents {
+			depFiles = append(depFiles, content.Files...)
 		}
+	}
 
-		// 7. Convert IR files to a FileDescriptorSet (includes all deps except WKTs),
-		// with source code info for comments.
-		fdsBytes, err := fdp.DescriptorSetBytes(irFiles, fdp.IncludeSourceCodeInfo(true))
-		if err != nil {
-			return docsErrMsg{fmt.Errorf("generating file descriptors: %w", err)}
+	// 4. Compile.
+	entry, err := compileFiles(ctx, currentFiles, depFiles)
+	if err != nil {
+		return docsCacheEntry{}, err
+	}
+
+	c.docsCacheMu.Lock()
+	if c.docsCache == nil {
+		c.docsCache = make(map[string]docsCacheEntry)
+	}
+	if len(c.docsCache) >= docsCacheMaxEntries {
+		for k := range c.docsCache {
+			delete(c.docsCache, k)
+			break
 		}
-		// 8. Build a registry, re-resolving custom options against the
-		// descriptor set's own extension declarations along the way.
-		regFiles, skipped, err := resolveRegistry(fdsBytes)
-		if err != nil {
-			return docsErrMsg{err}
+	}
+	c.docsCache[commitID] = entry
+	c.docsCacheMu.Unlock()
+
+	return entry, nil
+}
+
+// compileFiles compiles currentFiles' proto files against depFiles', the
+// second half of compiledDocs' pipeline, for callers that have the files
+// already.
+func compileFiles(ctx context.Context, currentFiles, depFiles []*modulev1.File) (docsCacheEntry, error) {
+	fileMap := source.NewMap(nil)
+	for _, f := range slices.Concat(currentFiles, depFiles) {
+		if strings.HasSuffix(f.Path, ".proto") {
+			fileMap.Add(f.Path, string(f.Content))
 		}
+	}
 
-		c.docsCacheMu.Lock()
-		if c.docsCache == nil {
-			c.docsCache = make(map[string]docsCacheEntry)
+	// Build the opener: WKTs first, then module files.
+	opener := &source.Openers{source.WKTs(), fileMap}
+
+	// Compile main module proto files using the experimental incremental compiler.
+	session := &ir.Session{}
+	executor := incremental.New()
+	irQueries := make([]incremental.Query[*ir.File], 0, len(currentFiles))
+	for _, f := range currentFiles {
+		if strings.HasSuffix(f.Path, ".proto") {
+			irQueries = append(irQueries, queries.IR{
+				Opener:  opener,
+				Session: session,
+				Path:    f.Path,
+			})
 		}
-		if len(c.docsCache) >= docsCacheMaxEntries {
-			for k := range c.docsCache {
-				delete(c.docsCache, k)
-				break
-			}
+	}
+	irResults, _, err := incremental.Run(ctx, executor, irQueries...)
+	if err != nil {
+		return docsCacheEntry{}, fmt.Errorf("compiling protos: %w", err)
+	}
+	irFiles := make([]*ir.File, 0, len(irResults))
+	for _, r := range irResults {
+		if r.Fatal != nil {
+			return docsCacheEntry{}, fmt.Errorf("compiling protos: %w", r.Fatal)
 		}
-		c.docsCache[commitID] = docsCacheEntry{files: regFiles, skipped: skipped}
-		c.docsCacheMu.Unlock()
+		irFiles = append(irFiles, r.Value)
+	}
 
-		return docsMsg{files: regFiles, skipped: skipped}
+	// Convert IR files to a FileDescriptorSet (includes all deps except WKTs),
+	// with source code info for comments.
+	fdsBytes, err := fdp.DescriptorSetBytes(irFiles, fdp.IncludeSourceCodeInfo(true))
+	if err != nil {
+		return docsCacheEntry{}, fmt.Errorf("generating file descriptors: %w", err)
+	}
+	// Build a registry, re-resolving custom options against the
+	// descriptor set's own extension declarations along the way.
+	regFiles, skipped, err := resolveRegistry(fdsBytes)
+	if err != nil {
+		return docsCacheEntry{}, err
 	}
+	return docsCacheEntry{files: regFiles, skipped: skipped, descriptorSet: fdsBytes}, nil
 }
 
 // resolveRegistry builds a *protoregistry.Files from a marshaled
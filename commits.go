@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+)
+
+// Filtering the commit list with a word starting with one of these prefixes
+// matches on the commit's author or labels rather than fuzzily on the whole
+// row, e.g. "@alice" or "#v2".
+const (
+	commitFilterAuthorPrefix = "@"
+	commitFilterLabelPrefix  = "#"
+)
+
+// commitAnnotationsMsg carries the author names and labels the commit list
+// shows alongside each commit.
+type commitAnnotationsMsg struct {
+	owner  string
+	module string
+	// authors maps user IDs to user names.
+	authors map[string]string
+	// labels maps commit IDs to the names of the labels pointing at them.
+	// It's nil if the labels weren't fetched, as opposed to there being none.
+	labels map[string][]string
+}
+
+// annotateCommits resolves who pushed commits and, if withLabels is set,
+// which of the module's labels point at which commits. Labels are listed
+// for the whole module in one go, so later pages of commits only need their
+// authors resolving.
+//
+// Both are niceties -- the commit list is usable without them -- so
+// failures leave the rows unannotated rather than surfacing an error.
+func (c *client) annotateCommits(owner, module string, commits []*modulev1.Commit, withLabels bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		msg := commitAnnotationsMsg{owner: owner, module: module}
+		msg.authors, _ = c.resolveUserNames(ctx, uniqueUserIDs(commits))
+		if withLabels {
+			if labels, err := c.allLabels(ctx, owner, module, labelArchiveFilterUnarchived); err == nil {
+				msg.labels = labelsByCommit(labels)
+			}
+		}
+		return msg
+	}
+}
+
+// labelsByCommit groups label names by the commit they point at, each
+// commit's labels sorted by name.
+func labelsByCommit(labels []*modulev1.Label) map[string][]string {
+	byCommit := make(map[string][]string)
+	for _, label := range labels {
+		byCommit[label.CommitId] = append(byCommit[label.CommitId], label.Name)
+	}
+	for _, names := range byCommit {
+		slices.Sort(names)
+	}
+	return byCommit
+}
+
+// commitFilter filters the commit list. Words prefixed with
+// [commitFilterAuthorPrefix] or [commitFilterLabelPrefix] must match the
+// start of the commit's author or one of its labels (case-insensitively);
+// any other words are fuzzy-matched as the list normally would.
+func commitFilter(term string, targets []string) []list.Rank {
+	var qualifiers, rest []string
+	for word := range strings.FieldsSeq(strings.ToLower(term)) {
+		if word != commitFilterAuthorPrefix && word != commitFilterLabelPrefix &&
+			(strings.HasPrefix(word, commitFilterAuthorPrefix) || strings.HasPrefix(word, commitFilterLabelPrefix)) {
+			qualifiers = append(qualifiers, word)
+		} else {
+			rest = append(rest, word)
+		}
+	}
+	if len(qualifiers) == 0 {
+		return trimMatchesToTitle(list.DefaultFilter(term, targets), targets)
+	}
+	var indexes []int
+	var candidates []string
+	for i, target := range targets {
+		words := strings.Fields(strings.ToLower(target))
+		if !containsAll(words, qualifiers) {
+			continue
+		}
+		indexes = append(indexes, i)
+		candidates = append(candidates, target)
+	}
+	if len(rest) == 0 {
+		ranks := make([]list.Rank, len(indexes))
+		for i, index := range indexes {
+			ranks[i] = list.Rank{Index: index}
+		}
+		return ranks
+	}
+	ranks := trimMatchesToTitle(list.DefaultFilter(strings.Join(rest, " "), candidates), candidates)
+	for i := range ranks {
+		ranks[i].Index = indexes[ranks[i].Index]
+	}
+	return ranks
+}
+
+// containsAll reports whether every qualifier is a prefix of one of words.
+func containsAll(words, qualifiers []string) bool {
+	for _, qualifier := range qualifiers {
+		if !slices.ContainsFunc(words, func(word string) bool {
+			return strings.HasPrefix(word, qualifier)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// trimMatchesToTitle drops matched characters past a commit's ID from
+// ranks. The delegate highlights matches in the row's title, which only
+// lines up with the filter value up to the end of the ID.
+func trimMatchesToTitle(ranks []list.Rank, targets []string) []list.Rank {
+	for i, rank := range ranks {
+		idLength := len(targets[rank.Index])
+		if space := strings.IndexByte(targets[rank.Index], ' '); space >= 0 {
+			idLength = space
+		}
+		ranks[i].MatchedIndexes = slices.DeleteFunc(rank.MatchedIndexes, func(index int) bool {
+			return index >= idLength
+		})
+	}
+	return ranks
+}
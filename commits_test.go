@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"go.vanburen.xyz/ok"
+)
+
+func TestCommitFilter(t *testing.T) {
+	t.Parallel()
+
+	commits := []*commit{
+		{underlying: &modulev1.Commit{Id: "aaa111"}, author: "alice", labels: []string{"main", "v2.0.0"}},
+		{underlying: &modulev1.Commit{Id: "bbb222"}, author: "bob", labels: []string{"v1.4.0"}},
+		{underlying: &modulev1.Commit{Id: "ccc333"}},
+	}
+	targets := make([]string, len(commits))
+	for i, c := range commits {
+		targets[i] = c.FilterValue()
+	}
+	matches := func(term string) []string {
+		var ids []string
+		for _, rank := range commitFilter(term, targets) {
+			ids = append(ids, commits[rank.Index].underlying.Id)
+		}
+		return ids
+	}
+
+	ok.DeepEqual(t, matches("@bob"), []string{"bbb222"})
+	ok.DeepEqual(t, matches("#v2"), []string{"aaa111"})
+	ok.DeepEqual(t, matches("#V"), []string{"aaa111", "bbb222"}, ok.Sprintf("qualifiers are case-insensitive"))
+	ok.DeepEqual(t, matches("@alice #v1"), []string(nil), ok.Sprintf("every qualifier must match"))
+	ok.DeepEqual(t, matches("@b 222"), []string{"bbb222"}, ok.Sprintf("other words are fuzzy-matched"))
+	ok.DeepEqual(t, matches("ccc"), []string{"ccc333"})
+	ok.DeepEqual(t, matches("#main"), []string{"aaa111"})
+}
+
+func TestCommitFilter_HighlightsOnlyTheID(t *testing.T) {
+	t.Parallel()
+
+	target := (&commit{underlying: &modulev1.Commit{Id: "abc"}, author: "abe"}).FilterValue()
+	ranks := commitFilter("abe", []string{target})
+	ok.Equal(t, len(ranks), 1)
+	for _, index := range ranks[0].MatchedIndexes {
+		ok.True(t, index < len("abc"), ok.Sprintf("matched index %d is past the ID", index))
+	}
+}
+
+func TestLabelsByCommit(t *testing.T) {
+	t.Parallel()
+
+	got := labelsByCommit([]*modulev1.Label{
+		{Name: "v2", CommitId: "c1"},
+		{Name: "main", CommitId: "c1"},
+		{Name: "v1", CommitId: "c2"},
+	})
+	ok.DeepEqual(t, got, map[string][]string{"c1": {"main", "v2"}, "c2": {"v1"}})
+}
@@ -42,45 +42,12 @@ func (c *client) getDeps(commitID, remote string) tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
 		defer cancel()
 
-		graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
-			ResourceRefs: []*modulev1.ResourceRef{{
-				Value: &modulev1.ResourceRef_Id{Id: commitID},
-			}},
-		}))
+		deps, err := c.fetchDepGraph(ctx, commitID)
 		if err != nil {
-			return depsErrMsg{fmt.Errorf("getting dependency graph: %w", err)}
+			return depsErrMsg{err}
 		}
-		graph := graphResp.Msg.Graph
-
-		// Resolve every commit's module_id to a Module (id, name, owner_id) in
-		// one batched call -- not once per commit.
-		moduleIDs := uniqueModuleIDs(graph.Commits)
-		moduleRefs := make([]*modulev1.ModuleRef, len(moduleIDs))
-		for i, id := range moduleIDs {
-			moduleRefs[i] = &modulev1.ModuleRef{Value: &modulev1.ModuleRef_Id{Id: id}}
-		}
-		modulesResp, err := c.moduleServiceClient.GetModules(ctx, connect.NewRequest(&modulev1.GetModulesRequest{
-			ModuleRefs: moduleRefs,
-		}))
-		if err != nil {
-			return depsErrMsg{fmt.Errorf("resolving dependency modules: %w", err)}
-		}
-
-		// Resolve every module's owner_id to an Owner (User or Organization)
-		// name, again in one batched call.
-		ownerIDs := uniqueOwnerIDs(modulesResp.Msg.Modules)
-		ownerRefs := make([]*ownerv1.OwnerRef, len(ownerIDs))
-		for i, id := range ownerIDs {
-			ownerRefs[i] = &ownerv1.OwnerRef{Value: &ownerv1.OwnerRef_Id{Id: id}}
-		}
-		ownersResp, err := c.ownerServiceClient.GetOwners(ctx, connect.NewRequest(&ownerv1.GetOwnersRequest{
-			OwnerRefs: ownerRefs,
-		}))
-		if err != nil {
-			return depsErrMsg{fmt.Errorf("resolving dependency owners: %w", err)}
-		}
-
-		nodes := commitDepNodes(remote, graph.Commits, modulesResp.Msg.Modules, ownersResp.Msg.Owners)
+		graph := deps.graph
+		nodes := commitDepNodes(remote, graph.Commits, deps.modules, deps.owners)
 		return depsMsg{
 			root:  depsTree(commitID, graph.Edges, nodes),
 			count: reachableDepCount(commitID, graph.Edges),
@@ -88,6 +55,62 @@ func (c *client) getDeps(commitID, remote string) tea.Cmd {
 	}
 }
 
+// depGraph is a commit's full transitive dependency graph, along with the
+// Modules and Owners its commits belong to.
+type depGraph struct {
+	graph   *modulev1.Graph
+	modules []*modulev1.Module
+	owners  []*ownerv1.Owner
+}
+
+// fetchDepGraph fetches the dependency graph for commitID and resolves the
+// module and owner of every commit in it, in three RPCs total.
+func (c *client) fetchDepGraph(ctx context.Context, commitID string) (depGraph, error) {
+	graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
+		ResourceRefs: []*modulev1.ResourceRef{{
+			Value: &modulev1.ResourceRef_Id{Id: commitID},
+		}},
+	}))
+	if err != nil {
+		return depGraph{}, fmt.Errorf("getting dependency graph: %w", err)
+	}
+	graph := graphResp.Msg.Graph
+
+	// Resolve every commit's module_id to a Module (id, name, owner_id) in
+	// one batched call -- not once per commit.
+	moduleIDs := uniqueModuleIDs(graph.Commits)
+	moduleRefs := make([]*modulev1.ModuleRef, len(moduleIDs))
+	for i, id := range moduleIDs {
+		moduleRefs[i] = &modulev1.ModuleRef{Value: &modulev1.ModuleRef_Id{Id: id}}
+	}
+	modulesResp, err := c.moduleServiceClient.GetModules(ctx, connect.NewRequest(&modulev1.GetModulesRequest{
+		ModuleRefs: moduleRefs,
+	}))
+	if err != nil {
+		return depGraph{}, fmt.Errorf("resolving dependency modules: %w", err)
+	}
+
+	// Resolve every module's owner_id to an Owner (User or Organization)
+	// name, again in one batched call.
+	ownerIDs := uniqueOwnerIDs(modulesResp.Msg.Modules)
+	ownerRefs := make([]*ownerv1.OwnerRef, len(ownerIDs))
+	for i, id := range ownerIDs {
+		ownerRefs[i] = &ownerv1.OwnerRef{Value: &ownerv1.OwnerRef_Id{Id: id}}
+	}
+	ownersResp, err := c.ownerServiceClient.GetOwners(ctx, connect.NewRequest(&ownerv1.GetOwnersRequest{
+		OwnerRefs: ownerRefs,
+	}))
+	if err != nil {
+		return depGraph{}, fmt.Errorf("resolving dependency owners: %w", err)
+	}
+
+	return depGraph{
+		graph:   graph,
+		modules: modulesResp.Msg.Modules,
+		owners:  ownersResp.Msg.Owners,
+	}, nil
+}
+
 // uniqueModuleIDs returns the distinct module_ids referenced by commits, in
 // first-seen order (for deterministic request ordering, easier to debug than
 // a random map iteration order).
@@ -136,6 +159,11 @@ func ownerName(o *ownerv1.Owner) string {
 type depNode struct {
 	label string
 	href  string
+	// owner, module and commit identify the dependency for the yank menu,
+	// when its module could be resolved.
+	owner  string
+	module string
+	commit *modulev1.Commit
 }
 
 // String renders the node as it appears in the tree: the label, hyperlinked
@@ -172,8 +200,11 @@ func commitDepNodes(remote string, commits []*modulev1.Commit, modules []*module
 			ref = ref[:12]
 		}
 		nodes[c.Id] = depNode{
-			label: fmt.Sprintf("%s/%s@%s", owner, module.Name, ref),
-			href:  fmt.Sprintf("https://%s/%s/%s/commits/%s", remote, owner, module.Name, c.Id),
+			label:  fmt.Sprintf("%s/%s@%s", owner, module.Name, ref),
+			href:   fmt.Sprintf("https://%s/%s/%s/commits/%s", remote, owner, module.Name, c.Id),
+			owner:  owner,
+			module: module.Name,
+			commit: c,
 		}
 	}
 	return nodes
@@ -267,9 +298,9 @@ func reachableDepCount(rootCommitID string, edges []*modulev1.Graph_Edge) int {
 	return len(seen)
 }
 
-// selectedDepTreeNode returns the tree node under the cursor, or nil if the
+// selectedTreeNode returns the tree node under the cursor, or nil if the
 // tree is empty.
-func selectedDepTreeNode(model tree.Model) *tree.Node {
+func selectedTreeNode(model tree.Model) *tree.Node {
 	for _, node := range model.AllNodes() {
 		if node.IsSelected() {
 			return node
@@ -281,7 +312,7 @@ func selectedDepTreeNode(model tree.Model) *tree.Node {
 // selectedDepNode returns the depNode under the cursor in the deps tree, and
 // whether one was found.
 func selectedDepNode(model tree.Model) (depNode, bool) {
-	node := selectedDepTreeNode(model)
+	node := selectedTreeNode(model)
 	if node == nil {
 		return depNode{}, false
 	}
@@ -289,9 +320,9 @@ func selectedDepNode(model tree.Model) (depNode, bool) {
 	return dep, ok
 }
 
-// depsTreeStyles returns the tree styles for the deps tab: the default
-// enumerator/indenter guides, with the root and the node under the cursor
-// picked out in the app's accent color.
+// depsTreeStyles returns the tree styles for the deps tab, which the Files
+// tab's tree shares: the default enumerator/indenter guides, with the root
+// and the node under the cursor picked out in the app's accent color.
 func depsTreeStyles(isDark bool) tree.Styles {
 	styles := tree.DefaultStyles(isDark)
 	// Leave dependency labels in the terminal's default foreground -- most
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/httplb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// descriptorSetFormat is an encoding of a FileDescriptorSet.
+type descriptorSetFormat string
+
+const (
+	descriptorSetBinary descriptorSetFormat = "binary"
+	descriptorSetJSON   descriptorSetFormat = "json"
+	descriptorSetText   descriptorSetFormat = "text"
+)
+
+// descriptorSetFormats lists the formats in the order the Docs tab's write
+// input cycles through them.
+var descriptorSetFormats = []descriptorSetFormat{descriptorSetBinary, descriptorSetJSON, descriptorSetText}
+
+// fileExtension returns the conventional file extension for a descriptor
+// set in this format, as buf names them.
+func (f descriptorSetFormat) fileExtension() string {
+	switch f {
+	case descriptorSetJSON:
+		return ".json"
+	case descriptorSetText:
+		return ".txtpb"
+	}
+	return ".binpb"
+}
+
+// descriptorSetFormatOf returns the format a descriptor set written to path
+// should be in, going by its extension, or false if it isn't one buf would
+// recognize.
+func descriptorSetFormatOf(path string) (descriptorSetFormat, bool) {
+	switch filepath.Ext(path) {
+	case ".binpb", ".pb", ".bin":
+		return descriptorSetBinary, true
+	case ".json":
+		return descriptorSetJSON, true
+	case ".txtpb", ".textproto", ".txt":
+		return descriptorSetText, true
+	}
+	return "", false
+}
+
+// descriptorSetOptions is what to leave out of a written descriptor set,
+// and how to encode it.
+type descriptorSetOptions struct {
+	format            descriptorSetFormat
+	excludeSourceInfo bool
+	excludeImports    bool
+}
+
+// buildDescriptorSet re-encodes a compiled commit's descriptor set (see
+// compiledDocs) for tools like Envoy's gRPC-JSON transcoder: ownPaths are
+// the commit's own files, and unless opts.excludeImports every file they
+// import is kept too, adding any well-known types the set doesn't carry so
+// it's self-contained. It's decoded against its own types
+// first, as resolveRegistry does, so custom options survive into JSON and
+// text instead of vanishing as unknown fields.
+func buildDescriptorSet(entry docsCacheEntry, ownPaths map[string]bool, opts descriptorSetOptions) ([]byte, error) {
+	types := dynamicpb.NewTypes(entry.files)
+	var fds descriptorpb.FileDescriptorSet
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(entry.descriptorSet, &fds); err != nil {
+		return nil, fmt.Errorf("unmarshalling file descriptors: %w", err)
+	}
+	if opts.excludeImports {
+		fds.File = slices.DeleteFunc(fds.File, func(file *descriptorpb.FileDescriptorProto) bool {
+			return !ownPaths[file.GetName()]
+		})
+	} else if err := addWellKnownImports(&fds); err != nil {
+		return nil, err
+	}
+	if opts.excludeSourceInfo {
+		for _, file := range fds.File {
+			file.SourceCodeInfo = nil
+		}
+	}
+
+	switch opts.format {
+	case descriptorSetBinary:
+		return proto.MarshalOptions{Deterministic: true}.Marshal(&fds)
+	case descriptorSetJSON:
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", Resolver: types}.Marshal(&fds)
+		return append(data, '\n'), err
+	case descriptorSetText:
+		return prototext.MarshalOptions{Multiline: true, Indent: "  ", Resolver: types}.Marshal(&fds)
+	}
+	return nil, fmt.Errorf("unknown descriptor set format %q (expected %q, %q or %q)", opts.format, descriptorSetBinary, descriptorSetJSON, descriptorSetText)
+}
+
+// addWellKnownImports adds the well-known type files fds's files import
+// but it doesn't carry, ahead of the files importing them -- a
+// FileDescriptorSet lists every file after its imports.
+func addWellKnownImports(fds *descriptorpb.FileDescriptorSet) error {
+	have := make(map[string]bool, len(fds.File))
+	for _, file := range fds.File {
+		have[file.GetName()] = true
+	}
+	var added []*descriptorpb.FileDescriptorProto
+	var add func(path string) error
+	add = func(path string) error {
+		if have[path] {
+			return nil
+		}
+		have[path] = true
+		desc, err := protoregistry.GlobalFiles.FindFileByPath(path)
+		if err != nil {
+			return fmt.Errorf("finding import %s: %w", path, err)
+		}
+		file := protodesc.ToFileDescriptorProto(desc)
+		for _, dep := range file.Dependency {
+			if err := add(dep); err != nil {
+				return err
+			}
+		}
+		added = append(added, file)
+		return nil
+	}
+	for _, file := range fds.File {
+		for _, dep := range file.Dependency {
+			if err := add(dep); err != nil {
+				return err
+			}
+		}
+	}
+	fds.File = append(added, fds.File...)
+	return nil
+}
+
+// descriptorSetFileName returns the file name the Docs tab suggests
+// writing a descriptor set to.
+func descriptorSetFileName(owner, module, commitID string, format descriptorSetFormat) string {
+	return fmt.Sprintf("%s-%s-%s%s", owner, module, shortCommitID(commitID), format.fileExtension())
+}
+
+type descriptorSetWrittenMsg struct{ path string }
+
+type descriptorSetErrMsg struct{ err error }
+
+func (e descriptorSetErrMsg) Error() string { return e.err.Error() }
+
+// writeDescriptorSet writes the compiled docs' descriptor set to path, for
+// the Docs tab's write action.
+func writeDescriptorSet(entry docsCacheEntry, ownPaths map[string]bool, opts descriptorSetOptions, path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := buildDescriptorSet(entry, ownPaths, opts)
+		if err != nil {
+			return descriptorSetErrMsg{err}
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return descriptorSetErrMsg{fmt.Errorf("writing descriptor set: %w", err)}
+		}
+		return descriptorSetWrittenMsg{path}
+	}
+}
+
+// openDescriptorSetInput opens the input for where to write the Docs tab's
+// descriptor set, suggesting a name in the format last written.
+func (m *model) openDescriptorSetInput() {
+	format, ok := descriptorSetFormatOf(m.descriptorSetInput.Value())
+	if !ok {
+		format = descriptorSetBinary
+	}
+	m.descriptorSetInputActive = true
+	m.descriptorSetErr = nil
+	m.descriptorSetInput.SetValue(descriptorSetFileName(m.currentOwner, m.currentModule, m.currentCommitID, format))
+	m.descriptorSetInput.CursorEnd()
+	m.descriptorSetInput.Focus()
+}
+
+// cycleDescriptorSetFormat switches the descriptor set input's file name to
+// the next format's extension.
+func (m *model) cycleDescriptorSetFormat() {
+	path := m.descriptorSetInput.Value()
+	format, ok := descriptorSetFormatOf(path)
+	next := descriptorSetBinary
+	if ok {
+		next = descriptorSetFormats[(slices.Index(descriptorSetFormats, format)+1)%len(descriptorSetFormats)]
+		path = strings.TrimSuffix(path, filepath.Ext(path))
+	}
+	m.descriptorSetInput.SetValue(path + next.fileExtension())
+	m.descriptorSetInput.CursorEnd()
+	m.descriptorSetErr = nil
+}
+
+// submitDescriptorSetInput starts writing the descriptor set to the path
+// in the input, in the format its extension names, or leaves the input open
+// with the error if it doesn't name one.
+func (m *model) submitDescriptorSetInput() tea.Cmd {
+	path := strings.TrimSpace(m.descriptorSetInput.Value())
+	format, ok := descriptorSetFormatOf(path)
+	if !ok {
+		m.descriptorSetErr = fmt.Errorf("name a .binpb, .json or .txtpb file")
+		return nil
+	}
+	m.descriptorSetInputActive = false
+	entry := docsCacheEntry{files: m.compiledDocs, descriptorSet: m.descriptorSet}
+	opts := m.descriptorSetOptions
+	opts.format = format
+	return tea.Batch(
+		m.docsList.NewStatusMessage("writing descriptor set to "+path),
+		writeDescriptorSet(entry, m.ownProtoFilePaths, opts, path),
+	)
+}
+
+// descriptorSetInputView renders the descriptor set input for the docs
+// search row, with what it will leave out.
+func (m model) descriptorSetInputView() string {
+	check := func(included bool) string {
+		if included {
+			return "✓"
+		}
+		return "✗"
+	}
+	view := "write descriptor set to " + m.descriptorSetInput.View()
+	view += lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("  source info %s · imports %s",
+		check(!m.descriptorSetOptions.excludeSourceInfo), check(!m.descriptorSetOptions.excludeImports)))
+	if m.descriptorSetErr != nil {
+		view += " " + lipgloss.NewStyle().Foreground(colorError).Render(m.descriptorSetErr.Error())
+	}
+	return view
+}
+
+// runDescriptors implements the headless "descriptors" subcommand: it
+// compiles the commit a reference resolves to, as the Docs tab does, and
+// writes its descriptor set, then exits.
+func runDescriptors(ctx context.Context, args []string) error {
+	var flags runFlags
+	var format, output string
+	var opts descriptorSetOptions
+	fs := newFlagSet("buftui descriptors", &flags)
+	fs.StringVar(&format, "format", "", "Descriptor set format: binary, json or text (default: from the output's extension, else binary)")
+	fs.StringVar(&output, "output", "-", "File to write the descriptor set to, or - for stdout")
+	fs.StringVar(&output, "o", "-", "File to write the descriptor set to, or - for stdout")
+	fs.BoolVar(&opts.excludeSourceInfo, "exclude-source-info", false, "Leave out source code info (comments and locations)")
+	fs.BoolVar(&opts.excludeImports, "exclude-imports", false, "Leave out files the module imports from its dependencies")
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+	opts.format = descriptorSetFormat(format)
+	if format == "" {
+		var ok bool
+		if opts.format, ok = descriptorSetFormatOf(output); !ok {
+			opts.format = descriptorSetBinary
+		}
+	}
+	remote, token, reference, asOf, err := resolveConnection(flags)
+	if err != nil {
+		return err
+	}
+	if reference == nil {
+		return fmt.Errorf("descriptors requires a reference (-r owner/module{:ref}{@time})")
+	}
+
+	httpClient := httplb.NewClient()
+	defer httpClient.Close()
+	c := newClient(httpClient, remote, token)
+
+	ctx, cancel := context.WithTimeout(ctx, compileDocsTimeout)
+	defer cancel()
+	commit, err := c.resolveReferenceCommit(ctx, reference, asOf)
+	if err != nil {
+		return err
+	}
+	// Like export, verify every commit's digest before building anything
+	// from its files, dependencies included.
+	graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
+		ResourceRefs: []*modulev1.ResourceRef{{
+			Value: &modulev1.ResourceRef_Id{Id: commit.Id},
+		}},
+	}))
+	if err != nil {
+		return fmt.Errorf("getting dependency graph: %w", err)
+	}
+	graph := graphResp.Msg.Graph
+	if !slices.ContainsFunc(graph.Commits, func(graphCommit *modulev1.Commit) bool { return graphCommit.Id == commit.Id }) {
+		return fmt.Errorf("commit %s missing from its dependency graph", commit.Id)
+	}
+	filesByCommitID, err := c.downloadVerified(ctx, graph)
+	if err != nil {
+		return err
+	}
+	files := filesByCommitID[commit.Id]
+	var depFiles []*modulev1.File
+	for _, graphCommit := range graph.Commits {
+		if graphCommit.Id != commit.Id {
+			depFiles = append(depFiles, filesByCommitID[graphCommit.Id]...)
+		}
+	}
+	ownPaths := make(map[string]bool, len(files))
+	for _, file := range files {
+		if strings.HasSuffix(file.Path, ".proto") {
+			ownPaths[file.Path] = true
+		}
+	}
+	if len(ownPaths) == 0 {
+		return fmt.Errorf("%s/%s has no proto files", reference.Owner, reference.Module)
+	}
+	entry, err := compileFiles(ctx, files, depFiles)
+	if err != nil {
+		return err
+	}
+	data, err := buildDescriptorSet(entry, ownPaths, opts)
+	if err != nil {
+		return err
+	}
+	return writeOutput(output, data)
+}
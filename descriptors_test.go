@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// descriptorSetTestEntry compiles a module file, event.proto, importing a
+// dependency's file and a well-known type, as compiledDocs would.
+func descriptorSetTestEntry(t *testing.T) docsCacheEntry {
+	t.Helper()
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		{
+			Name:        new("dep/v1/id.proto"),
+			Syntax:      new("proto3"),
+			Package:     new("dep.v1"),
+			MessageType: []*descriptorpb.DescriptorProto{{Name: new("ID")}},
+		},
+		{
+			Name:       new("event.proto"),
+			Syntax:     new("proto3"),
+			Package:    new("event"),
+			Dependency: []string{"dep/v1/id.proto", "google/protobuf/timestamp.proto"},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: new("Event"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: new("id"), Number: new(int32(1)), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: new(".dep.v1.ID"), JsonName: new("id")},
+					{Name: new("at"), Number: new(int32(2)), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: new(".google.protobuf.Timestamp"), JsonName: new("at")},
+				},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, Span: []int32{3, 0, 6, 1}, LeadingComments: new(" An event.\n")},
+			}},
+		},
+	}}
+	data, err := proto.Marshal(fds)
+	ok.MustNoError(t, err)
+	files, _, err := resolveRegistry(data)
+	ok.MustNoError(t, err)
+	return docsCacheEntry{files: files, descriptorSet: data}
+}
+
+func TestBuildDescriptorSet(t *testing.T) {
+	t.Parallel()
+
+	entry := descriptorSetTestEntry(t)
+	own := map[string]bool{"event.proto": true}
+	decode := func(opts descriptorSetOptions) *descriptorpb.FileDescriptorSet {
+		t.Helper()
+		data, err := buildDescriptorSet(entry, own, opts)
+		ok.MustNoError(t, err)
+		var fds descriptorpb.FileDescriptorSet
+		ok.MustNoError(t, proto.Unmarshal(data, &fds))
+		return &fds
+	}
+	names := func(fds *descriptorpb.FileDescriptorSet) string {
+		var names []string
+		for _, file := range fds.File {
+			names = append(names, file.GetName())
+		}
+		return strings.Join(names, " ")
+	}
+
+	fds := decode(descriptorSetOptions{format: descriptorSetBinary})
+	ok.Equal(t, names(fds), "google/protobuf/timestamp.proto dep/v1/id.proto event.proto")
+	ok.True(t, fds.File[2].SourceCodeInfo != nil)
+
+	fds = decode(descriptorSetOptions{format: descriptorSetBinary, excludeImports: true, excludeSourceInfo: true})
+	ok.Equal(t, names(fds), "event.proto")
+	ok.True(t, fds.File[0].SourceCodeInfo == nil)
+
+	// protojson and prototext deliberately vary their whitespace, so check
+	// the other formats by reading them back.
+	data, err := buildDescriptorSet(entry, own, descriptorSetOptions{format: descriptorSetJSON, excludeImports: true})
+	ok.MustNoError(t, err)
+	var fromJSON descriptorpb.FileDescriptorSet
+	ok.MustNoError(t, protojson.Unmarshal(data, &fromJSON))
+	ok.Equal(t, names(&fromJSON), "event.proto")
+	ok.Equal(t, fromJSON.File[0].GetSourceCodeInfo().GetLocation()[0].GetLeadingComments(), " An event.\n")
+	data, err = buildDescriptorSet(entry, own, descriptorSetOptions{format: descriptorSetText})
+	ok.MustNoError(t, err)
+	var fromText descriptorpb.FileDescriptorSet
+	ok.MustNoError(t, prototext.Unmarshal(data, &fromText))
+	ok.Equal(t, names(&fromText), "google/protobuf/timestamp.proto dep/v1/id.proto event.proto")
+
+	_, err = buildDescriptorSet(entry, own, descriptorSetOptions{format: "yaml"})
+	ok.Error(t, err)
+}
+
+func TestAddWellKnownImports(t *testing.T) {
+	t.Parallel()
+
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		{Name: new("a.proto"), Dependency: []string{"google/protobuf/api.proto"}},
+		{Name: new("b.proto"), Dependency: []string{"a.proto", "google/protobuf/type.proto"}},
+	}}
+	ok.MustNoError(t, addWellKnownImports(fds))
+	var names []string
+	for _, file := range fds.File {
+		names = append(names, file.GetName())
+	}
+	// api.proto imports source_context.proto and type.proto, which imports
+	// any.proto too; each lands ahead of whatever imports it.
+	ok.Equal(t, strings.Join(names, " "), "google/protobuf/source_context.proto google/protobuf/any.proto google/protobuf/type.proto google/protobuf/api.proto a.proto b.proto")
+
+	fds.File = append(fds.File, &descriptorpb.FileDescriptorProto{Name: new("c.proto"), Dependency: []string{"missing.proto"}})
+	ok.Error(t, addWellKnownImports(fds))
+}
+
+// TestDocsTab_WriteDescriptorSet verifies the Docs tab's write input: its
+// suggested name, cycling formats, toggling what's left out, and writing.
+func TestDocsTab_WriteDescriptorSet(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.currentOwner = "acme"
+	m.currentModule = "events"
+	m.currentCommitID = "abc123def4567890"
+	m.ownProtoFilePaths = map[string]bool{"event.proto": true}
+	entry := descriptorSetTestEntry(t)
+	updated, _ := m.Update(docsMsg{files: entry.files, descriptorSet: entry.descriptorSet})
+	m = updated.(model)
+	m.state = modelStateBrowsingCommitContents
+	m.activeCommitTab = commitTabDocs
+	press := func(msg tea.KeyPressMsg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(model)
+		return cmd
+	}
+
+	press(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	ok.True(t, m.descriptorSetInputActive)
+	ok.Equal(t, m.descriptorSetInput.Value(), "acme-events-abc123def456.binpb")
+	press(tea.KeyPressMsg{Code: tea.KeyTab})
+	ok.Equal(t, m.descriptorSetInput.Value(), "acme-events-abc123def456.json")
+	press(tea.KeyPressMsg{Code: 'o', Mod: tea.ModCtrl})
+	ok.True(t, m.descriptorSetOptions.excludeImports)
+	ok.True(t, strings.Contains(m.descriptorSetInputView(), "imports ✗"))
+
+	// A name that doesn't say which format stays open to fix.
+	path := filepath.Join(t.TempDir(), "events")
+	m.descriptorSetInput.SetValue(path)
+	ok.True(t, press(tea.KeyPressMsg{Code: tea.KeyEnter}) == nil)
+	ok.True(t, m.descriptorSetInputActive)
+	ok.Error(t, m.descriptorSetErr)
+
+	m.descriptorSetInput.SetValue(path + ".txtpb")
+	ok.True(t, press(tea.KeyPressMsg{Code: tea.KeyEnter}) != nil)
+	ok.True(t, !m.descriptorSetInputActive)
+	msg := writeDescriptorSet(entry, m.ownProtoFilePaths, descriptorSetOptions{format: descriptorSetText, excludeImports: true}, path+".txtpb")()
+	ok.Equal(t, msg, tea.Msg(descriptorSetWrittenMsg{path + ".txtpb"}))
+	data, err := os.ReadFile(path + ".txtpb")
+	ok.MustNoError(t, err)
+	var fds descriptorpb.FileDescriptorSet
+	ok.MustNoError(t, prototext.Unmarshal(data, &fds))
+	ok.Equal(t, len(fds.File), 1)
+}
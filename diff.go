@@ -0,0 +1,575 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// diffContextLines is how many unchanged lines a diff shows around
+	// each change.
+	diffContextLines = 3
+	// diffMaxEdits is the most lines a diff adds and removes before the
+	// files are shown as one replaced by the other instead, which bounds
+	// the time and memory myers takes to a few megabytes.
+	diffMaxEdits = 1000
+)
+
+// fileDiffStatus is how a file in the Files tab differs from the commit
+// being diffed against.
+type fileDiffStatus int
+
+const (
+	fileUnchanged fileDiffStatus = iota
+	fileModified
+	fileAdded
+	fileRemoved
+)
+
+// marker renders the status as the one-letter mark before a file's name in
+// the Files tab, or "" for an unchanged file.
+func (s fileDiffStatus) marker() string {
+	switch s {
+	case fileModified:
+		return lipgloss.NewStyle().Foreground(colorDiffModified).Render("M")
+	case fileAdded:
+		return lipgloss.NewStyle().Foreground(colorDiffAdded).Render("A")
+	case fileRemoved:
+		return lipgloss.NewStyle().Foreground(colorDiffRemoved).Render("D")
+	}
+	return ""
+}
+
+// withMarker prefixes name with the status's marker, if it has one.
+func (s fileDiffStatus) withMarker(name string) string {
+	if s == fileUnchanged {
+		return name
+	}
+	return s.marker() + " " + name
+}
+
+// diffBaseMsg carries the files of the commit the Files tab is diffed
+// against: the commit ref resolved to, in owner/module.
+type diffBaseMsg struct {
+	owner, module, ref string
+	content            *modulev1.DownloadResponse_Content
+}
+
+type diffBaseErrMsg struct {
+	ref string
+	err error
+}
+
+func (e diffBaseErrMsg) Error() string { return e.err.Error() }
+
+// getDiffBase downloads the files of ref -- a commit ID or label -- of
+// owner/module, to diff commits against.
+func (c *client) getDiffBase(owner, module, ref string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		response, err := c.downloadServiceClient.Download(ctx, connect.NewRequest(&modulev1.DownloadRequest{
+			Values: []*modulev1.DownloadRequest_Value{{
+				ResourceRef: &modulev1.ResourceRef{
+					Value: &modulev1.ResourceRef_Name_{
+						Name: &modulev1.ResourceRef_Name{
+							Owner:  owner,
+							Module: module,
+							Child:  &modulev1.ResourceRef_Name_Ref{Ref: ref},
+						},
+					},
+				},
+			}},
+		}))
+		if err != nil {
+			return diffBaseErrMsg{ref, fmt.Errorf("getting %s: %w", ref, err)}
+		}
+		if len(response.Msg.Contents) != 1 {
+			return diffBaseErrMsg{ref, fmt.Errorf("requested 1 commit contents, got %v", len(response.Msg.Contents))}
+		}
+		return diffBaseMsg{owner: owner, module: module, ref: ref, content: response.Msg.Contents[0]}
+	}
+}
+
+// diffFileItems lists files as the Files tab's items, marked with how each
+// differs from base, and with base's files that aren't in files added as
+// removed. Unlike the plain listing, they're sorted by path, so removed
+// files land among their neighbors.
+func diffFileItems(files []*modulev1.File, base map[string]*modulev1.File, newItem func(*modulev1.File) *commitFile) []list.Item {
+	var items []*commitFile
+	paths := make(map[string]bool, len(files))
+	for _, file := range files {
+		paths[file.Path] = true
+		item := newItem(file)
+		switch baseFile, ok := base[file.Path]; {
+		case !ok:
+			item.diff = fileAdded
+		case !bytes.Equal(baseFile.Content, file.Content):
+			item.diff = fileModified
+		}
+		items = append(items, item)
+	}
+	for path, file := range base {
+		if !paths[path] {
+			item := newItem(file)
+			item.diff = fileRemoved
+			items = append(items, item)
+		}
+	}
+	slices.SortFunc(items, func(a, b *commitFile) int {
+		return strings.Compare(a.underlying.Path, b.underlying.Path)
+	})
+	listItems := make([]list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = item
+	}
+	return listItems
+}
+
+// diffKind is what a line of a diff does.
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffDelete
+	diffInsert
+)
+
+// diffLine is a line of a diff: a line of the old file (a), the new file
+// (b), or both, as 0-indexed line numbers, -1 where it isn't in one.
+type diffLine struct {
+	kind diffKind
+	a, b int
+}
+
+// diffLines diffs a against b line by line with Myers' algorithm, returning
+// every line of both in order. Common leading and trailing lines are
+// trimmed first, so the usual small change to a large file is cheap.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var lines []diffLine
+	for i := range prefix {
+		lines = append(lines, diffLine{diffEqual, i, i})
+	}
+	for _, line := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if line.a >= 0 {
+			line.a += prefix
+		}
+		if line.b >= 0 {
+			line.b += prefix
+		}
+		lines = append(lines, line)
+	}
+	for i := range suffix {
+		lines = append(lines, diffLine{diffEqual, len(a) - suffix + i, len(b) - suffix + i})
+	}
+	return lines
+}
+
+// myers is the greedy O(ND) diff of "An O(ND) Difference Algorithm and Its
+// Variations" (Myers, 1986), keeping the part of each round's
+// furthest-reaching paths that round can reach to walk the shortest edit
+// script back from the end. That's O(D²) memory, so past diffMaxEdits edits
+// it gives up and replaces a with b wholesale.
+func myers(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= min(n+m, diffMaxEdits); d++ {
+		// Round d only reads diagonals -d+1 through d-1 of the rounds
+		// before it.
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return myersBacktrack(trace, n, m)
+			}
+		}
+	}
+	return replaceLines(n, m)
+}
+
+// myersBacktrack walks the shortest edit script back from (x, y), where
+// trace[d] holds diagonals -d through d of the furthest-reaching paths
+// before round d.
+func myersBacktrack(trace [][]int, x, y int) []diffLine {
+	var lines []diffLine
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, diffLine{diffEqual, x, y})
+		}
+		if x == prevX {
+			lines = append(lines, diffLine{diffInsert, -1, prevY})
+		} else {
+			lines = append(lines, diffLine{diffDelete, prevX, -1})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		lines = append(lines, diffLine{diffEqual, x, y})
+	}
+	slices.Reverse(lines)
+	return lines
+}
+
+// replaceLines is the diff that deletes all n lines of a and inserts all m
+// of b, for files too different to diff line by line.
+func replaceLines(n, m int) []diffLine {
+	lines := make([]diffLine, 0, n+m)
+	for i := range n {
+		lines = append(lines, diffLine{diffDelete, i, -1})
+	}
+	for i := range m {
+		lines = append(lines, diffLine{diffInsert, -1, i})
+	}
+	return lines
+}
+
+// diffHunks groups the changed lines of a diff, with diffContextLines of
+// unchanged lines around each, merging groups whose context would overlap.
+func diffHunks(lines []diffLine) [][]diffLine {
+	var hunks [][]diffLine
+	start, end := -1, -1
+	for i, line := range lines {
+		if line.kind == diffEqual {
+			continue
+		}
+		from, to := max(0, i-diffContextLines), min(len(lines), i+diffContextLines+1)
+		if start >= 0 && from <= end {
+			end = to
+			continue
+		}
+		if start >= 0 {
+			hunks = append(hunks, lines[start:end])
+		}
+		start, end = from, to
+	}
+	if start >= 0 {
+		hunks = append(hunks, lines[start:end])
+	}
+	return hunks
+}
+
+// hunkHeader renders a hunk's "@@ -a,b +c,d @@" header, as in a unified
+// diff.
+func hunkHeader(hunk []diffLine) string {
+	aStart, bStart, aCount, bCount := -1, -1, 0, 0
+	for _, line := range hunk {
+		if line.a >= 0 {
+			aCount++
+			if aStart < 0 {
+				aStart = line.a
+			}
+		}
+		if line.b >= 0 {
+			bCount++
+			if bStart < 0 {
+				bStart = line.b
+			}
+		}
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart+1, aCount, bStart+1, bCount)
+}
+
+// highlightSource syntax-highlights a file's source for the terminal, split
+// into lines. Unlike highlightFile it never renders markdown, so each line
+// stays the file's own.
+func highlightSource(filename, fileContents string, isDark bool) []string {
+	lines := splitFileLines(fileContents)
+	output, err := highlightCode(filename, fileContents, isDark)
+	if err != nil {
+		return lines
+	}
+	// chroma's terminal formatters close their escapes before every
+	// newline, so the output splits into self-contained lines.
+	highlighted := splitFileLines(output)
+	if len(highlighted) != len(lines) {
+		return lines
+	}
+	return highlighted
+}
+
+// splitFileLines splits content into lines, without an empty last line for
+// a trailing newline. An empty file has no lines.
+func splitFileLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// renderFileDiff renders the diff of a file from before to after (either of
+// which is nil if the file was added or removed), syntax-highlighted, unified or
+// side by side in width columns.
+func renderFileDiff(path string, before, after *modulev1.File, sideBySide, isDark bool, width int) string {
+	var oldContent, newContent string
+	if before != nil {
+		oldContent = string(before.Content)
+	}
+	if after != nil {
+		newContent = string(after.Content)
+	}
+	oldLines, newLines := splitFileLines(oldContent), splitFileLines(newContent)
+	oldHighlighted, newHighlighted := oldLines, newLines
+	if len(oldLines) > 0 {
+		oldHighlighted = highlightSource(path, oldContent, isDark)
+	}
+	if len(newLines) > 0 {
+		newHighlighted = highlightSource(path, newContent, isDark)
+	}
+	lines := diffLines(oldLines, newLines)
+	numberWidth := len(fmt.Sprint(max(len(oldLines), len(newLines), 1)))
+	dim := lipgloss.NewStyle().Faint(true)
+	hunkStyle := lipgloss.NewStyle().Foreground(colorForeground)
+	removed := lipgloss.NewStyle().Foreground(colorDiffRemoved).Render("-")
+	added := lipgloss.NewStyle().Foreground(colorDiffAdded).Render("+")
+	number := func(i int) string {
+		if i < 0 {
+			return dim.Render(strings.Repeat(" ", numberWidth))
+		}
+		return dim.Render(fmt.Sprintf("%*d", numberWidth, i+1))
+	}
+	// side renders one side of a side-by-side row, exactly half wide.
+	half := max(1, (width-3)/2)
+	side := func(i int, marker string, text []string) string {
+		var s string
+		if i < 0 {
+			s = number(-1) + "  "
+		} else {
+			s = number(i) + marker + " " + text[i]
+		}
+		s = ansi.Truncate(s, half, "…")
+		return s + strings.Repeat(" ", max(0, half-ansi.StringWidth(s)))
+	}
+	var b strings.Builder
+	for i, hunk := range diffHunks(lines) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(hunkStyle.Render(hunkHeader(hunk)) + "\n")
+		if !sideBySide {
+			for _, line := range hunk {
+				switch line.kind {
+				case diffEqual:
+					b.WriteString(number(line.a) + " " + number(line.b) + "   " + newHighlighted[line.b] + "\n")
+				case diffDelete:
+					b.WriteString(number(line.a) + " " + number(-1) + " " + removed + " " + oldHighlighted[line.a] + "\n")
+				case diffInsert:
+					b.WriteString(number(-1) + " " + number(line.b) + " " + added + " " + newHighlighted[line.b] + "\n")
+				}
+			}
+			continue
+		}
+		separator := dim.Render(" │ ")
+		for j := 0; j < len(hunk); {
+			if hunk[j].kind == diffEqual {
+				line := hunk[j]
+				b.WriteString(side(line.a, " ", oldHighlighted) + separator + side(line.b, " ", newHighlighted) + "\n")
+				j++
+				continue
+			}
+			// Pair a run of deleted lines with the inserted lines after it,
+			// row by row.
+			var deleted, inserted []int
+			for ; j < len(hunk) && hunk[j].kind == diffDelete; j++ {
+				deleted = append(deleted, hunk[j].a)
+			}
+			for ; j < len(hunk) && hunk[j].kind == diffInsert; j++ {
+				inserted = append(inserted, hunk[j].b)
+			}
+			for row := range max(len(deleted), len(inserted)) {
+				left, right := side(-1, "", nil), side(-1, "", nil)
+				if row < len(deleted) {
+					left = side(deleted[row], removed, oldHighlighted)
+				}
+				if row < len(inserted) {
+					right = side(inserted[row], added, newHighlighted)
+				}
+				b.WriteString(left + separator + right + "\n")
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return dim.Render("No changes")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// fileDiffMsg carries a file's rendered diff for the file viewer.
+type fileDiffMsg struct {
+	id      int
+	content string
+}
+
+// renderFileDiffCmd renders a file's diff (see renderFileDiff) off the
+// event loop, as a fileDiffMsg tagged with id.
+func renderFileDiffCmd(id int, path string, before, after *modulev1.File, sideBySide, isDark bool, width int) tea.Cmd {
+	return func() tea.Msg {
+		return fileDiffMsg{id: id, content: renderFileDiff(path, before, after, sideBySide, isDark, width)}
+	}
+}
+
+// diffing reports whether the Files tab is diffed against another commit.
+func (m model) diffing() bool {
+	return m.diffFiles != nil
+}
+
+// openDiffInput opens the input for the commit or label to diff the Files
+// tab against, holding the current one to edit.
+func (m *model) openDiffInput() {
+	m.diffInputActive = true
+	m.diffErr = nil
+	m.diffInput.SetValue(m.diffRef)
+	m.diffInput.CursorEnd()
+	m.diffInput.Focus()
+}
+
+// startDiff starts diffing the Files tab, for this commit and any opened
+// after it in the same module, against ref: a commit ID or label. An empty
+// ref stops diffing.
+func (m *model) startDiff(ref string) tea.Cmd {
+	m.diffInputActive = false
+	m.diffErr = nil
+	if ref == "" {
+		return m.stopDiff()
+	}
+	m.diffRef = ref
+	m.diffOwner = m.currentOwner
+	m.diffModule = m.currentModule
+	m.diffLoading = true
+	return m.client.getDiffBase(m.currentOwner, m.currentModule, ref)
+}
+
+// stopDiff stops diffing the Files tab, if it is, returning the command
+// that re-renders the file viewer.
+func (m *model) stopDiff() tea.Cmd {
+	wasDiffing := m.diffing()
+	m.diffRef = ""
+	m.diffOwner = ""
+	m.diffModule = ""
+	m.diffBaseCommitID = ""
+	m.diffFiles = nil
+	m.diffLoading = false
+	if wasDiffing && m.currentCommitFiles != nil {
+		m.setCommitFileItems()
+		return m.refreshFileView()
+	}
+	return nil
+}
+
+// setCommitFileItems fills the Files tab from the current commit's files,
+// marked up against the diffed commit if there is one, keeping the file
+// under the cursor selected where it's still listed.
+func (m *model) setCommitFileItems() {
+	var selectedPath string
+	if selected, ok := m.commitFilesList.SelectedItem().(*commitFile); ok {
+		selectedPath = selected.underlying.Path
+	}
+	newItem := func(file *modulev1.File) *commitFile {
+		return &commitFile{underlying: file, remote: m.remote, owner: m.currentOwner, moduleName: m.currentModule, commitID: m.currentCommitID}
+	}
+	var items []list.Item
+	if m.diffing() {
+		items = diffFileItems(m.currentCommitFiles, m.diffFiles, newItem)
+	} else {
+		items = make([]list.Item, len(m.currentCommitFiles))
+		for i, file := range m.currentCommitFiles {
+			items[i] = newItem(file)
+		}
+	}
+	m.commitFilesList.ResetFilter()
+	m.commitFilesList.SetItems(items)
+	m.commitFilesList.ResetSelected()
+	for i, item := range items {
+		if item.(*commitFile).underlying.Path == selectedPath {
+			m.commitFilesList.Select(i)
+			break
+		}
+	}
+	m.rebuildFilesTree()
+}
+
+// refreshFileView re-renders the file under the cursor in the file viewer,
+// e.g. as a diff once there's something to diff it against.
+func (m *model) refreshFileView() tea.Cmd {
+	selected, ok := m.commitFilesList.SelectedItem().(*commitFile)
+	if !ok {
+		return nil
+	}
+	cmd := m.updateFileView(selected.underlying)
+	m.fileViewport.GotoTop()
+	return cmd
+}
+
+// fileDiff returns the versions of the file at path to diff -- before, in
+// the diffed commit, and after, in the current one, either nil if it isn't
+// in that commit -- or false if the tab isn't diffed or the file is the same
+// in both.
+func (m model) fileDiff(path string) (before, after *modulev1.File, ok bool) {
+	if !m.diffing() {
+		return nil, nil, false
+	}
+	before, after = m.diffFiles[path], findFile(m.currentCommitFiles, path)
+	if before != nil && after != nil && bytes.Equal(before.Content, after.Content) {
+		return nil, nil, false
+	}
+	return before, after, true
+}
+
+// diffSummary describes the diff for the line under the file viewer.
+func (m model) diffSummary() string {
+	changed := 0
+	for _, item := range m.commitFilesList.Items() {
+		if file, ok := item.(*commitFile); ok && file.diff != fileUnchanged {
+			changed++
+		}
+	}
+	base := m.diffRef
+	if m.diffBaseCommitID != "" && !strings.HasPrefix(m.diffBaseCommitID, m.diffRef) {
+		base += " (" + m.diffBaseCommitID[:min(12, len(m.diffBaseCommitID))] + ")"
+	}
+	return fmt.Sprintf("Diff against %s · %d changed file%s", base, changed, plural(changed))
+}
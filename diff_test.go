@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+// applyDiff applies a diff's edits to a, which should give b.
+func applyDiff(a, b []string, lines []diffLine) []string {
+	var out []string
+	for _, line := range lines {
+		switch line.kind {
+		case diffEqual:
+			out = append(out, a[line.a])
+		case diffInsert:
+			out = append(out, b[line.b])
+		}
+	}
+	return out
+}
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		a, b    string
+		changes int
+	}{
+		{"", "", 0},
+		{"a b c", "a b c", 0},
+		{"", "a b", 2},
+		{"a b", "", 2},
+		{"a b c d", "a x c d", 2},
+		{"a b c a b b a", "c b a b a c", 5},
+		{"x a b c", "a b c y", 2},
+	} {
+		a, b := strings.Fields(test.a), strings.Fields(test.b)
+		lines := diffLines(a, b)
+		ok.Equal(t, strings.Join(applyDiff(a, b, lines), " "), strings.Join(b, " "), ok.Sprintf("%q → %q", test.a, test.b))
+		changes := 0
+		for _, line := range lines {
+			if line.kind != diffEqual {
+				changes++
+			}
+		}
+		ok.Equal(t, changes, test.changes, ok.Sprintf("%q → %q is not a shortest edit", test.a, test.b))
+	}
+}
+
+// TestDiffLines_TooDifferent verifies that files too different to diff
+// line by line show as one replaced by the other, past their common lines.
+func TestDiffLines_TooDifferent(t *testing.T) {
+	t.Parallel()
+
+	a, b := []string{"same"}, []string{"same"}
+	for i := range diffMaxEdits {
+		a = append(a, fmt.Sprint("old ", i))
+		b = append(b, fmt.Sprint("new ", i))
+	}
+	lines := diffLines(a, b)
+	ok.Equal(t, strings.Join(applyDiff(a, b, lines), "\n"), strings.Join(b, "\n"))
+	ok.Equal(t, lines[0], diffLine{diffEqual, 0, 0})
+	for i, line := range lines[1:] {
+		if i < diffMaxEdits {
+			ok.Equal(t, line, diffLine{diffDelete, i + 1, -1})
+		} else {
+			ok.Equal(t, line, diffLine{diffInsert, -1, i - diffMaxEdits + 1})
+		}
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	t.Parallel()
+
+	var a, b []string
+	for i := range 30 {
+		line := string(rune('a' + i%26))
+		a = append(a, line)
+		switch i {
+		case 2:
+			b = append(b, "changed")
+		case 8:
+			// Close enough to share the first change's hunk.
+		case 25:
+			b = append(b, line, "inserted")
+		default:
+			b = append(b, line)
+		}
+	}
+	hunks := diffHunks(diffLines(a, b))
+	ok.Equal(t, len(hunks), 2)
+	ok.Equal(t, hunkHeader(hunks[0]), "@@ -1,12 +1,11 @@")
+	ok.Equal(t, hunkHeader(hunks[1]), "@@ -24,6 +23,7 @@")
+}
+
+func TestDiffFileItems(t *testing.T) {
+	t.Parallel()
+
+	files := []*modulev1.File{
+		{Path: "same.proto", Content: []byte("same")},
+		{Path: "changed.proto", Content: []byte("new")},
+		{Path: "added.proto", Content: []byte("added")},
+	}
+	base := map[string]*modulev1.File{
+		"same.proto":    {Path: "same.proto", Content: []byte("same")},
+		"changed.proto": {Path: "changed.proto", Content: []byte("old")},
+		"gone.proto":    {Path: "gone.proto", Content: []byte("gone")},
+	}
+	items := diffFileItems(files, base, func(file *modulev1.File) *commitFile {
+		return &commitFile{underlying: file}
+	})
+	var got []string
+	for _, item := range items {
+		got = append(got, ansi.Strip(item.(*commitFile).Title()))
+	}
+	ok.Equal(t, strings.Join(got, ", "), "A added.proto, M changed.proto, D gone.proto, same.proto")
+}
+
+func TestRenderFileDiff(t *testing.T) {
+	t.Parallel()
+
+	before := &modulev1.File{Path: "notes.txt", Content: []byte("one\ntwo\nthree\n")}
+	after := &modulev1.File{Path: "notes.txt", Content: []byte("one\n2\nthree\nfour\n")}
+
+	unified := ansi.Strip(renderFileDiff("notes.txt", before, after, false, true, 80))
+	ok.Equal(t, unified, strings.Join([]string{
+		"@@ -1,3 +1,4 @@",
+		"1 1   one",
+		"2   - two",
+		"  2 + 2",
+		"3 3   three",
+		"  4 + four",
+	}, "\n"))
+
+	sideBySide := ansi.Strip(renderFileDiff("notes.txt", before, after, true, true, 23))
+	ok.Equal(t, sideBySide, strings.Join([]string{
+		"@@ -1,3 +1,4 @@",
+		"1  one     │ 1  one    ",
+		"2- two     │ 2+ 2      ",
+		"3  three   │ 3  three  ",
+		"           │ 4+ four   ",
+	}, "\n"))
+
+	ok.Equal(t, ansi.Strip(renderFileDiff("notes.txt", before, before, false, true, 80)), "No changes")
+}
+
+// TestFilesTab_Diff verifies diffing the Files tab against a label: the
+// files are marked with how they changed, and a changed file shows as a
+// diff.
+func TestFilesTab_Diff(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	m.currentCommitID = "fff000"
+	// Against the fake server's commits: buf.yaml changes, buf.gen.yaml
+	// goes, and new.proto arrives.
+	updated, _ := m.Update(contentsMsg(&modulev1.DownloadResponse_Content{Files: []*modulev1.File{
+		{Path: "buf.yaml", Content: []byte("version: v2\nbreaking:\n  use: FILE\n")},
+		{Path: "README.md", Content: []byte("# Registry\n\nThe Buf registry module.\n")},
+		{Path: "new.proto", Content: []byte("syntax = \"proto3\";\n")},
+	}}))
+	m = updated.(model)
+	m.activeCommitTab = commitTabFiles
+	press := func(code rune, text string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+		return cmd
+	}
+
+	press('M', "M")
+	ok.True(t, m.diffInputActive)
+	for _, r := range "main" {
+		press(r, string(r))
+	}
+	cmd := press(tea.KeyEnter, "")
+	ok.True(t, !m.diffInputActive)
+	ok.True(t, m.diffLoading)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, m.diffing())
+
+	var titles []string
+	for _, item := range m.commitFilesList.Items() {
+		titles = append(titles, ansi.Strip(item.(*commitFile).Title()))
+	}
+	ok.Equal(t, strings.Join(titles, ", "), "README.md, D buf.gen.yaml, M buf.yaml, A new.proto")
+	ok.True(t, strings.Contains(ansi.Strip(m.fileStatusView()), "Diff against main (abc123def456) · 3 changed files"))
+
+	// The diff renders off the event loop, with a placeholder until then.
+	m.commitFilesList.Select(2)
+	cmd = m.refreshFileView()
+	ok.Equal(t, ansi.Strip(m.fileViewport.GetContent()), "Rendering diff…")
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	content := ansi.Strip(m.fileViewport.GetContent())
+	ok.True(t, strings.Contains(content, "1   - version: v1\n  1 + version: v2"), ok.Sprintf("expected a unified diff:\n%s", content))
+	cmd = press('v', "v")
+	ok.True(t, m.diffSideBySide)
+	// A render for content since replaced is dropped.
+	stale := m.refreshFileView()
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.Equal(t, ansi.Strip(m.fileViewport.GetContent()), "Rendering diff…")
+	updated, _ = m.Update(stale())
+	m = updated.(model)
+	content = ansi.Strip(m.fileViewport.GetContent())
+	ok.True(t, strings.Contains(content, "1- version: v1"), ok.Sprintf("expected a side-by-side diff:\n%s", content))
+
+	// Clearing the ref stops diffing.
+	press('M', "M")
+	for range "main" {
+		press(tea.KeyBackspace, "")
+	}
+	press(tea.KeyEnter, "")
+	ok.True(t, !m.diffing())
+	ok.Equal(t, len(m.commitFilesList.Items()), 3)
+}
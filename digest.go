@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"crypto/sha3"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"connectrpc.com/connect"
+)
+
+// shake256Length is the output length, in bytes, of every SHAKE256 digest
+// that goes into a B5 digest (and of the B5 digest itself).
+const shake256Length = 64
+
+// digestStatus is the outcome of checking the current commit's downloaded
+// files against the digest the BSR reports for it.
+type digestStatus int
+
+const (
+	// digestPending means verification hasn't finished yet.
+	digestPending digestStatus = iota
+	digestVerified
+	digestMismatch
+	// digestUnverifiable means verification couldn't be carried out at
+	// all (e.g. fetching the dependency graph failed), which says nothing
+	// either way about the content.
+	digestUnverifiable
+)
+
+// digestMismatchError reports that a commit's content doesn't hash to the
+// digest the BSR reports for it.
+type digestMismatchError struct {
+	commitID string
+	expected string
+	actual   string
+}
+
+func (e *digestMismatchError) Error() string {
+	return fmt.Sprintf("digest mismatch for commit %s: BSR reports %s, content hashes to %s", e.commitID, e.expected, e.actual)
+}
+
+// b5Digest computes the BSR's B5 digest of a commit from its files and the
+// B5 digests of all of its (transitive) dependencies.
+//
+// It mirrors buf's own implementation: a manifest of "shake256:<hex>  <path>"
+// lines, one per file sorted by path, is hashed, and that digest followed by
+// the sorted dependency digests (newline-separated) is hashed again.
+func b5Digest(files []*modulev1.File, depDigests []*modulev1.Digest) (*modulev1.Digest, error) {
+	sortedFiles := slices.Clone(files)
+	slices.SortFunc(sortedFiles, func(a, b *modulev1.File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	var manifest strings.Builder
+	for _, f := range sortedFiles {
+		manifest.WriteString(shake256String(f.Content) + "  " + f.Path + "\n")
+	}
+
+	depDigestStrings := make([]string, len(depDigests))
+	for i, d := range depDigests {
+		if d.GetType() != modulev1.DigestType_DIGEST_TYPE_B5 {
+			return nil, fmt.Errorf("dependency has unsupported digest type %v", d.GetType())
+		}
+		depDigestStrings[i] = digestString(d)
+	}
+	slices.Sort(depDigestStrings)
+
+	digestStrings := append([]string{shake256String([]byte(manifest.String()))}, depDigestStrings...)
+	return &modulev1.Digest{
+		Type:  modulev1.DigestType_DIGEST_TYPE_B5,
+		Value: sha3.SumSHAKE256([]byte(strings.Join(digestStrings, "\n")), shake256Length),
+	}, nil
+}
+
+func shake256String(data []byte) string {
+	return "shake256:" + hex.EncodeToString(sha3.SumSHAKE256(data, shake256Length))
+}
+
+// verifyDigest checks files against commit's reported digest, returning a
+// *digestMismatchError if they don't match.
+func verifyDigest(commit *modulev1.Commit, files []*modulev1.File, depDigests []*modulev1.Digest) error {
+	if commit.GetDigest().GetType() != modulev1.DigestType_DIGEST_TYPE_B5 {
+		return fmt.Errorf("commit %s has unsupported digest type %v", commit.GetId(), commit.GetDigest().GetType())
+	}
+	actual, err := b5Digest(files, depDigests)
+	if err != nil {
+		return fmt.Errorf("computing digest of commit %s: %w", commit.GetId(), err)
+	}
+	expected := digestString(commit.Digest)
+	if digestString(actual) != expected {
+		return &digestMismatchError{
+			commitID: commit.GetId(),
+			expected: expected,
+			actual:   digestString(actual),
+		}
+	}
+	return nil
+}
+
+// transitiveDepDigests returns the digests of every commit reachable from
+// commitID in graph, not including commitID itself.
+func transitiveDepDigests(commitID string, graph *modulev1.Graph) []*modulev1.Digest {
+	children := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		from, to := edge.FromNode.CommitId, edge.ToNode.CommitId
+		children[from] = append(children[from], to)
+	}
+	digestByCommitID := make(map[string]*modulev1.Digest, len(graph.Commits))
+	for _, commit := range graph.Commits {
+		digestByCommitID[commit.Id] = commit.Digest
+	}
+	visited := map[string]bool{commitID: true}
+	var digests []*modulev1.Digest
+	queue := slices.Clone(children[commitID])
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		digests = append(digests, digestByCommitID[id])
+		queue = append(queue, children[id]...)
+	}
+	return digests
+}
+
+// digestVerifiedMsg and digestErrMsg carry the result of verifyCommitDigest.
+// Both record the commit they're about, so a result arriving after the user
+// has moved on to another commit can be dropped.
+type digestVerifiedMsg struct {
+	commitID string
+}
+
+type digestErrMsg struct {
+	commitID string
+	err      error
+}
+
+func (e digestErrMsg) Error() string { return e.err.Error() }
+
+// verifyCommitDigest checks the already-downloaded files of commit against
+// its reported digest. The B5 digest covers the commit's dependencies too,
+// so this fetches the dependency graph for their digests.
+func (c *client) verifyCommitDigest(commit *modulev1.Commit, files []*modulev1.File) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
+			ResourceRefs: []*modulev1.ResourceRef{{
+				Value: &modulev1.ResourceRef_Id{Id: commit.Id},
+			}},
+		}))
+		if err != nil {
+			return digestErrMsg{commit.Id, fmt.Errorf("getting dependency graph: %w", err)}
+		}
+		if err := verifyDigest(commit, files, transitiveDepDigests(commit.Id, graphResp.Msg.Graph)); err != nil {
+			return digestErrMsg{commit.Id, err}
+		}
+		return digestVerifiedMsg{commit.Id}
+	}
+}
+
+// downloadVerified downloads the full content of every commit in graph in
+// one batched request and verifies each against its reported digest,
+// returning the files by commit ID. Any mismatch fails the whole download:
+// it's for exports, where unverified content must never reach disk.
+func (c *client) downloadVerified(ctx context.Context, graph *modulev1.Graph) (map[string][]*modulev1.File, error) {
+	return c.downloadVerifiedCommits(ctx, graph, graph.Commits)
+}
+
+// downloadVerifiedCommits is downloadVerified for just commits, some of
+// graph's: verifying a commit's digest takes its dependencies' digests,
+// which the graph has, but not their content.
+func (c *client) downloadVerifiedCommits(ctx context.Context, graph *modulev1.Graph, commits []*modulev1.Commit) (map[string][]*modulev1.File, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	values := make([]*modulev1.DownloadRequest_Value, len(commits))
+	for i, commit := range commits {
+		values[i] = &modulev1.DownloadRequest_Value{
+			ResourceRef: &modulev1.ResourceRef{
+				Value: &modulev1.ResourceRef_Id{Id: commit.Id},
+			},
+		}
+	}
+	response, err := c.downloadServiceClient.Download(ctx, connect.NewRequest(&modulev1.DownloadRequest{
+		Values: values,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("downloading commits: %w", err)
+	}
+	filesByCommitID := make(map[string][]*modulev1.File, len(response.Msg.Contents))
+	for _, content := range response.Msg.Contents {
+		filesByCommitID[content.GetCommit().GetId()] = content.Files
+	}
+	var errs []error
+	for _, commit := range commits {
+		files, ok := filesByCommitID[commit.Id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("commit %s missing from download", commit.Id))
+			continue
+		}
+		errs = append(errs, verifyDigest(commit, files, transitiveDepDigests(commit.Id, graph)))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return filesByCommitID, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/sha3"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"go.vanburen.xyz/ok"
+)
+
+func TestB5Digest_Format(t *testing.T) {
+	t.Parallel()
+
+	files := []*modulev1.File{{Path: "a.proto", Content: []byte("syntax = \"proto3\";\n")}}
+	dep := &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0x01}}
+	got, err := b5Digest(files, []*modulev1.Digest{dep})
+	ok.MustNoError(t, err)
+
+	// Spelled out by hand: the manifest digest, then each dependency's.
+	fileDigest := hex.EncodeToString(sha3.SumSHAKE256(files[0].Content, 64))
+	manifest := "shake256:" + fileDigest + "  a.proto\n"
+	manifestDigest := "shake256:" + hex.EncodeToString(sha3.SumSHAKE256([]byte(manifest), 64))
+	want := sha3.SumSHAKE256([]byte(manifestDigest+"\nb5:01"), 64)
+
+	ok.Equal(t, got.Type, modulev1.DigestType_DIGEST_TYPE_B5)
+	ok.Equal(t, hex.EncodeToString(got.Value), hex.EncodeToString(want))
+}
+
+func TestB5Digest_OrderIndependent(t *testing.T) {
+	t.Parallel()
+
+	a := &modulev1.File{Path: "a.proto", Content: []byte("a")}
+	b := &modulev1.File{Path: "b/b.proto", Content: []byte("b")}
+	x := &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0x0a}}
+	y := &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0x0b}}
+
+	first, err := b5Digest([]*modulev1.File{a, b}, []*modulev1.Digest{x, y})
+	ok.MustNoError(t, err)
+	second, err := b5Digest([]*modulev1.File{b, a}, []*modulev1.Digest{y, x})
+	ok.MustNoError(t, err)
+	ok.Equal(t, digestString(first), digestString(second))
+
+	withoutDeps, err := b5Digest([]*modulev1.File{a, b}, nil)
+	ok.MustNoError(t, err)
+	ok.NotEqual(t, digestString(first), digestString(withoutDeps), ok.Sprintf("dependency digests should contribute"))
+}
+
+func TestB5Digest_UnsupportedDepDigest(t *testing.T) {
+	t.Parallel()
+
+	_, err := b5Digest(nil, []*modulev1.Digest{{Type: modulev1.DigestType_DIGEST_TYPE_UNSPECIFIED}})
+	ok.Error(t, err)
+}
+
+func TestVerifyDigest(t *testing.T) {
+	t.Parallel()
+
+	files := []*modulev1.File{{Path: "a.proto", Content: []byte("a")}}
+	digest, err := b5Digest(files, nil)
+	ok.MustNoError(t, err)
+	commit := &modulev1.Commit{Id: "c1", Digest: digest}
+
+	ok.NoError(t, verifyDigest(commit, files, nil))
+
+	tampered := []*modulev1.File{{Path: "a.proto", Content: []byte("b")}}
+	err = verifyDigest(commit, tampered, nil)
+	var mismatch *digestMismatchError
+	ok.True(t, errors.As(err, &mismatch), ok.Sprintf("expected *digestMismatchError, got %v", err))
+	ok.Equal(t, mismatch.commitID, "c1")
+	ok.Equal(t, mismatch.expected, digestString(digest))
+
+	err = verifyDigest(&modulev1.Commit{Id: "c2"}, files, nil)
+	ok.Error(t, err)
+	ok.True(t, !errors.As(err, &mismatch), ok.Sprintf("a missing digest isn't a mismatch"))
+}
+
+func TestTransitiveDepDigests(t *testing.T) {
+	t.Parallel()
+
+	// root -> a -> c, root -> b -> c: c is reached twice but counted once.
+	digestOf := func(id string) *modulev1.Digest {
+		return &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte(id)}
+	}
+	edge := func(from, to string) *modulev1.Graph_Edge {
+		return &modulev1.Graph_Edge{
+			FromNode: &modulev1.Graph_Node{CommitId: from},
+			ToNode:   &modulev1.Graph_Node{CommitId: to},
+		}
+	}
+	graph := &modulev1.Graph{
+		Commits: []*modulev1.Commit{
+			{Id: "root", Digest: digestOf("root")},
+			{Id: "a", Digest: digestOf("a")},
+			{Id: "b", Digest: digestOf("b")},
+			{Id: "c", Digest: digestOf("c")},
+		},
+		Edges: []*modulev1.Graph_Edge{edge("root", "a"), edge("root", "b"), edge("a", "c"), edge("b", "c")},
+	}
+
+	var got []string
+	for _, d := range transitiveDepDigests("root", graph) {
+		got = append(got, string(d.Value))
+	}
+	ok.DeepEqual(t, got, []string{"a", "b", "c"})
+
+	got = nil
+	for _, d := range transitiveDepDigests("a", graph) {
+		got = append(got, string(d.Value))
+	}
+	ok.DeepEqual(t, got, []string{"c"})
+	ok.Zero(t, len(transitiveDepDigests("c", graph)))
+}
@@ -35,6 +35,9 @@ type docsPackage struct {
 	// "[type.url]{...}" form instead of falling back to raw type_url/value
 	// bytes. Shared across every docsPackage built from the same registry.
 	resolver *dynamicpb.Types
+	// blame, once the package has been blamed (see blame.go), holds the
+	// commit each field and enum value got its current definition in.
+	blame symbolBlames
 }
 
 func (p *docsPackage) FilterValue() string { return p.name }
@@ -227,14 +230,14 @@ func renderPackage(p *docsPackage, isDark bool) string {
 		rule(string(msg.Name()) + annotate(msg))
 		writeComment(msg)
 		b.WriteString("\n")
-		renderMessageFields(&b, msg, resolver, typeStyle, dimStyle, commentStyle)
+		renderMessageFields(&b, msg, resolver, p.blame, typeStyle, dimStyle, commentStyle)
 		b.WriteString("\n")
 		// Nested enum types, shown as subsections with dotted path.
-		renderNestedEnums(&b, msg, string(msg.Name()), resolver, dimStyle, commentStyle, nameStyle, ruleStyle, annotate, writeComment)
+		renderNestedEnums(&b, msg, string(msg.Name()), resolver, p.blame, dimStyle, commentStyle, nameStyle, ruleStyle, annotate, writeComment)
 		// Nested extend blocks, declared directly inside this message.
-		renderNestedExtensions(&b, msg, resolver, typeStyle, dimStyle, commentStyle)
+		renderNestedExtensions(&b, msg, resolver, p.blame, typeStyle, dimStyle, commentStyle)
 		// Nested message types, shown as subsections with dotted path.
-		renderNestedMessages(&b, msg, string(msg.Name()), resolver, typeStyle, dimStyle, commentStyle, nameStyle, ruleStyle, annotate, writeComment)
+		renderNestedMessages(&b, msg, string(msg.Name()), resolver, p.blame, typeStyle, dimStyle, commentStyle, nameStyle, ruleStyle, annotate, writeComment)
 	}
 
 	for _, enum := range p.enums {
@@ -243,7 +246,7 @@ func renderPackage(p *docsPackage, isDark bool) string {
 		b.WriteString("\n")
 		for i := range enum.Values().Len() {
 			v := enum.Values().Get(i)
-			b.WriteString(renderEnumValue(v, enumValueAliasOf(enum, v), resolver, dimStyle, commentStyle))
+			b.WriteString(renderEnumValue(v, enumValueAliasOf(enum, v), resolver, p.blame, dimStyle, commentStyle))
 		}
 		renderEnumReserved(&b, enum, dimStyle)
 		b.WriteString("\n")
@@ -259,7 +262,7 @@ func renderPackage(p *docsPackage, isDark bool) string {
 		writeComment(ext)
 		b.WriteString("\n")
 		b.WriteString(dimStyle.Render(fmt.Sprintf("extend %s {", ext.ContainingMessage().FullName())) + "\n")
-		b.WriteString("  " + renderField(ext, resolver, typeStyle, dimStyle, commentStyle))
+		b.WriteString("  " + renderField(ext, resolver, p.blame, typeStyle, dimStyle, commentStyle))
 		b.WriteString(dimStyle.Render("}") + "\n\n")
 	}
 
@@ -269,14 +272,14 @@ func renderPackage(p *docsPackage, isDark bool) string {
 // renderMessageFields renders a message's own fields, oneof blocks, reserved
 // ranges/names, and extension ranges — everything about the message except
 // its nested types.
-func renderMessageFields(b *strings.Builder, msg protoreflect.MessageDescriptor, resolver *dynamicpb.Types, typeStyle, dimStyle, commentStyle lipgloss.Style) {
+func renderMessageFields(b *strings.Builder, msg protoreflect.MessageDescriptor, resolver *dynamicpb.Types, blame symbolBlames, typeStyle, dimStyle, commentStyle lipgloss.Style) {
 	// Non-oneof fields first. proto3 `optional` fields compile to a hidden
 	// "synthetic" oneof containing just that field -- treat those as plain
 	// fields rather than surfacing the synthetic oneof as a visible block.
 	for i := range msg.Fields().Len() {
 		f := msg.Fields().Get(i)
 		if oneof := f.ContainingOneof(); oneof == nil || oneof.IsSynthetic() {
-			b.WriteString(renderField(f, resolver, typeStyle, dimStyle, commentStyle))
+			b.WriteString(renderField(f, resolver, blame, typeStyle, dimStyle, commentStyle))
 		}
 	}
 	// Oneof blocks.
@@ -296,7 +299,7 @@ func renderMessageFields(b *strings.Builder, msg protoreflect.MessageDescriptor,
 		}
 		b.WriteString(dimStyle.Render(header) + "\n")
 		for j := range oneof.Fields().Len() {
-			b.WriteString("  " + renderField(oneof.Fields().Get(j), resolver, typeStyle, dimStyle, commentStyle))
+			b.WriteString("  " + renderField(oneof.Fields().Get(j), resolver, blame, typeStyle, dimStyle, commentStyle))
 		}
 		b.WriteString(dimStyle.Render("}") + "\n")
 	}
@@ -373,6 +376,7 @@ func renderNestedEnums(
 	msg protoreflect.MessageDescriptor,
 	path string,
 	resolver *dynamicpb.Types,
+	blame symbolBlames,
 	dimStyle, commentStyle, nameStyle, ruleStyle lipgloss.Style,
 	annotateFn func(protoreflect.Descriptor) string,
 	writeCommentFn func(protoreflect.Descriptor),
@@ -387,7 +391,7 @@ func renderNestedEnums(
 		b.WriteString("\n")
 		for j := range enum.Values().Len() {
 			v := enum.Values().Get(j)
-			b.WriteString(renderEnumValue(v, enumValueAliasOf(enum, v), resolver, dimStyle, commentStyle))
+			b.WriteString(renderEnumValue(v, enumValueAliasOf(enum, v), resolver, blame, dimStyle, commentStyle))
 		}
 		renderEnumReserved(b, enum, dimStyle)
 		b.WriteString("\n")
@@ -395,11 +399,11 @@ func renderNestedEnums(
 }
 
 // renderNestedExtensions renders extend blocks declared directly inside msg.
-func renderNestedExtensions(b *strings.Builder, msg protoreflect.MessageDescriptor, resolver *dynamicpb.Types, typeStyle, dimStyle, commentStyle lipgloss.Style) {
+func renderNestedExtensions(b *strings.Builder, msg protoreflect.MessageDescriptor, resolver *dynamicpb.Types, blame symbolBlames, typeStyle, dimStyle, commentStyle lipgloss.Style) {
 	for i := range msg.Extensions().Len() {
 		ext := msg.Extensions().Get(i)
 		b.WriteString(dimStyle.Render(fmt.Sprintf("extend %s {", ext.ContainingMessage().FullName())) + "\n")
-		b.WriteString("  " + renderField(ext, resolver, typeStyle, dimStyle, commentStyle))
+		b.WriteString("  " + renderField(ext, resolver, blame, typeStyle, dimStyle, commentStyle))
 		b.WriteString(dimStyle.Render("}") + "\n\n")
 	}
 }
@@ -411,6 +415,7 @@ func renderNestedMessages(
 	msg protoreflect.MessageDescriptor,
 	path string,
 	resolver *dynamicpb.Types,
+	blame symbolBlames,
 	typeStyle, dimStyle, commentStyle, nameStyle, ruleStyle lipgloss.Style,
 	annotateFn func(protoreflect.Descriptor) string,
 	writeCommentFn func(protoreflect.Descriptor),
@@ -426,11 +431,11 @@ func renderNestedMessages(
 		b.WriteString(ruleStyle.Render(strings.Repeat("─", lipgloss.Width(headerText))) + "\n")
 		writeCommentFn(nested)
 		b.WriteString("\n")
-		renderMessageFields(b, nested, resolver, typeStyle, dimStyle, commentStyle)
+		renderMessageFields(b, nested, resolver, blame, typeStyle, dimStyle, commentStyle)
 		b.WriteString("\n")
-		renderNestedEnums(b, nested, subPath, resolver, dimStyle, commentStyle, nameStyle, ruleStyle, annotateFn, writeCommentFn)
-		renderNestedExtensions(b, nested, resolver, typeStyle, dimStyle, commentStyle)
-		renderNestedMessages(b, nested, subPath, resolver, typeStyle, dimStyle, commentStyle, nameStyle, ruleStyle, annotateFn, writeCommentFn)
+		renderNestedEnums(b, nested, subPath, resolver, blame, dimStyle, commentStyle, nameStyle, ruleStyle, annotateFn, writeCommentFn)
+		renderNestedExtensions(b, nested, resolver, blame, typeStyle, dimStyle, commentStyle)
+		renderNestedMessages(b, nested, subPath, resolver, blame, typeStyle, dimStyle, commentStyle, nameStyle, ruleStyle, annotateFn, writeCommentFn)
 	}
 }
 
@@ -477,7 +482,7 @@ func renderMethod(m protoreflect.MethodDescriptor, resolver *dynamicpb.Types, ty
 	return b.String()
 }
 
-func renderField(f protoreflect.FieldDescriptor, resolver *dynamicpb.Types, typeStyle, dimStyle, commentStyle lipgloss.Style) string {
+func renderField(f protoreflect.FieldDescriptor, resolver *dynamicpb.Types, blame symbolBlames, typeStyle, dimStyle, commentStyle lipgloss.Style) string {
 	var b strings.Builder
 	typeName := fieldTypeName(f)
 	if f.Kind() == protoreflect.GroupKind && f.ParentFile().Syntax() == protoreflect.Proto2 {
@@ -548,6 +553,7 @@ func renderField(f protoreflect.FieldDescriptor, resolver *dynamicpb.Types, type
 	if custom := customOptionsAnnotation(f.Options(), resolver); custom != "" {
 		line += "  " + dimStyle.Render(custom)
 	}
+	line += blame.annotation(f, dimStyle)
 	b.WriteString(line + "\n")
 	if c := leadingComment(f); c != "" {
 		for l := range strings.SplitSeq(c, "\n") {
@@ -568,7 +574,7 @@ func enumValueAliasOf(enum protoreflect.EnumDescriptor, v protoreflect.EnumValue
 	return string(canonical.Name())
 }
 
-func renderEnumValue(v protoreflect.EnumValueDescriptor, aliasOf string, resolver *dynamicpb.Types, dimStyle, commentStyle lipgloss.Style) string {
+func renderEnumValue(v protoreflect.EnumValueDescriptor, aliasOf string, resolver *dynamicpb.Types, blame symbolBlames, dimStyle, commentStyle lipgloss.Style) string {
 	var b strings.Builder
 	line := fmt.Sprintf("%s = %d", string(v.Name()), v.Number())
 	if aliasOf != "" {
@@ -580,6 +586,7 @@ func renderEnumValue(v protoreflect.EnumValueDescriptor, aliasOf string, resolve
 	if custom := customOptionsAnnotation(v.Options(), resolver); custom != "" {
 		line += "  " + dimStyle.Render(custom)
 	}
+	line += blame.annotation(v, dimStyle)
 	b.WriteString(line + "\n")
 	if c := leadingComment(v); c != "" {
 		for l := range strings.SplitSeq(c, "\n") {
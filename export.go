@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/httplb"
+)
+
+// exportedMsg and exportErrMsg carry the result of exportCommit. fromLabels
+// records whether the export was started from the labels tab rather than
+// the commit list, so the result is reported in the same place.
+type exportedMsg struct {
+	dir        string
+	files      int
+	fromLabels bool
+}
+
+type exportErrMsg struct {
+	err        error
+	fromLabels bool
+}
+
+func (e exportErrMsg) Error() string { return e.err.Error() }
+
+// exportDirName returns the directory the commit list and labels tab export
+// a commit's files to.
+func exportDirName(owner, module, commitID string) string {
+	return fmt.Sprintf("%s-%s-%s", owner, module, shortCommitID(commitID))
+}
+
+// exportCommit writes commitID's files, and its dependencies' if
+// includeDeps, to dir (see exportFiles), for the commit list and labels
+// tab's export actions.
+func (c *client) exportCommit(commitID string, includeDeps bool, dir string, fromLabels bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		files, err := c.exportFiles(ctx, commitID, includeDeps)
+		if err != nil {
+			return exportErrMsg{err, fromLabels}
+		}
+		if err := writeExport(dir, files); err != nil {
+			return exportErrMsg{err, fromLabels}
+		}
+		return exportedMsg{dir: dir, files: len(files), fromLabels: fromLabels}
+	}
+}
+
+// exportFiles fetches the files of commitID to export, as buf export would
+// lay them out: all of the commit's own files, plus, if includeDeps, the
+// .proto files of every commit in its transitive dependency graph -- their
+// buf.yaml, README and LICENSE files would only collide with the module's
+// own. Everything is downloaded with downloadVerified, so nothing that
+// doesn't match its digest is ever exported. A path two commits both have
+// with different content is an error rather than one silently winning.
+//
+// The graph is fetched even without includeDeps, as compileDocs fetches it:
+// the commit's digest covers its dependencies' digests, so it can't be
+// verified without them.
+func (c *client) exportFiles(ctx context.Context, commitID string, includeDeps bool) ([]*modulev1.File, error) {
+	graphResp, err := c.graphServiceClient.GetGraph(ctx, connect.NewRequest(&modulev1.GetGraphRequest{
+		ResourceRefs: []*modulev1.ResourceRef{{
+			Value: &modulev1.ResourceRef_Id{Id: commitID},
+		}},
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("getting dependency graph: %w", err)
+	}
+	graph := graphResp.Msg.Graph
+	commits := graph.Commits
+	if !includeDeps {
+		commits = slices.DeleteFunc(slices.Clone(commits), func(commit *modulev1.Commit) bool {
+			return commit.Id != commitID
+		})
+	}
+	if !slices.ContainsFunc(commits, func(commit *modulev1.Commit) bool { return commit.Id == commitID }) {
+		// A graph is supposed to include the commit it's for, but digests
+		// can't be verified without it, so don't export anything unverified
+		// on the strength of that.
+		return nil, fmt.Errorf("commit %s missing from its dependency graph", commitID)
+	}
+	filesByCommitID, err := c.downloadVerifiedCommits(ctx, graph, commits)
+	if err != nil {
+		return nil, err
+	}
+	return mergeExportFiles(commitID, commits, filesByCommitID)
+}
+
+// mergeExportFiles lays out the files of commits, by commit ID in
+// filesByCommitID, as exportFiles describes, sorted by path.
+func mergeExportFiles(commitID string, commits []*modulev1.Commit, filesByCommitID map[string][]*modulev1.File) ([]*modulev1.File, error) {
+	byPath := make(map[string]*modulev1.File)
+	var errs []error
+	for _, commit := range commits {
+		for _, file := range filesByCommitID[commit.Id] {
+			if commit.Id != commitID && path.Ext(file.Path) != ".proto" {
+				continue
+			}
+			if existing, ok := byPath[file.Path]; ok {
+				if !bytes.Equal(existing.Content, file.Content) {
+					errs = append(errs, fmt.Errorf("%s differs between dependencies", file.Path))
+				}
+				continue
+			}
+			byPath[file.Path] = file
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	files := make([]*modulev1.File, 0, len(byPath))
+	for _, file := range byPath {
+		files = append(files, file)
+	}
+	slices.SortFunc(files, func(a, b *modulev1.File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return files, nil
+}
+
+// writeExport writes files to dir, creating it and any directories their
+// paths need. Paths are opened within dir with os.Root, so a file can't be
+// written outside it however its path is spelled.
+func writeExport(dir string, files []*modulev1.File) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	for _, file := range files {
+		if parent := path.Dir(file.Path); parent != "." {
+			if err := root.MkdirAll(parent, 0o755); err != nil {
+				return fmt.Errorf("writing %s: %w", file.Path, err)
+			}
+		}
+		if err := root.WriteFile(file.Path, file.Content, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
+
+// runExport implements the headless "export" subcommand: it writes the
+// files of the commit a reference resolves to into a directory, then exits.
+func runExport(ctx context.Context, args []string) error {
+	var flags runFlags
+	var output string
+	var includeDeps bool
+	fs := newFlagSet("buftui export", &flags)
+	fs.StringVar(&output, "output", "", "Directory to write the files to (default: owner-module-commit)")
+	fs.StringVar(&output, "o", "", "Directory to write the files to (default: owner-module-commit)")
+	fs.BoolVar(&includeDeps, "deps", false, "Also write the .proto files of every transitive dependency")
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+	remote, token, reference, asOf, err := resolveConnection(flags)
+	if err != nil {
+		return err
+	}
+	if reference == nil {
+		return fmt.Errorf("export requires a reference (-r owner/module{:ref}{@time})")
+	}
+
+	httpClient := httplb.NewClient()
+	defer httpClient.Close()
+	c := newClient(httpClient, remote, token)
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+	commit, err := c.resolveReferenceCommit(ctx, reference, asOf)
+	if err != nil {
+		return err
+	}
+	files, err := c.exportFiles(ctx, commit.Id, includeDeps)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = exportDirName(reference.Owner, reference.Module, commit.Id)
+	}
+	if err := writeExport(output, files); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d files to %s\n", len(files), output)
+	return nil
+}
+
+// exportSelectedCommit starts exporting the commit selected in the commit
+// list or labels tab, with its dependencies if includeDeps, or returns nil
+// if neither is showing.
+func (m *model) exportSelectedCommit(includeDeps bool) tea.Cmd {
+	var commitID string
+	var fromLabels bool
+	switch {
+	case m.state == modelStateBrowsingCommits && m.historyQuery == "":
+		commit, ok := m.commitList.SelectedItem().(*commit)
+		if !ok {
+			return nil
+		}
+		commitID = commit.underlying.Id
+	case m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabLabels:
+		label, ok := m.labelsList.SelectedItem().(*labelItem)
+		if !ok {
+			return nil
+		}
+		commitID, fromLabels = label.underlying.CommitId, true
+	default:
+		return nil
+	}
+	dir := exportDirName(m.currentOwner, m.currentModule, commitID)
+	status := m.commitList.NewStatusMessage
+	if fromLabels {
+		status = m.labelsList.NewStatusMessage
+	}
+	what := "files"
+	if includeDeps {
+		what = "files and dependencies"
+	}
+	return tea.Batch(
+		status(fmt.Sprintf("exporting %s of %s to %s", what, shortCommitID(commitID), dir)),
+		m.client.exportCommit(commitID, includeDeps, dir, fromLabels),
+	)
+}
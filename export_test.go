@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"go.vanburen.xyz/ok"
+)
+
+func TestMergeExportFiles(t *testing.T) {
+	t.Parallel()
+
+	commits := []*modulev1.Commit{{Id: "root"}, {Id: "dep1"}, {Id: "dep2"}}
+	files := map[string][]*modulev1.File{
+		"root": {
+			{Path: "buf.yaml", Content: []byte("root")},
+			{Path: "acme/v1/acme.proto", Content: []byte("acme")},
+		},
+		"dep1": {
+			{Path: "buf.yaml", Content: []byte("dep1")},
+			{Path: "LICENSE", Content: []byte("dep1")},
+			{Path: "google/type/date.proto", Content: []byte("date")},
+		},
+		// The same file vendored twice is fine.
+		"dep2": {
+			{Path: "google/type/date.proto", Content: []byte("date")},
+		},
+	}
+	merged, err := mergeExportFiles("root", commits, files)
+	ok.MustNoError(t, err)
+	var paths []string
+	for _, file := range merged {
+		paths = append(paths, file.Path+"="+string(file.Content))
+	}
+	ok.Equal(t, strings.Join(paths, " "), "acme/v1/acme.proto=acme buf.yaml=root google/type/date.proto=date")
+
+	files["dep2"] = []*modulev1.File{{Path: "google/type/date.proto", Content: []byte("other")}}
+	_, err = mergeExportFiles("root", commits, files)
+	ok.True(t, err != nil && strings.Contains(err.Error(), "google/type/date.proto differs"), ok.Sprintf("expected a conflict, got %v", err))
+}
+
+func TestWriteExport(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "export")
+	ok.MustNoError(t, writeExport(dir, []*modulev1.File{
+		{Path: "buf.yaml", Content: []byte("version: v2\n")},
+		{Path: "acme/v1/acme.proto", Content: []byte("syntax = \"proto3\";\n")},
+	}))
+	data, err := os.ReadFile(filepath.Join(dir, "acme", "v1", "acme.proto"))
+	ok.MustNoError(t, err)
+	ok.Equal(t, string(data), "syntax = \"proto3\";\n")
+
+	// Nothing gets written outside the directory, whatever the path says.
+	err = writeExport(dir, []*modulev1.File{{Path: "../escaped.proto", Content: []byte("x")}})
+	ok.Error(t, err)
+	_, err = os.Stat(filepath.Join(filepath.Dir(dir), "escaped.proto"))
+	ok.True(t, os.IsNotExist(err))
+}
+
+func TestExportDirName(t *testing.T) {
+	t.Parallel()
+
+	ok.Equal(t, exportDirName("bufbuild", "registry", "abc123def4567890"), "bufbuild-registry-abc123def456")
+}
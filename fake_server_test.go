@@ -3,22 +3,32 @@ package main
 import (
 	"connectrpc.com/connect"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"testing/synctest"
 	"time"
 
+	infov1 "buf.build/gen/go/bufbuild/bufplugin/protocolbuffers/go/buf/plugin/info/v1"
 	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/module/v1/modulev1connect"
+	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/owner/v1/ownerv1connect"
+	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/plugin/v1beta1/pluginv1beta1connect"
 	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
+	pluginv1beta1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/plugin/v1beta1"
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/list"
 	"charm.land/bubbles/v2/spinner"
+	"charm.land/bubbles/v2/tree"
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 	"go.vanburen.xyz/ok"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -37,6 +47,16 @@ func (f *fakeModuleServiceHandler) ListModules(
 	req *connect.Request[modulev1.ListModulesRequest],
 ) (*connect.Response[modulev1.ListModulesResponse], error) {
 	sleepOrDone(ctx, f.delay)
+	switch req.Msg.OwnerRefs[0].GetName() {
+	case "bigowner":
+		return connect.NewResponse(bigOwnerModulesPage(req.Msg.PageToken)), nil
+	case "flakyowner":
+		// bigowner's modules, except that the third page won't load.
+		if req.Msg.PageToken == "20" {
+			return nil, connect.NewError(connect.CodeUnavailable, errors.New("try again later"))
+		}
+		return connect.NewResponse(bigOwnerModulesPage(req.Msg.PageToken)), nil
+	}
 	modules := []*modulev1.Module{
 		{
 			Id:          "mod1",
@@ -66,6 +86,86 @@ func (f *fakeModuleServiceHandler) ListModules(
 	return response, nil
 }
 
+// UpdateModules applies each change to a module of that name, refusing any
+// to bufbuild/protovalidate.
+func (f *fakeModuleServiceHandler) UpdateModules(
+	ctx context.Context,
+	req *connect.Request[modulev1.UpdateModulesRequest],
+) (*connect.Response[modulev1.UpdateModulesResponse], error) {
+	var modules []*modulev1.Module
+	for _, value := range req.Msg.Values {
+		name := value.ModuleRef.GetName()
+		if name.Module == "bufbuild/protovalidate" {
+			return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("not an admin of %s", name.Module))
+		}
+		module := &modulev1.Module{
+			Name:       name.Module,
+			Visibility: modulev1.ModuleVisibility_MODULE_VISIBILITY_PUBLIC,
+			State:      modulev1.ModuleState_MODULE_STATE_ACTIVE,
+			CreateTime: timestamppb.New(time.Now().Add(-24 * time.Hour)),
+			UpdateTime: timestamppb.Now(),
+		}
+		if value.Visibility != nil {
+			module.Visibility = *value.Visibility
+		}
+		if value.State != nil {
+			module.State = *value.State
+		}
+		module.Description = value.GetDescription()
+		module.Url = value.GetUrl()
+		module.DefaultLabelName = value.GetDefaultLabelName()
+		modules = append(modules, module)
+	}
+	return connect.NewResponse(&modulev1.UpdateModulesResponse{Modules: modules}), nil
+}
+
+// bigOwnerModulesPage serves bigowner's 25 modules, mod00 to mod24, in
+// pages of 10 whose tokens are the index of their first module.
+func bigOwnerModulesPage(pageToken string) *modulev1.ListModulesResponse {
+	const total, size = 25, 10
+	start, _ := strconv.Atoi(pageToken)
+	response := &modulev1.ListModulesResponse{}
+	for i := start; i < min(start+size, total); i++ {
+		response.Modules = append(response.Modules, &modulev1.Module{
+			Id:         fmt.Sprintf("big%02d", i),
+			Name:       fmt.Sprintf("mod%02d", i),
+			CreateTime: timestamppb.New(time.Now().Add(-time.Duration(i) * time.Hour)),
+			UpdateTime: timestamppb.New(time.Now()),
+		})
+	}
+	if start+size < total {
+		response.NextPageToken = strconv.Itoa(start + size)
+	}
+	return response
+}
+
+// GetModules returns a module for any name except "missing".
+func (f *fakeModuleServiceHandler) GetModules(
+	ctx context.Context,
+	req *connect.Request[modulev1.GetModulesRequest],
+) (*connect.Response[modulev1.GetModulesResponse], error) {
+	var modules []*modulev1.Module
+	for _, ref := range req.Msg.ModuleRefs {
+		name := ref.GetName().GetModule()
+		if name == "missing" {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("module %q not found", name))
+		}
+		modules = append(modules, &modulev1.Module{
+			Id:               "mod1",
+			Name:             name,
+			OwnerId:          "owner1",
+			Description:      "The Buf registry module",
+			Url:              "https://github.com/bufbuild/registry",
+			Visibility:       modulev1.ModuleVisibility_MODULE_VISIBILITY_PUBLIC,
+			State:            modulev1.ModuleState_MODULE_STATE_DEPRECATED,
+			DefaultLabelName: "main",
+			CreateTime:       timestamppb.New(time.Now().Add(-24 * time.Hour)),
+			UpdateTime:       timestamppb.New(time.Now()),
+		})
+	}
+	return connect.NewResponse(&modulev1.GetModulesResponse{Modules: modules}), nil
+}
+
 // fakeCommitServiceHandler implements the CommitService for testing.
 type fakeCommitServiceHandler struct {
 	modulev1connect.UnimplementedCommitServiceHandler
@@ -78,12 +178,14 @@ func (f *fakeCommitServiceHandler) ListCommits(
 	commits := []*modulev1.Commit{
 		{
 			Id:               "abc123def456",
+			CreatedByUserId:  "user1",
 			CreateTime:       timestamppb.New(time.Now().Add(-1 * time.Hour)),
 			SourceControlUrl: "https://github.com/bufbuild/registry/commit/abc123def4567890",
 		},
 		{
-			Id:         "def456ghi789",
-			CreateTime: timestamppb.New(time.Now().Add(-2 * time.Hour)),
+			Id:              "def456ghi789",
+			CreatedByUserId: "user2",
+			CreateTime:      timestamppb.New(time.Now().Add(-2 * time.Hour)),
 		},
 		{
 			Id:         "ghi789jkl012",
@@ -106,10 +208,25 @@ func (f *fakeDownloadServiceHandler) Download(
 	ctx context.Context,
 	req *connect.Request[modulev1.DownloadRequest],
 ) (*connect.Response[modulev1.DownloadResponse], error) {
-	contents := []*modulev1.DownloadResponse_Content{
-		{
+	// Every commit has the same files, except that the README grows with
+	// each of the commits ListCommits returns, for history searches.
+	readmes := map[string]string{
+		"ghi789jkl012": "# Registry\n",
+		"def456ghi789": "# Registry\n\nThe registry module.\n",
+	}
+	var contents []*modulev1.DownloadResponse_Content
+	for _, value := range req.Msg.Values {
+		id := value.GetResourceRef().GetId()
+		if id == "" {
+			id = "abc123def456"
+		}
+		readme, ok := readmes[id]
+		if !ok {
+			readme = "# Registry\n\nThe Buf registry module.\n"
+		}
+		contents = append(contents, &modulev1.DownloadResponse_Content{
 			Commit: &modulev1.Commit{
-				Id:         "abc123def456",
+				Id:         id,
 				CreateTime: timestamppb.New(time.Now().Add(-1 * time.Hour)),
 			},
 			Files: []*modulev1.File{
@@ -123,10 +240,10 @@ func (f *fakeDownloadServiceHandler) Download(
 				},
 				{
 					Path:    "README.md",
-					Content: []byte("# Registry\n\nThe Buf registry module.\n"),
+					Content: []byte(readme),
 				},
 			},
-		},
+		})
 	}
 
 	response := connect.NewResponse(&modulev1.DownloadResponse{
@@ -195,6 +312,247 @@ func (f *fakeGraphServiceHandler) GetGraph(
 	}), nil
 }
 
+// fakeLabelServiceHandler implements the LabelService for testing: the
+// "main" label has pointed at each of the fake commits in turn, and now
+// points at the newest.
+type fakeLabelServiceHandler struct {
+	modulev1connect.UnimplementedLabelServiceHandler
+}
+
+func (f *fakeLabelServiceHandler) ListLabels(
+	ctx context.Context,
+	req *connect.Request[modulev1.ListLabelsRequest],
+) (*connect.Response[modulev1.ListLabelsResponse], error) {
+	unarchived := []*modulev1.Label{
+		{
+			Id:         "label1",
+			Name:       "main",
+			CommitId:   "abc123def456",
+			CreateTime: timestamppb.New(time.Now().Add(-3 * time.Hour)),
+			UpdateTime: timestamppb.New(time.Now().Add(-1 * time.Hour)),
+		},
+	}
+	archived := []*modulev1.Label{
+		{
+			Id:          "label2",
+			Name:        "v0.1.0",
+			CommitId:    "ghi789jkl012",
+			CreateTime:  timestamppb.New(time.Now().Add(-3 * time.Hour)),
+			UpdateTime:  timestamppb.New(time.Now().Add(-3 * time.Hour)),
+			ArchiveTime: timestamppb.New(time.Now().Add(-2 * time.Hour)),
+		},
+	}
+	var labels []*modulev1.Label
+	switch req.Msg.ArchiveFilter {
+	case modulev1.ListLabelsRequest_ARCHIVE_FILTER_ARCHIVED_ONLY:
+		labels = archived
+	case modulev1.ListLabelsRequest_ARCHIVE_FILTER_ALL:
+		labels = slices.Concat(unarchived, archived)
+	default:
+		labels = unarchived
+	}
+	if commitID := req.Msg.ResourceRef.GetId(); commitID != "" {
+		labels = slices.DeleteFunc(slices.Clone(labels), func(label *modulev1.Label) bool {
+			return label.CommitId != commitID
+		})
+	}
+	return connect.NewResponse(&modulev1.ListLabelsResponse{Labels: labels}), nil
+}
+
+// GetLabels returns "main" and the archived "v0.1.0", as ListLabels lists
+// them; any other label isn't found.
+func (f *fakeLabelServiceHandler) GetLabels(
+	ctx context.Context,
+	req *connect.Request[modulev1.GetLabelsRequest],
+) (*connect.Response[modulev1.GetLabelsResponse], error) {
+	all, err := f.ListLabels(ctx, connect.NewRequest(&modulev1.ListLabelsRequest{
+		ArchiveFilter: modulev1.ListLabelsRequest_ARCHIVE_FILTER_ALL,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	var labels []*modulev1.Label
+	for _, ref := range req.Msg.LabelRefs {
+		name := ref.GetName().GetLabel()
+		i := slices.IndexFunc(all.Msg.Labels, func(label *modulev1.Label) bool { return label.Name == name })
+		if i < 0 {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("label %q not found", name))
+		}
+		labels = append(labels, all.Msg.Labels[i])
+	}
+	return connect.NewResponse(&modulev1.GetLabelsResponse{Labels: labels}), nil
+}
+
+func (f *fakeLabelServiceHandler) ListLabelHistory(
+	ctx context.Context,
+	req *connect.Request[modulev1.ListLabelHistoryRequest],
+) (*connect.Response[modulev1.ListLabelHistoryResponse], error) {
+	if name := req.Msg.LabelRef.GetName().GetLabel(); name != "main" {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("label %q not found", name))
+	}
+	return connect.NewResponse(&modulev1.ListLabelHistoryResponse{
+		Values: []*modulev1.ListLabelHistoryResponse_Value{
+			{Commit: &modulev1.Commit{Id: "abc123def456", CreatedByUserId: "user1", CreateTime: timestamppb.New(time.Now().Add(-1 * time.Hour))}},
+			{Commit: &modulev1.Commit{Id: "def456ghi789", CreatedByUserId: "user2", CreateTime: timestamppb.New(time.Now().Add(-2 * time.Hour))}},
+			{Commit: &modulev1.Commit{Id: "ghi789jkl012", CreateTime: timestamppb.New(time.Now().Add(-3 * time.Hour))}},
+		},
+	}), nil
+}
+
+// CreateOrUpdateLabels, ArchiveLabels and UnarchiveLabels succeed, except
+// on the "readonly" module, where the caller isn't permitted to write.
+func (f *fakeLabelServiceHandler) CreateOrUpdateLabels(
+	ctx context.Context,
+	req *connect.Request[modulev1.CreateOrUpdateLabelsRequest],
+) (*connect.Response[modulev1.CreateOrUpdateLabelsResponse], error) {
+	var labels []*modulev1.Label
+	for _, value := range req.Msg.Values {
+		if err := checkLabelWritable(value.LabelRef); err != nil {
+			return nil, err
+		}
+		labels = append(labels, &modulev1.Label{Name: value.LabelRef.GetName().GetLabel(), CommitId: value.CommitId})
+	}
+	return connect.NewResponse(&modulev1.CreateOrUpdateLabelsResponse{Labels: labels}), nil
+}
+
+func (f *fakeLabelServiceHandler) ArchiveLabels(
+	ctx context.Context,
+	req *connect.Request[modulev1.ArchiveLabelsRequest],
+) (*connect.Response[modulev1.ArchiveLabelsResponse], error) {
+	for _, ref := range req.Msg.LabelRefs {
+		if err := checkLabelWritable(ref); err != nil {
+			return nil, err
+		}
+	}
+	return connect.NewResponse(&modulev1.ArchiveLabelsResponse{}), nil
+}
+
+func (f *fakeLabelServiceHandler) UnarchiveLabels(
+	ctx context.Context,
+	req *connect.Request[modulev1.UnarchiveLabelsRequest],
+) (*connect.Response[modulev1.UnarchiveLabelsResponse], error) {
+	for _, ref := range req.Msg.LabelRefs {
+		if err := checkLabelWritable(ref); err != nil {
+			return nil, err
+		}
+	}
+	return connect.NewResponse(&modulev1.UnarchiveLabelsResponse{}), nil
+}
+
+func checkLabelWritable(ref *modulev1.LabelRef) error {
+	if ref.GetName().GetModule() == "readonly" {
+		return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("cannot write to module %q", ref.GetName().GetModule()))
+	}
+	return nil
+}
+
+// fakeOwnerServiceHandler implements the OwnerService for testing, knowing
+// two users by ID and the bufbuild organization by name.
+type fakeOwnerServiceHandler struct {
+	ownerv1connect.UnimplementedOwnerServiceHandler
+}
+
+func (f *fakeOwnerServiceHandler) GetOwners(
+	ctx context.Context,
+	req *connect.Request[ownerv1.GetOwnersRequest],
+) (*connect.Response[ownerv1.GetOwnersResponse], error) {
+	users := map[string]string{"user1": "alice", "user2": "bob"}
+	var owners []*ownerv1.Owner
+	for _, ref := range req.Msg.OwnerRefs {
+		if ref.GetName() == "bufbuild" {
+			owners = append(owners, &ownerv1.Owner{
+				Value: &ownerv1.Owner_Organization{Organization: &ownerv1.Organization{
+					Id:                 "org1",
+					Name:               "bufbuild",
+					Description:        "The Buf team.",
+					Url:                "https://buf.build",
+					Visibility:         ownerv1.OrganizationVisibility_ORGANIZATION_VISIBILITY_PUBLIC,
+					VerificationStatus: ownerv1.OrganizationVerificationStatus_ORGANIZATION_VERIFICATION_STATUS_OFFICIAL,
+					CreateTime:         timestamppb.New(time.Now().Add(-365 * 24 * time.Hour)),
+				}},
+			})
+			continue
+		}
+		name, found := users[ref.GetId()]
+		if !found {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("owner %q not found", ref.GetId()))
+		}
+		owners = append(owners, &ownerv1.Owner{
+			Value: &ownerv1.Owner_User{User: &ownerv1.User{Id: ref.GetId(), Name: name}},
+		})
+	}
+	return connect.NewResponse(&ownerv1.GetOwnersResponse{Owners: owners}), nil
+}
+
+// fakePluginServiceHandler, fakePluginCommitServiceHandler and
+// fakePluginLabelServiceHandler serve bufbuild's one plugin, buf-plugin-rpc,
+// which has two commits, the newer labelled v1.1.0 and main.
+type fakePluginServiceHandler struct {
+	pluginv1beta1connect.UnimplementedPluginServiceHandler
+}
+
+func (f *fakePluginServiceHandler) ListPlugins(
+	ctx context.Context,
+	req *connect.Request[pluginv1beta1.ListPluginsRequest],
+) (*connect.Response[pluginv1beta1.ListPluginsResponse], error) {
+	if req.Msg.OwnerRefs[0].GetName() != "bufbuild" {
+		return connect.NewResponse(&pluginv1beta1.ListPluginsResponse{}), nil
+	}
+	return connect.NewResponse(&pluginv1beta1.ListPluginsResponse{
+		Plugins: []*pluginv1beta1.Plugin{{
+			Id:          "plugin1",
+			Name:        "buf-plugin-rpc",
+			Type:        pluginv1beta1.PluginType_PLUGIN_TYPE_CHECK,
+			Visibility:  pluginv1beta1.PluginVisibility_PLUGIN_VISIBILITY_PUBLIC,
+			State:       pluginv1beta1.PluginState_PLUGIN_STATE_ACTIVE,
+			Description: "Lint rules for RPCs.",
+			SourceUrl:   "https://github.com/bufbuild/buf-plugin-rpc",
+			UpdateTime:  timestamppb.New(time.Now().Add(-time.Hour)),
+		}},
+	}), nil
+}
+
+type fakePluginCommitServiceHandler struct {
+	pluginv1beta1connect.UnimplementedCommitServiceHandler
+}
+
+func (f *fakePluginCommitServiceHandler) ListCommits(
+	ctx context.Context,
+	req *connect.Request[pluginv1beta1.ListCommitsRequest],
+) (*connect.Response[pluginv1beta1.ListCommitsResponse], error) {
+	return connect.NewResponse(&pluginv1beta1.ListCommitsResponse{
+		Commits: []*pluginv1beta1.Commit{
+			{
+				Id:              "p2",
+				CreatedByUserId: "user1",
+				CreateTime:      timestamppb.New(time.Now().Add(-time.Hour)),
+				PluginInfo: &infov1.PluginInfo{
+					Documentation: "Checks RPC naming.\nAnd more.",
+					License:       &infov1.License{SpdxLicenseId: "Apache-2.0"},
+				},
+			},
+			{Id: "p1", CreatedByUserId: "user2", CreateTime: timestamppb.New(time.Now().Add(-48 * time.Hour))},
+		},
+	}), nil
+}
+
+type fakePluginLabelServiceHandler struct {
+	pluginv1beta1connect.UnimplementedLabelServiceHandler
+}
+
+func (f *fakePluginLabelServiceHandler) ListLabels(
+	ctx context.Context,
+	req *connect.Request[pluginv1beta1.ListLabelsRequest],
+) (*connect.Response[pluginv1beta1.ListLabelsResponse], error) {
+	return connect.NewResponse(&pluginv1beta1.ListLabelsResponse{
+		Labels: []*pluginv1beta1.Label{
+			{Name: "main", CommitId: "p2"},
+			{Name: "v1.1.0", CommitId: "p2"},
+			{Name: "v1.0.0", CommitId: "p1", ArchiveTime: timestamppb.Now()},
+		},
+	}), nil
+}
+
 // startFakeServer creates an in-memory Buf registry service and returns a client.
 func startFakeServer(t *testing.T) *client {
 	t.Helper()
@@ -206,16 +564,56 @@ func startFakeServer(t *testing.T) *client {
 	mux.Handle(modulev1connect.NewDownloadServiceHandler(&fakeDownloadServiceHandler{}))
 	mux.Handle(modulev1connect.NewResourceServiceHandler(&fakeResourceServiceHandler{}))
 	mux.Handle(modulev1connect.NewGraphServiceHandler(&fakeGraphServiceHandler{}))
+	mux.Handle(modulev1connect.NewLabelServiceHandler(&fakeLabelServiceHandler{}))
+	mux.Handle(ownerv1connect.NewOwnerServiceHandler(&fakeOwnerServiceHandler{}))
+	mux.Handle(pluginv1beta1connect.NewPluginServiceHandler(&fakePluginServiceHandler{}))
+	mux.Handle(pluginv1beta1connect.NewCommitServiceHandler(&fakePluginCommitServiceHandler{}))
+	mux.Handle(pluginv1beta1connect.NewLabelServiceHandler(&fakePluginLabelServiceHandler{}))
+	mux.HandleFunc("GET /gen/", fakeSDKRegistryHandler)
 
 	httpClient := inMemoryClient(t, mux)
 
 	// Return a client with all services
 	return &client{
-		moduleServiceClient:   modulev1connect.NewModuleServiceClient(httpClient, "https://example.com"),
-		commitServiceClient:   modulev1connect.NewCommitServiceClient(httpClient, "https://example.com"),
-		downloadServiceClient: modulev1connect.NewDownloadServiceClient(httpClient, "https://example.com"),
-		resourceServiceClient: modulev1connect.NewResourceServiceClient(httpClient, "https://example.com"),
-		graphServiceClient:    modulev1connect.NewGraphServiceClient(httpClient, "https://example.com"),
+		pluginServiceClient:       pluginv1beta1connect.NewPluginServiceClient(httpClient, "https://example.com"),
+		pluginCommitServiceClient: pluginv1beta1connect.NewCommitServiceClient(httpClient, "https://example.com"),
+		pluginLabelServiceClient:  pluginv1beta1connect.NewLabelServiceClient(httpClient, "https://example.com"),
+		httpClient:                httpClient,
+		address:                   "https://example.com",
+		moduleServiceClient:       modulev1connect.NewModuleServiceClient(httpClient, "https://example.com"),
+		commitServiceClient:       modulev1connect.NewCommitServiceClient(httpClient, "https://example.com"),
+		downloadServiceClient:     modulev1connect.NewDownloadServiceClient(httpClient, "https://example.com"),
+		resourceServiceClient:     modulev1connect.NewResourceServiceClient(httpClient, "https://example.com"),
+		graphServiceClient:        modulev1connect.NewGraphServiceClient(httpClient, "https://example.com"),
+		labelServiceClient:        modulev1connect.NewLabelServiceClient(httpClient, "https://example.com"),
+		ownerServiceClient:        ownerv1connect.NewOwnerServiceClient(httpClient, "https://example.com"),
+	}
+}
+
+// fakeSDKRegistryHandler serves the latest generated SDK versions of
+// bufbuild/registry from each package registry, with grpc/java never
+// generated.
+func fakeSDKRegistryHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.EscapedPath() {
+	case "/gen/go/bufbuild/registry/protocolbuffers/go/@latest":
+		fmt.Fprint(w, `{"Version":"v1.36.11-20260713175918-10d915f5b43b.1","Time":"2026-07-13T17:59:18Z"}`)
+	case "/gen/go/bufbuild/registry/connectrpc/go/@latest":
+		fmt.Fprint(w, `{"Version":"v1.20.0-20260713175918-10d915f5b43b.1"}`)
+	case "/gen/go/bufbuild/registry/grpc/go/@latest":
+		fmt.Fprint(w, `{"Version":"v1.6.0-20260713175918-10d915f5b43b.2"}`)
+	case "/gen/npm/v1/@buf%2fbufbuild_registry.bufbuild_es":
+		fmt.Fprint(w, `{"name":"@buf/bufbuild_registry.bufbuild_es","dist-tags":{"latest":"2.10.2-20260713175918-10d915f5b43b.1"}}`)
+	case "/gen/maven/build/buf/gen/bufbuild_registry_protocolbuffers_java/maven-metadata.xml":
+		fmt.Fprint(w, `<metadata><versioning><latest>33.1.0.1.20260713175918.10d915f5b43b</latest><release>33.1.0.1.20260713175918.10d915f5b43b</release></versioning></metadata>`)
+	case "/gen/python/bufbuild-registry-protocolbuffers-python/":
+		fmt.Fprint(w, `<html><body>
+<a href="bufbuild_registry_protocolbuffers_python-33.1.0.1.20260713175918%2B10d915f5b43b-py3-none-any.whl">a</a>
+<a href="bufbuild_registry_protocolbuffers_python-32.0.0.1.20250101000000%2B0123456789ab-py3-none-any.whl">b</a>
+</body></html>`)
+	case "/gen/python/bufbuild-registry-grpc-python/":
+		fmt.Fprint(w, `<a href="bufbuild_registry_grpc_python-1.76.0.1.20260713175918+10d915f5b43b-py3-none-any.whl">a</a>`)
+	default:
+		http.NotFound(w, r)
 	}
 }
 
@@ -270,6 +668,7 @@ func newTestModel(c *client) model {
 
 	commitList := list.New(nil, delegate, 20, 20)
 	commitList.SetShowHelp(false)
+	commitList.Filter = commitFilter
 
 	commitFilesList := list.New(nil, delegate, 20, 20)
 	commitFilesList.SetShowHelp(false)
@@ -277,24 +676,74 @@ func newTestModel(c *client) model {
 	docsList := list.New(nil, delegate, 20, 20)
 	docsList.SetShowHelp(false)
 
+	labelsList := list.New(nil, delegate, 20, 20)
+	labelsList.SetShowHelp(false)
+
+	labelHistoryList := list.New(nil, delegate, 20, 20)
+	labelHistoryList.SetShowHelp(false)
+
+	sdkList := list.New(nil, delegate, 20, 20)
+	sdkList.SetShowHelp(false)
+
+	pluginList := list.New(nil, delegate, 20, 20)
+	pluginList.SetShowHelp(false)
+
+	pluginCommitList := list.New(nil, delegate, 20, 20)
+	pluginCommitList.SetShowHelp(false)
+
+	activityList := list.New(nil, delegate, 20, 20)
+	activityList.SetShowHelp(false)
+	activityList.Filter = commitFilter
+
+	filesTree := tree.New(nil, 0, 0)
+	filesTree.SetShowHelp(false)
+
+	grepList := list.New(nil, delegate, 20, 20)
+	grepList.SetShowHelp(false)
+	grepList.SetShowTitle(false)
+	grepList.SetShowStatusBar(false)
+
+	historyList := list.New(nil, delegate, 20, 20)
+	historyList.SetShowHelp(false)
+	historyList.SetShowTitle(false)
+	historyList.SetShowStatusBar(false)
+
 	return model{
-		state:            modelStateNavigating,
-		spinner:          spinner.New(spinner.WithSpinner(spinner.Dot)),
-		client:           c,
-		help:             help.New(),
-		keys:             keys,
-		currentReference: nil,
-		navigateInput:    newNavigateInput(),
-		docsSearchInput:  newDocsSearchInput(),
-		docsMatchIdx:     -1,
-		remote:           "buf.build",
-		fileViewport:     viewport.New(),
-		docsViewport:     viewport.New(),
-
-		moduleList:      moduleList,
-		commitList:      commitList,
-		commitFilesList: commitFilesList,
-		docsList:        docsList,
+		state:              modelStateNavigating,
+		spinner:            spinner.New(spinner.WithSpinner(spinner.Dot)),
+		client:             c,
+		help:               help.New(),
+		keys:               keys,
+		currentReference:   nil,
+		navigateInput:      newNavigateInput(),
+		docsSearchInput:    newDocsSearchInput(),
+		descriptorSetInput: newDescriptorSetInput(),
+		labelNameInput:     newLabelNameInput(),
+		moduleEditInput:    newModuleEditInput(),
+		grepInput:          newGrepInput(),
+		historyInput:       newHistoryInput(),
+		fileSearchInput:    newFileSearchInput(),
+		fileGotoInput:      newFileGotoInput(),
+		diffInput:          newDiffInput(),
+		docsMatchIdx:       -1,
+		fileMatchIdx:       -1,
+		remote:             "buf.build",
+		fileViewport:       viewport.New(),
+		docsViewport:       viewport.New(),
+
+		moduleList:       moduleList,
+		commitList:       commitList,
+		commitFilesList:  commitFilesList,
+		filesTree:        filesTree,
+		grepList:         grepList,
+		historyList:      historyList,
+		docsList:         docsList,
+		labelsList:       labelsList,
+		labelHistoryList: labelHistoryList,
+		sdkList:          sdkList,
+		pluginList:       pluginList,
+		pluginCommitList: pluginCommitList,
+		activityList:     activityList,
 	}
 }
 
@@ -333,8 +782,8 @@ func TestListModulesCommand(t *testing.T) {
 	// Should return a modulesMsg
 	modules, isModules := msg.(modulesMsg)
 	ok.True(t, isModules, ok.Sprintf("expected modulesMsg, got %T", msg))
-	ok.True(t, len(modules) > 0, ok.Sprintf("expected modules, got %d", len(modules)))
-	ok.Equal(t, modules[0].Name, "bufbuild/registry")
+	ok.True(t, len(modules.modules) > 0, ok.Sprintf("expected modules, got %d", len(modules.modules)))
+	ok.Equal(t, modules.modules[0].Name, "bufbuild/registry")
 }
 
 // TestListCommitsCommand tests the listCommits client command.
@@ -440,7 +889,7 @@ func TestModuleFilteringNoOSCCodes(t *testing.T) {
 	// Populate the module list and currentModules (both are checked in View).
 	mod1 := &modulev1.Module{Name: "registry"}
 	mod2 := &modulev1.Module{Name: "protovalidate"}
-	m.currentModules = modulesMsg{mod1, mod2}
+	m.currentModules = []*modulev1.Module{mod1, mod2}
 	m.moduleList.SetItems([]list.Item{
 		&module{underlying: mod1, remote: "buf.build", owner: "bufbuild"},
 		&module{underlying: mod2, remote: "buf.build", owner: "bufbuild"},
@@ -802,3 +1251,539 @@ func inMemoryClient(t *testing.T, mux http.Handler) *http.Client {
 	t.Cleanup(server.CloseClientConnections)
 	return server.Client()
 }
+
+// TestListLabelHistoryCommand tests the listLabelHistory client command,
+// including resolving who pushed each commit.
+func TestListLabelHistoryCommand(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+
+	msg := c.listLabelHistory("bufbuild", "registry", "main", "")()
+	history, isHistory := msg.(labelHistoryMsg)
+	ok.True(t, isHistory, ok.Sprintf("expected labelHistoryMsg, got %T", msg))
+	ok.Equal(t, history.label, "main")
+	ok.Equal(t, len(history.commits), 3)
+	ok.Equal(t, history.commits[0].Id, "abc123def456")
+	ok.Equal(t, history.authors["user1"], "alice")
+	ok.Equal(t, history.authors["user2"], "bob")
+
+	msg = c.listLabelHistory("bufbuild", "registry", "main", "page2")()
+	_, isMore := msg.(moreLabelHistoryMsg)
+	ok.True(t, isMore, ok.Sprintf("expected moreLabelHistoryMsg for a later page, got %T", msg))
+
+	msg = c.listLabelHistory("bufbuild", "registry", "missing", "")()
+	_, isErr := msg.(errMsg)
+	ok.True(t, isErr, ok.Sprintf("expected errMsg, got %T", msg))
+}
+
+// TestLabelHistory_OpenAndJump walks the Labels tab flow: entering a label
+// opens its history, and entering a historical commit loads that commit.
+func TestLabelHistory_OpenAndJump(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.state = modelStateBrowsingCommitContents
+	m.activeCommitTab = commitTabLabels
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	m.currentCommitID = "abc123def456"
+
+	updated, _ := m.Update(c.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter)())
+	m = updated.(model)
+	ok.Equal(t, len(m.labelsList.Items()), 1)
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingCommitFileContents)
+	ok.Equal(t, m.currentHistoryLabel, "main")
+	ok.True(t, m.loadingLabelHistory, ok.Sprintf("expected history to be loading"))
+	ok.True(t, cmd != nil, ok.Sprintf("expected a command"))
+
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, !m.loadingLabelHistory, ok.Sprintf("expected history to have loaded"))
+	items := m.labelHistoryList.Items()
+	ok.Equal(t, len(items), 3)
+	ok.True(t, items[0].(*labelHistoryItem).current, ok.Sprintf("the newest entry is where the label points now"))
+	ok.True(t, !items[1].(*labelHistoryItem).current, ok.Sprintf("older entries aren't current"))
+	ok.Equal(t, items[1].(*labelHistoryItem).author, "bob")
+
+	m.labelHistoryList.Select(2)
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingCommitFileContents)
+	ok.Equal(t, m.currentCommitID, "ghi789jkl012")
+	ok.True(t, cmd != nil, ok.Sprintf("expected a command"))
+}
+
+// TestLabelHistory_StaleResponseIgnored verifies a history page for a label
+// other than the open one (e.g. the user backed out and opened another
+// label before it arrived) doesn't clobber the list.
+func TestLabelHistory_StaleResponseIgnored(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.currentHistoryLabel = "v1"
+	m.loadingLabelHistory = true
+
+	updated, _ := m.Update(labelHistoryMsg{label: "main", commits: []*modulev1.Commit{{Id: "abc123def456"}}})
+	m = updated.(model)
+	ok.True(t, m.loadingLabelHistory, ok.Sprintf("expected the stale page to be ignored"))
+	ok.Equal(t, len(m.labelHistoryList.Items()), 0)
+}
+
+// TestLabels_ToggleArchived verifies "a" cycles the Labels tab through
+// unarchived, all, and archived-only labels, refetching each time.
+func TestLabels_ToggleArchived(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.state = modelStateBrowsingCommitContents
+	m.activeCommitTab = commitTabLabels
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	m.currentDefaultLabelName = "main"
+
+	labelNames := func(m model) []string {
+		var names []string
+		for _, item := range m.labelsList.Items() {
+			names = append(names, item.(*labelItem).Title())
+		}
+		return names
+	}
+
+	updated, _ := m.Update(c.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter)())
+	m = updated.(model)
+	ok.DeepEqual(t, labelNames(m), []string{"main (default)"})
+
+	for _, want := range [][]string{
+		{"main (default)", "v0.1.0 (archived)"},
+		{"v0.1.0 (archived)"},
+		{"main (default)"},
+	} {
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+		m = updated.(model)
+		ok.True(t, m.loadingLabels, ok.Sprintf("expected labels to be reloading"))
+		updated, _ = m.Update(cmd())
+		m = updated.(model)
+		ok.DeepEqual(t, labelNames(m), want)
+	}
+}
+
+// TestLabels_StaleArchiveFilterIgnored verifies labels listed with a filter
+// the user has since toggled away from don't replace the current ones.
+func TestLabels_StaleArchiveFilterIgnored(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.labelArchiveFilter = labelArchiveFilterArchived
+	m.loadingLabels = true
+
+	updated, _ := m.Update(labelsMsg{labels: []*modulev1.Label{{Name: "main"}}, archiveFilter: labelArchiveFilterUnarchived})
+	m = updated.(model)
+	ok.True(t, m.loadingLabels, ok.Sprintf("expected the stale labels to be ignored"))
+	ok.Equal(t, len(m.currentLabels), 0)
+}
+
+// newLabelsTabTestModel returns an authenticated model browsing the Labels
+// tab of commit def456ghi789, with the fake server's labels loaded ("main"
+// points at a different commit).
+func newLabelsTabTestModel(t *testing.T, module string) model {
+	t.Helper()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.authenticated = true
+	m.state = modelStateBrowsingCommitContents
+	m.activeCommitTab = commitTabLabels
+	m.currentOwner = "bufbuild"
+	m.currentModule = module
+	m.currentCommitID = "def456ghi789"
+	updated, _ := m.Update(c.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter)())
+	return updated.(model)
+}
+
+// TestLabelWrite_MoveConfirmed verifies moving a label asks for
+// confirmation first, then writes and refetches the labels.
+func TestLabelWrite_MoveConfirmed(t *testing.T) {
+	t.Parallel()
+
+	m := newLabelsTabTestModel(t, "registry")
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'm', Text: "m"})
+	m = updated.(model)
+	ok.True(t, cmd == nil, ok.Sprintf("nothing should be written before confirming"))
+	ok.True(t, m.pendingLabelWrite != nil, ok.Sprintf("expected a pending label write"))
+	ok.Equal(t, m.pendingLabelWrite.prompt(), `Move label "main" from commit abc123def456 to def456ghi789?`)
+
+	// Unrelated keys are swallowed while the prompt is up.
+	updated, _ = m.Update(tea.KeyPressMsg{Code: ']', Text: "]"})
+	m = updated.(model)
+	ok.Equal(t, m.activeCommitTab, commitTabLabels)
+
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	m = updated.(model)
+	ok.True(t, m.pendingLabelWrite == nil, ok.Sprintf("expected the prompt to close"))
+	msg := cmd()
+	written, isWritten := msg.(labelWrittenMsg)
+	ok.True(t, isWritten, ok.Sprintf("expected labelWrittenMsg, got %T", msg))
+	ok.Equal(t, written.write.label, "main")
+	ok.Equal(t, written.write.commitID, "def456ghi789")
+
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.True(t, m.loadingLabels, ok.Sprintf("expected labels to be refetched"))
+}
+
+// TestLabelWrite_CreateFromCommitList verifies "c" in the commit list
+// prompts for a label name and then for confirmation, and that cancelling
+// writes nothing.
+func TestLabelWrite_CreateFromCommitList(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.authenticated = true
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	updated, _ := m.Update(c.listCommits(m.currentOwner, m.currentModule)())
+	m = updated.(model)
+	m.commitList.Select(1)
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	m = updated.(model)
+	ok.True(t, m.labelNameInputActive, ok.Sprintf("expected the label name input"))
+	for _, r := range "v2" {
+		updated, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+		m = updated.(model)
+	}
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	ok.True(t, !m.labelNameInputActive, ok.Sprintf("expected the label name input to close"))
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, m.pendingLabelWrite != nil, ok.Sprintf("expected a pending label write"))
+	ok.Equal(t, m.pendingLabelWrite.prompt(), `Point label "v2" at commit def456ghi789?`)
+
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	m = updated.(model)
+	ok.True(t, cmd == nil, ok.Sprintf("cancelling should write nothing"))
+	ok.True(t, m.pendingLabelWrite == nil, ok.Sprintf("expected the prompt to close"))
+	ok.Equal(t, m.state, modelStateBrowsingCommits)
+}
+
+// TestLabelWrite_MoveFromCommitList verifies that naming an existing label
+// in the commit list's label name input prompts to move it, even an
+// archived one the labels list hasn't loaded.
+func TestLabelWrite_MoveFromCommitList(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.authenticated = true
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	updated, _ := m.Update(c.listCommits(m.currentOwner, m.currentModule)())
+	m = updated.(model)
+	m.commitList.Select(1)
+	press := func(msg tea.KeyPressMsg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(model)
+		return cmd
+	}
+
+	press(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	for _, r := range "v0.1.0" {
+		press(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	cmd := press(tea.KeyPressMsg{Code: tea.KeyEnter})
+	ok.True(t, m.pendingLabelWrite == nil, ok.Sprintf("expected no prompt before the label is looked up"))
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, m.pendingLabelWrite != nil, ok.Sprintf("expected a pending label write"))
+	ok.Equal(t, m.pendingLabelWrite.prompt(), `Move label "v0.1.0" from commit ghi789jkl012 to def456ghi789?`)
+
+	// A label that already points at the commit isn't offered.
+	m.pendingLabelWrite = nil
+	m.commitList.Select(2)
+	press(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	for _, r := range "v0.1.0" {
+		press(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	cmd = press(tea.KeyPressMsg{Code: tea.KeyEnter})
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, m.pendingLabelWrite == nil, ok.Sprintf("expected no pending label write"))
+}
+
+// TestLabelWrite_ArchiveTogglesOnState verifies "D" archives an unarchived
+// label and unarchives an archived one.
+func TestLabelWrite_ArchiveTogglesOnState(t *testing.T) {
+	t.Parallel()
+
+	m := newLabelsTabTestModel(t, "registry")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	m = updated.(model)
+	ok.True(t, m.pendingLabelWrite != nil, ok.Sprintf("expected a pending label write"))
+	ok.Equal(t, m.pendingLabelWrite.action, labelActionArchive)
+
+	m.pendingLabelWrite = nil
+	m.labelArchiveFilter = labelArchiveFilterArchived
+	updated, _ = m.Update(m.client.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter)())
+	m = updated.(model)
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	m = updated.(model)
+	ok.Equal(t, m.pendingLabelWrite.action, labelActionUnarchive)
+	ok.True(t, cmd == nil, ok.Sprintf("nothing should be written before confirming"))
+}
+
+// TestLabelWrite_PermissionDenied verifies a refused write is reported as
+// such and hides label changes for that module from then on.
+func TestLabelWrite_PermissionDenied(t *testing.T) {
+	t.Parallel()
+
+	m := newLabelsTabTestModel(t, "readonly")
+	ok.True(t, m.canWriteLabels(), ok.Sprintf("writes should be offered until refused"))
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	m = updated.(model)
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	msg := cmd()
+	writeErr, isErr := msg.(labelWriteErrMsg)
+	ok.True(t, isErr, ok.Sprintf("expected labelWriteErrMsg, got %T", msg))
+	ok.True(t, writeErr.permissionDenied(), ok.Sprintf("expected a permission error, got %v", writeErr.err))
+
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.True(t, !m.canWriteLabels(), ok.Sprintf("writes should no longer be offered"))
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	m = updated.(model)
+	ok.True(t, m.pendingLabelWrite == nil, ok.Sprintf("expected no prompt once refused"))
+}
+
+// TestLabelWrite_RequiresToken verifies label changes aren't offered
+// without a token.
+func TestLabelWrite_RequiresToken(t *testing.T) {
+	t.Parallel()
+
+	m := newLabelsTabTestModel(t, "registry")
+	m.authenticated = false
+	for _, b := range m.ShortHelp() {
+		ok.NotEqual(t, b.Help().Key, keys.NewLabel.Help().Key)
+	}
+	for _, r := range "cmD" {
+		updated, _ := m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+		m = updated.(model)
+	}
+	ok.True(t, !m.labelNameInputActive, ok.Sprintf("expected no label name input"))
+	ok.True(t, m.pendingLabelWrite == nil, ok.Sprintf("expected no prompt"))
+}
+
+// TestGetCommitDetailsCommand verifies the commit header's details: who
+// pushed the commit and the labels pointing at it.
+func TestGetCommitDetailsCommand(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+
+	msg := c.getCommitDetails(&modulev1.Commit{Id: "abc123def456", CreatedByUserId: "user1"})()
+	details, isDetails := msg.(commitDetailsMsg)
+	ok.True(t, isDetails, ok.Sprintf("expected commitDetailsMsg, got %T", msg))
+	ok.Equal(t, details.commitID, "abc123def456")
+	ok.Equal(t, details.author, "alice")
+	ok.DeepEqual(t, details.labels, []string{"main"})
+
+	// An author the owner service doesn't know is left blank rather than
+	// failing the whole header.
+	msg = c.getCommitDetails(&modulev1.Commit{Id: "def456ghi789", CreatedByUserId: "nobody"})()
+	details, isDetails = msg.(commitDetailsMsg)
+	ok.True(t, isDetails, ok.Sprintf("expected commitDetailsMsg, got %T", msg))
+	ok.Equal(t, details.author, "")
+	ok.Zero(t, len(details.labels))
+}
+
+// TestCommitDetails_HeaderAndStaleResponse verifies the commit header shows
+// the loaded details, and that details for a commit the user has since left
+// are dropped.
+func TestCommitDetails_HeaderAndStaleResponse(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.state = modelStateBrowsingCommitContents
+	m.currentCommitID = "abc123def456"
+	m.currentCommit = &modulev1.Commit{
+		Id:               "abc123def456",
+		CreateTime:       timestamppb.New(time.Now().Add(-1 * time.Hour)),
+		SourceControlUrl: "https://github.com/bufbuild/registry/commit/0123456789abcdef",
+		Digest:           &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0xab, 0xcd}},
+	}
+	m.loadingCommitDetails = true
+
+	updated, _ := m.Update(commitDetailsMsg{commitID: "def456ghi789", author: "bob"})
+	m = updated.(model)
+	ok.True(t, m.loadingCommitDetails, ok.Sprintf("expected the stale details to be ignored"))
+
+	updated, _ = m.Update(commitDetailsMsg{commitID: "abc123def456", author: "alice", labels: []string{"main"}})
+	m = updated.(model)
+	ok.True(t, !m.loadingCommitDetails, ok.Sprintf("expected the details to have loaded"))
+	header := ansi.Strip(m.commitDetailsView())
+	ok.True(t, strings.Contains(header, "alice"), ok.Sprintf("expected the author in %q", header))
+	ok.True(t, strings.Contains(header, "main"), ok.Sprintf("expected the label in %q", header))
+	ok.True(t, strings.Contains(header, "github.com/bufbuild/registry@0123456789ab"), ok.Sprintf("expected the source commit in %q", header))
+	ok.True(t, strings.Contains(header, "b5:abcd"), ok.Sprintf("expected the digest in %q", header))
+}
+
+// TestCommitList_Annotations verifies commit rows pick up their authors and
+// labels once resolved, and that annotations for a module the user has left
+// are dropped.
+func TestCommitList_Annotations(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+
+	updated, cmd := m.Update(c.listCommits(m.currentOwner, m.currentModule)())
+	m = updated.(model)
+	ok.True(t, cmd != nil, ok.Sprintf("expected the commits to be annotated"))
+	annotations := cmd()
+
+	stale := m
+	stale.currentModule = "other"
+	updated, _ = stale.Update(annotations)
+	ok.Equal(t, updated.(model).commitList.Items()[0].(*commit).author, "", ok.Sprintf("expected stale annotations to be ignored"))
+
+	updated, _ = m.Update(annotations)
+	m = updated.(model)
+	items := m.commitList.Items()
+	first, second, third := items[0].(*commit), items[1].(*commit), items[2].(*commit)
+	ok.Equal(t, first.author, "alice")
+	ok.DeepEqual(t, first.labels, []string{"main"})
+	ok.Equal(t, first.Title(), "abc123def456 [main]")
+	ok.True(t, strings.Contains(first.Description(), "alice"), ok.Sprintf("expected the author in %q", first.Description()))
+	ok.Equal(t, second.author, "bob")
+	ok.Zero(t, len(second.labels))
+	ok.Equal(t, third.author, "", ok.Sprintf("a commit without a known pusher has no author"))
+
+	// Rows for later pages pick up the labels already known.
+	updated, _ = m.Update(moreCommitsMsg{commits: []*modulev1.Commit{{Id: "abc123def456", CreatedByUserId: "user1"}}})
+	m = updated.(model)
+	later := m.commitList.Items()[3].(*commit)
+	ok.Equal(t, later.author, "alice")
+	ok.DeepEqual(t, later.labels, []string{"main"})
+}
+
+// TestResolveCommitAsOf verifies a module or label opened as of a time
+// resolves to the latest commit pushed at or before it.
+func TestResolveCommitAsOf(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	ctx := t.Context()
+	registry := &modulev1.ResourceRef_Name{Owner: "bufbuild", Module: "registry"}
+	main := &modulev1.ResourceRef_Name{Owner: "bufbuild", Module: "registry", Child: &modulev1.ResourceRef_Name_Ref{Ref: "main"}}
+
+	commit, err := c.resolveCommitAsOf(ctx, registry, time.Now().Add(-90*time.Minute))
+	ok.MustNoError(t, err)
+	ok.Equal(t, commit.Id, "def456ghi789")
+
+	commit, err = c.resolveCommitAsOf(ctx, registry, time.Now())
+	ok.MustNoError(t, err)
+	ok.Equal(t, commit.Id, "abc123def456")
+
+	commit, err = c.resolveCommitAsOf(ctx, main, time.Now().Add(-150*time.Minute))
+	ok.MustNoError(t, err)
+	ok.Equal(t, commit.Id, "ghi789jkl012")
+
+	_, err = c.resolveCommitAsOf(ctx, registry, time.Now().Add(-24*time.Hour))
+	ok.Error(t, err, ok.Sprintf("nothing was pushed that long ago"))
+
+	commitRef := &modulev1.ResourceRef_Name{Owner: "bufbuild", Module: "registry", Child: &modulev1.ResourceRef_Name_Ref{Ref: "abc123def456"}}
+	_, err = c.resolveCommitAsOf(ctx, commitRef, time.Now())
+	ok.True(t, err != nil && strings.Contains(err.Error(), "not a commit"), ok.Sprintf("expected a commit ref to be refused, got %v", err))
+}
+
+// TestNavigate_AsOf verifies entering a time-travel reference in the
+// navigate input opens the commit that was current then.
+func TestNavigate_AsOf(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.navigateInput.SetValue("bufbuild/registry@" + time.Now().Add(-90*time.Minute).UTC().Format(time.RFC3339))
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingReference)
+	ok.True(t, !m.referenceAsOf.IsZero(), ok.Sprintf("expected the as-of time to be kept"))
+
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingCommitFileContents)
+	ok.Equal(t, m.currentCommitID, "def456ghi789")
+}
+
+// TestOverview_FetchesModuleAndRendersReadme verifies opening the Overview
+// tab for a module not opened from the module list fetches its metadata,
+// and that the tab shows it alongside the commit's README and license.
+func TestOverview_FetchesModuleAndRendersReadme(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(100, 60)
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	m.currentCommitID = "abc123def456"
+	updated, _ := m.Update(c.getCommitContent(m.currentCommitID)())
+	m = updated.(model)
+	ok.Equal(t, m.activeCommitTab, commitTabDocs, ok.Sprintf("commits still open on the Docs tab"))
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: '[', Text: "["})
+	m = updated.(model)
+	ok.Equal(t, m.activeCommitTab, commitTabOverview)
+	ok.True(t, m.loadingModuleInfo, ok.Sprintf("expected the module to be loading"))
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, !m.loadingModuleInfo, ok.Sprintf("expected the module to have loaded"))
+
+	overview := ansi.Strip(m.overviewViewport.GetContent())
+	for _, want := range []string{"The Buf registry module", "https://github.com/bufbuild/registry", "public", "deprecated", "main", "License", "none", "README.md", "Registry"} {
+		ok.True(t, strings.Contains(overview, want), ok.Sprintf("expected %q in the overview:\n%s", want, overview))
+	}
+
+	// Coming back to the tab doesn't refetch.
+	updated, _ = m.Update(tea.KeyPressMsg{Code: ']', Text: "]"})
+	m = updated.(model)
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: '[', Text: "["})
+	m = updated.(model)
+	ok.True(t, cmd == nil, ok.Sprintf("expected the module metadata to be reused"))
+}
+
+// TestOverview_ModuleErrorStillShowsReadme verifies a failure to fetch the
+// module's metadata doesn't hide the README.
+func TestOverview_ModuleErrorStillShowsReadme(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(100, 60)
+	m.currentOwner = "bufbuild"
+	m.currentModule = "missing"
+	m.currentCommitID = "abc123def456"
+	updated, _ := m.Update(c.getCommitContent(m.currentCommitID)())
+	m = updated.(model)
+	m.activeCommitTab = commitTabOverview
+	updated, _ = m.Update(m.loadTabIfNeeded()())
+	m = updated.(model)
+
+	overview := ansi.Strip(m.overviewViewport.GetContent())
+	ok.True(t, strings.Contains(overview, "Error loading module"), ok.Sprintf("expected the error in the overview:\n%s", overview))
+	ok.True(t, strings.Contains(overview, "Registry"), ok.Sprintf("expected the README in the overview:\n%s", overview))
+}
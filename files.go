@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	"charm.land/bubbles/v2/tree"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// filesStatusExpiredMsg retires the files tree's status message, as
+// depsStatusExpiredMsg does the deps tab's.
+type filesStatusExpiredMsg struct{ seq int }
+
+// filesDirNode is a directory in the Files tab's tree. name is its path
+// relative to its parent in the tree, which is more than one directory when
+// a chain of directories holding nothing but each other is compacted into
+// one node (e.g. "proto/acme/weather/v1").
+type filesDirNode struct {
+	name  string
+	count int
+}
+
+// String renders the directory as it appears in the tree, with the number
+// of files beneath it.
+func (d filesDirNode) String() string {
+	files := "files"
+	if d.count == 1 {
+		files = "file"
+	}
+	return d.name + "/ " + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(%d %s)", d.count, files))
+}
+
+// filesFileNode is a file in the Files tab's tree.
+type filesFileNode struct {
+	file *modulev1.File
+	diff fileDiffStatus
+}
+
+// String renders the file as its base name; the directories above it in the
+// tree make up the rest of its path.
+func (f filesFileNode) String() string {
+	return f.diff.withMarker(path.Base(f.file.Path))
+}
+
+// filesDir is a directory being assembled into the tree.
+type filesDir struct {
+	dirs  map[string]*filesDir
+	files []*modulev1.File
+	count int
+}
+
+// filesTree builds files into a directory tree rooted at rootName, with
+// directories before files at each level and both sorted by name, marking
+// each with its status in diffs (keyed by path) if the tab is diffed. Every
+// directory starts open.
+func filesTree(rootName string, files []*modulev1.File, diffs map[string]fileDiffStatus) *tree.Node {
+	root := &filesDir{}
+	for _, file := range files {
+		dir := root
+		dir.count++
+		segments := strings.Split(file.Path, "/")
+		for _, segment := range segments[:len(segments)-1] {
+			if dir.dirs == nil {
+				dir.dirs = make(map[string]*filesDir)
+			}
+			child, ok := dir.dirs[segment]
+			if !ok {
+				child = &filesDir{}
+				dir.dirs[segment] = child
+			}
+			dir = child
+			dir.count++
+		}
+		dir.files = append(dir.files, file)
+	}
+	node := tree.Root(filesDirNode{name: rootName, count: root.count})
+	root.addChildren(node, diffs)
+	return node
+}
+
+func (d *filesDir) addChildren(node *tree.Node, diffs map[string]fileDiffStatus) {
+	for _, name := range slices.Sorted(maps.Keys(d.dirs)) {
+		dir := d.dirs[name]
+		// Fold directories that hold only a single directory into it, so
+		// deep package layouts don't cost a level of nesting apiece.
+		for len(dir.files) == 0 && len(dir.dirs) == 1 {
+			for childName, child := range dir.dirs {
+				name += "/" + childName
+				dir = child
+			}
+		}
+		child := tree.Root(filesDirNode{name: name, count: dir.count})
+		dir.addChildren(child, diffs)
+		node.Child(child)
+	}
+	files := slices.SortedFunc(slices.Values(d.files), func(a, b *modulev1.File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	for _, file := range files {
+		// Added by value, like the deps tree's leaves, so files don't get an
+		// expand/collapse indicator.
+		node.Child(filesFileNode{file: file, diff: diffs[file.Path]})
+	}
+}
+
+// selectedFilesTreeFile returns the file under the cursor in the files tree,
+// or nil if the cursor is on a directory.
+func selectedFilesTreeFile(model tree.Model) *modulev1.File {
+	node := selectedTreeNode(model)
+	if node == nil {
+		return nil
+	}
+	if file, ok := node.GivenValue().(filesFileNode); ok {
+		return file.file
+	}
+	return nil
+}
+
+// filesTreeShown reports whether the Files tab shows its files as a tree
+// (the default) rather than a flat list of paths or grep results.
+func (m model) filesTreeShown() bool {
+	return !m.filesAsList && !m.grepShown()
+}
+
+// rebuildFilesTree rebuilds the files tree from the files commitFilesList
+// currently shows, so the tree narrows to whatever its filter matches.
+func (m *model) rebuildFilesTree() {
+	var files []*modulev1.File
+	diffs := make(map[string]fileDiffStatus)
+	for _, item := range m.commitFilesList.VisibleItems() {
+		if file, ok := item.(*commitFile); ok {
+			files = append(files, file.underlying)
+			diffs[file.underlying.Path] = file.diff
+		}
+	}
+	m.filesTree.SetNodes(filesTree(m.currentModule, files, diffs))
+	m.filesTree.GoToTop()
+}
+
+// selectFilesTreeFile points commitFilesList at the file under the tree's
+// cursor. The list stays the source of truth for which file is selected --
+// the file viewer, yank and browse all read it -- so the tree only has to
+// steer it. A directory under the cursor leaves the selection alone.
+func (m *model) selectFilesTreeFile() tea.Cmd {
+	file := selectedFilesTreeFile(m.filesTree)
+	if file == nil || !m.selectCommitFile(file) {
+		return nil
+	}
+	cmd := m.updateFileView(file)
+	m.fileViewport.GotoTop()
+	return cmd
+}
+
+// selectCommitFile selects file in commitFilesList, clearing the list's
+// filter first if it hides file, and reports whether the selection changed.
+func (m *model) selectCommitFile(file *modulev1.File) bool {
+	index := func() int {
+		return slices.IndexFunc(m.commitFilesList.VisibleItems(), func(item list.Item) bool {
+			commitFile, ok := item.(*commitFile)
+			return ok && commitFile.underlying == file
+		})
+	}
+	i := index()
+	if i < 0 && m.commitFilesList.FilterState() != list.Unfiltered {
+		m.commitFilesList.ResetFilter()
+		m.rebuildFilesTree()
+		i = index()
+	}
+	if i < 0 || i == m.commitFilesList.Index() {
+		return false
+	}
+	m.commitFilesList.Select(i)
+	return true
+}
+
+// setFilesStatus shows text in the files tree's status bar, as setDepsStatus
+// does for the deps tab.
+func (m *model) setFilesStatus(text string) tea.Cmd {
+	m.filesStatus = text
+	m.filesStatusSeq++
+	seq := m.filesStatusSeq
+	return tea.Tick(depsStatusLifetime, func(time.Time) tea.Msg {
+		return filesStatusExpiredMsg{seq: seq}
+	})
+}
+
+// filesStatusMessage shows text wherever the Files tab's status bar
+// currently is: above the tree or grep results, or the list's own.
+func (m *model) filesStatusMessage(text string) tea.Cmd {
+	if m.filesTreeShown() || m.grepShown() {
+		return m.setFilesStatus(text)
+	}
+	return m.commitFilesList.NewStatusMessage(text)
+}
+
+// filesStatusView renders the status bar above the files tree: the list's
+// filter input while a filter is being typed, otherwise the file count (or
+// how many files the filter matched), temporarily replaced by the result of
+// the last yank/browse. Its geometry is pinned as in depsStatusView.
+func (m model) filesStatusView() string {
+	style := m.listStyles.StatusBar.Padding(0, 0, 1, 2).MaxWidth(m.filesTree.Width())
+	total := len(m.commitFilesList.Items())
+	switch {
+	case m.commitFilesList.FilterState() == list.Filtering:
+		return style.Render(m.commitFilesList.FilterInput.View())
+	case m.filesStatus != "":
+		return style.Render(m.filesStatus)
+	case m.commitFilesList.FilterState() == list.FilterApplied:
+		return style.Render(fmt.Sprintf("%d of %d files match %q", len(m.commitFilesList.VisibleItems()), total, m.commitFilesList.FilterValue()))
+	case total == 1:
+		return style.Render("1 file")
+	default:
+		return style.Render(fmt.Sprintf("%d files", total))
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+var testTreeFiles = []*modulev1.File{
+	{Path: "proto/acme/weather/v1/weather.proto", Content: []byte("syntax = \"proto3\";")},
+	{Path: "proto/acme/weather/v1/forecast.proto", Content: []byte("// forecast")},
+	{Path: "proto/acme/geo/v1/geo.proto", Content: []byte("// geo")},
+	{Path: "buf.yaml", Content: []byte("version: v2")},
+	{Path: "README.md", Content: []byte("# Weather")},
+}
+
+func TestFilesTree_CountsCompactionAndOrder(t *testing.T) {
+	t.Parallel()
+
+	rendered := ansi.Strip(filesTree("weather", testTreeFiles, nil).String())
+	lines := strings.Split(rendered, "\n")
+	var names []string
+	for _, line := range lines {
+		names = append(names, strings.TrimLeft(line, "│├└─ "))
+	}
+	ok.Equal(t, strings.Join(names, "\n"), strings.Join([]string{
+		"weather/ (5 files)",
+		// "proto" holds only "acme", so they share a node; "acme" holds two
+		// directories, so it stops there.
+		"proto/acme/ (3 files)",
+		"geo/v1/ (1 file)",
+		"geo.proto",
+		"weather/v1/ (2 files)",
+		"forecast.proto",
+		"weather.proto",
+		// Files come after the directories beside them.
+		"README.md",
+		"buf.yaml",
+	}, "\n"))
+}
+
+func newFilesTreeTestModel(t *testing.T) model {
+	t.Helper()
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "acme"
+	m.currentModule = "weather"
+	m.currentCommitID = "abc123def456"
+	updated, _ := m.Update(contentsMsg(&modulev1.DownloadResponse_Content{Files: testTreeFiles}))
+	m = updated.(model)
+	m.activeCommitTab = commitTabFiles
+	return m
+}
+
+// TestFilesTree_NavigationSelectsFiles verifies moving through the tree
+// drives the file viewer, and that directories open and close in place.
+func TestFilesTree_NavigationSelectsFiles(t *testing.T) {
+	t.Parallel()
+
+	m := newFilesTreeTestModel(t)
+	press := func(code rune, text string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+	}
+	down := func(n int) {
+		t.Helper()
+		for range n {
+			press('j', "j")
+		}
+	}
+
+	// Down to geo.proto, under the root, proto/acme and geo/v1.
+	down(3)
+	ok.Equal(t, selectedFilesTreeFile(m.filesTree).Path, "proto/acme/geo/v1/geo.proto")
+	selected, _ := m.commitFilesList.SelectedItem().(*commitFile)
+	ok.Equal(t, selected.underlying.Path, "proto/acme/geo/v1/geo.proto")
+	ok.True(t, strings.Contains(ansi.Strip(m.fileViewport.GetContent()), "// geo"), ok.Sprintf("expected geo.proto in the viewer"))
+
+	// → on a file opens it in the viewer.
+	press('l', "l")
+	ok.Equal(t, m.state, modelStateBrowsingCommitFileContents)
+	press('h', "h")
+	ok.Equal(t, m.state, modelStateBrowsingCommitContents)
+
+	// ← on an open directory closes it rather than leaving the commit.
+	press('k', "k")
+	ok.True(t, selectedFilesTreeFile(m.filesTree) == nil, ok.Sprintf("expected a directory under the cursor"))
+	press('h', "h")
+	ok.Equal(t, m.state, modelStateBrowsingCommitContents)
+	ok.True(t, !selectedTreeNode(m.filesTree).IsOpen(), ok.Sprintf("expected geo/v1 to have closed"))
+	view := ansi.Strip(m.View().Content)
+	ok.True(t, !strings.Contains(view, "geo.proto"), ok.Sprintf("expected geo.proto hidden:\n%s", view))
+
+	// → opens it again.
+	press('l', "l")
+	ok.Equal(t, m.state, modelStateBrowsingCommitContents)
+	ok.True(t, selectedTreeNode(m.filesTree).IsOpen(), ok.Sprintf("expected geo/v1 to have opened"))
+}
+
+// TestFilesTree_Filtering verifies the list's filter narrows the tree, and
+// that esc clears it before leaving the commit.
+func TestFilesTree_Filtering(t *testing.T) {
+	t.Parallel()
+
+	m := newFilesTreeTestModel(t)
+	// update runs msg and any cmds it produces, as the program would, so
+	// the filter's matches arrive.
+	var update func(msg tea.Msg)
+	update = func(msg tea.Msg) {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(model)
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case list.FilterMatchesMsg:
+			update(msg)
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				if cmd == nil {
+					continue
+				}
+				if msg, ok := cmd().(list.FilterMatchesMsg); ok {
+					update(msg)
+				}
+			}
+		}
+	}
+
+	update(tea.KeyPressMsg{Code: '/', Text: "/"})
+	ok.Equal(t, m.commitFilesList.FilterState(), list.Filtering)
+	for _, r := range "weather.proto" {
+		update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	ok.Equal(t, m.commitFilesList.FilterState(), list.FilterApplied)
+
+	view := ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, "weather.proto"), ok.Sprintf("expected the match in the tree:\n%s", view))
+	ok.True(t, !strings.Contains(view, "geo.proto"), ok.Sprintf("expected geo.proto filtered out:\n%s", view))
+	ok.True(t, strings.Contains(view, "of 5 files match"), ok.Sprintf("expected the match count:\n%s", view))
+
+	update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	ok.Equal(t, m.state, modelStateBrowsingCommitContents)
+	ok.Equal(t, m.commitFilesList.FilterState(), list.Unfiltered)
+	view = ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, "geo.proto"), ok.Sprintf("expected the whole tree back:\n%s", view))
+	ok.True(t, strings.Contains(view, "5 files"), ok.Sprintf("expected the file count:\n%s", view))
+}
+
+// TestFilesTree_ToggleList verifies t switches between the tree and the
+// flat list of paths.
+func TestFilesTree_ToggleList(t *testing.T) {
+	t.Parallel()
+
+	m := newFilesTreeTestModel(t)
+	ok.True(t, m.filesTreeShown(), ok.Sprintf("expected the tree by default"))
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 't', Text: "t"})
+	m = updated.(model)
+	ok.True(t, !m.filesTreeShown(), ok.Sprintf("expected the list after t"))
+	view := ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, "proto/acme/geo/v1/geo.proto"), ok.Sprintf("expected full paths in the list:\n%s", view))
+}
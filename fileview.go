@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/viewport"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fileLineGutter returns the file viewer's line-number gutter for a file of
+// lines lines, wide enough for the last. current, if positive, is the line
+// jumped to by the last goto or search, whose number is picked out.
+func fileLineGutter(lines, current int) viewport.GutterFunc {
+	width := len(strconv.Itoa(lines))
+	dim := lipgloss.NewStyle().Faint(true)
+	return func(info viewport.GutterContext) string {
+		switch {
+		case info.Soft:
+			return dim.Render(strings.Repeat(" ", width) + " │ ")
+		case info.Index >= lines:
+			return dim.Render(fmt.Sprintf("%*s │ ", width, "~"))
+		case info.Index+1 == current:
+			return lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%*d", width, info.Index+1)) + dim.Render(" │ ")
+		default:
+			return dim.Render(fmt.Sprintf("%*d │ ", width, info.Index+1))
+		}
+	}
+}
+
+// fileViewOffset returns the viewport Y offset that puts line (0-indexed) of
+// content at the top. Without soft wrap that's the line itself, but wrapped
+// lines above it take up as many rows as they wrap to, at width columns.
+func fileViewOffset(content string, line, width int, softWrap bool) int {
+	if !softWrap || width <= 0 {
+		return line
+	}
+	offset := 0
+	for i, text := range strings.Split(content, "\n") {
+		if i == line {
+			break
+		}
+		offset += max(1, int(math.Ceil(float64(ansi.StringWidth(text))/float64(width))))
+	}
+	return offset
+}
+
+// fileTextWidth is how many columns of text the file viewer shows per row,
+// after its gutter.
+func (m model) fileTextWidth() int {
+	return m.fileViewport.Width() - ansi.StringWidth(m.fileViewport.LeftGutterFunc(viewport.GutterContext{}))
+}
+
+// scrollFileViewTo scrolls the file viewer so line (0-indexed) of its
+// content is shown with a few lines of leading context, and marks it in the
+// gutter.
+func (m *model) scrollFileViewTo(line int) {
+	m.fileCurrentLine = line + 1
+	m.setFileGutter()
+	offset := fileViewOffset(m.fileViewport.GetContent(), max(0, line-3), m.fileTextWidth(), m.fileViewport.SoftWrap)
+	m.fileViewport.SetYOffset(offset)
+}
+
+// setFileGutter numbers the file viewer's lines, unless it's showing
+// rendered markdown, whose lines don't correspond to the file's, or a diff,
+// which numbers its own.
+func (m *model) setFileGutter() {
+	if m.fileMarkdown || m.fileDiffShown {
+		m.fileViewport.LeftGutterFunc = viewport.NoGutter
+		return
+	}
+	m.fileViewport.LeftGutterFunc = fileLineGutter(m.fileLines, m.fileCurrentLine)
+}
+
+// resetFileSearch clears the file viewer's search results and goto
+// position, e.g. because it's showing a different file.
+func (m *model) resetFileSearch() {
+	m.fileMatches = nil
+	m.fileMatchIdx = -1
+	m.fileSearchQuery = ""
+	m.fileGotoErr = nil
+	m.fileCurrentLine = 0
+}
+
+// runFileSearch searches the file viewer's content for the search input's
+// query, as the docs search does, and jumps to the first match.
+func (m *model) runFileSearch() {
+	m.fileSearchActive = false
+	m.fileSearchQuery = m.fileSearchInput.Value()
+	m.fileMatches = docsSearchMatches(m.fileViewport.GetContent(), m.fileSearchQuery)
+	m.fileMatchIdx = -1
+	if len(m.fileMatches) > 0 {
+		m.fileMatchIdx = 0
+		m.jumpToFileMatch()
+	}
+}
+
+// jumpToFileMatch scrolls the file viewer to the match at
+// fileMatches[fileMatchIdx], doing nothing if fileMatchIdx is out of range.
+func (m *model) jumpToFileMatch() {
+	if m.fileMatchIdx < 0 || m.fileMatchIdx >= len(m.fileMatches) {
+		return
+	}
+	m.scrollFileViewTo(docsMatchLine(m.fileViewport.GetContent(), m.fileMatches[m.fileMatchIdx][0]))
+}
+
+// gotoFileLine scrolls the file viewer to the line number in the goto
+// input, or leaves the input open with the error if it isn't one.
+func (m *model) gotoFileLine() {
+	n, err := strconv.Atoi(strings.TrimSpace(m.fileGotoInput.Value()))
+	if err != nil || n < 1 {
+		m.fileGotoErr = fmt.Errorf("not a line number: %q", m.fileGotoInput.Value())
+		return
+	}
+	lines := strings.Count(m.fileViewport.GetContent(), "\n") + 1
+	m.fileGotoActive = false
+	m.fileGotoErr = nil
+	m.scrollFileViewTo(min(n, lines) - 1)
+}
+
+// fileStatusView renders the line under the file viewer: the search or goto
+// input while one is open, otherwise where the last search got to.
+func (m model) fileStatusView() string {
+	style := lipgloss.NewStyle().MaxWidth(m.fileViewport.Width() + borderSize)
+	switch {
+	case m.diffInputActive:
+		return style.Render("diff against " + m.diffInput.View())
+	case m.fileSearchActive:
+		return style.Render("/" + m.fileSearchInput.View())
+	case m.fileGotoActive:
+		view := ":" + m.fileGotoInput.View()
+		if m.fileGotoErr != nil {
+			view += " " + lipgloss.NewStyle().Foreground(colorError).Render(m.fileGotoErr.Error())
+		}
+		return style.Render(view)
+	case m.fileSearchQuery != "" && len(m.fileMatches) == 0:
+		return style.Render(fmt.Sprintf("No matches for %q", m.fileSearchQuery))
+	case m.fileSearchQuery != "":
+		return style.Render(fmt.Sprintf("Match %d of %d for %q", m.fileMatchIdx+1, len(m.fileMatches), m.fileSearchQuery))
+	case m.diffLoading:
+		return style.Render(m.spinner.View() + " Loading " + m.diffRef + " to diff against")
+	case m.diffErr != nil:
+		return style.Render(lipgloss.NewStyle().Foreground(colorError).Render("Error diffing: " + m.diffErr.Error()))
+	case m.diffing():
+		return style.Render(m.diffSummary())
+	}
+	return ""
+}
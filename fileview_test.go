@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+func TestFileLineGutter(t *testing.T) {
+	t.Parallel()
+
+	gutter := fileLineGutter(120, 7)
+	ok.Equal(t, ansi.Strip(gutter(viewport.GutterContext{Index: 0})), "  1 │ ")
+	ok.Equal(t, ansi.Strip(gutter(viewport.GutterContext{Index: 6})), "  7 │ ")
+	ok.Equal(t, ansi.Strip(gutter(viewport.GutterContext{Index: 119})), "120 │ ")
+	ok.Equal(t, ansi.Strip(gutter(viewport.GutterContext{Index: 5, Soft: true})), "    │ ")
+	ok.Equal(t, ansi.Strip(gutter(viewport.GutterContext{Index: 120})), "  ~ │ ")
+}
+
+func TestFileViewOffset(t *testing.T) {
+	t.Parallel()
+
+	content := "short\n" + strings.Repeat("x", 25) + "\nshort\nshort"
+	ok.Equal(t, fileViewOffset(content, 3, 10, false), 3)
+	// The long line wraps to three rows.
+	ok.Equal(t, fileViewOffset(content, 1, 10, true), 1)
+	ok.Equal(t, fileViewOffset(content, 2, 10, true), 4)
+	ok.Equal(t, fileViewOffset(content, 3, 10, true), 5)
+}
+
+// TestFileViewer_SearchGotoAndWrap verifies searching the file viewer, going
+// to a line, and toggling wrap, all from the keyboard.
+func TestFileViewer_SearchGotoAndWrap(t *testing.T) {
+	t.Parallel()
+
+	var content strings.Builder
+	for i := 1; i <= 100; i++ {
+		if i == 40 || i == 80 {
+			fmt.Fprintf(&content, "// line %d has a needle\n", i)
+		} else {
+			fmt.Fprintf(&content, "// line %d\n", i)
+		}
+	}
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.currentCommitID = "abc123def456"
+	updated, _ := m.Update(contentsMsg(&modulev1.DownloadResponse_Content{Files: []*modulev1.File{
+		{Path: "long.proto", Content: []byte(content.String())},
+	}}))
+	m = updated.(model)
+	m.activeCommitTab = commitTabFiles
+	m.state = modelStateBrowsingCommitFileContents
+	press := func(code rune, text string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+	}
+	typeText := func(text string) {
+		t.Helper()
+		for _, r := range text {
+			press(r, string(r))
+		}
+		press(tea.KeyEnter, "")
+	}
+	ok.Equal(t, m.fileLines, 100)
+
+	press('/', "/")
+	ok.True(t, m.fileSearchActive)
+	typeText("NEEDLE")
+	ok.True(t, !m.fileSearchActive)
+	ok.Equal(t, len(m.fileMatches), 2)
+	ok.Equal(t, m.fileCurrentLine, 40)
+	ok.Equal(t, m.fileViewport.YOffset(), 36)
+	view := ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, `Match 1 of 2 for "NEEDLE"`), ok.Sprintf("expected the match count:\n%s", view))
+	ok.True(t, strings.Contains(view, " 40 │ // line 40 has a needle"), ok.Sprintf("expected numbered lines:\n%s", view))
+
+	press('n', "n")
+	ok.Equal(t, m.fileCurrentLine, 80)
+	press('n', "n")
+	ok.Equal(t, m.fileCurrentLine, 40)
+	press('N', "N")
+	ok.Equal(t, m.fileCurrentLine, 80)
+
+	// A goto to something other than a line number stays open to fix.
+	press(':', ":")
+	typeText("ten")
+	ok.True(t, m.fileGotoActive)
+	ok.True(t, m.fileGotoErr != nil)
+	press(tea.KeyEscape, "")
+	ok.True(t, !m.fileGotoActive)
+	press(':', ":")
+	typeText("10")
+	ok.True(t, !m.fileGotoActive)
+	ok.Equal(t, m.fileCurrentLine, 10)
+	ok.Equal(t, m.fileViewport.YOffset(), 6)
+
+	press('w', "w")
+	ok.True(t, m.fileViewport.SoftWrap)
+	ok.Equal(t, m.fileCurrentLine, 10)
+	press('w', "w")
+	ok.True(t, !m.fileViewport.SoftWrap)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// grepMaxMatches caps how many lines a grep reports, so a query like "e"
+// on a large module doesn't build a list nobody will page through.
+const grepMaxMatches = 1000
+
+// grepSnippetLead is how much of a line is kept before the match in a
+// result's snippet, so matches far along a long line stay on screen.
+const grepSnippetLead = 24
+
+// grepMatch is a line of a commit's file matching a grep, and the first
+// match on it.
+type grepMatch struct {
+	file *modulev1.File
+	// line is 1-indexed.
+	line int
+	text string
+	// start and end are the byte offsets of the match in text.
+	start, end int
+}
+
+// FilterValue implements [list.Item].
+func (g *grepMatch) FilterValue() string {
+	return g.file.Path + " " + g.text
+}
+
+// Title implements [list.DefaultItem].
+func (g *grepMatch) Title() string {
+	return g.file.Path + ":" + strconv.Itoa(g.line)
+}
+
+// Description implements [list.DefaultItem]: the line, trimmed to start a
+// little before the match, with the match highlighted.
+func (g *grepMatch) Description() string {
+	from := strings.IndexFunc(g.text, func(r rune) bool { return !unicode.IsSpace(r) })
+	prefix := ""
+	if from < 0 || from > g.start {
+		from = g.start
+	}
+	if g.start-from > grepSnippetLead {
+		from = g.start - grepSnippetLead
+		// Don't cut a multi-byte character in half.
+		for from < g.start && !utf8.RuneStart(g.text[from]) {
+			from++
+		}
+		prefix = "…"
+	}
+	highlight := lipgloss.NewStyle().Reverse(true)
+	return prefix + g.text[from:g.start] + highlight.Render(g.text[g.start:g.end]) + g.text[g.end:]
+}
+
+// grepPattern compiles a grep query. A query wrapped in slashes
+// ("/rpc \w+/") is a regular expression, used as written; anything else is
+// a plain substring, matched case-insensitively unless it contains an
+// uppercase letter.
+func grepPattern(query string) (*regexp.Regexp, error) {
+	if len(query) > 2 && strings.HasPrefix(query, "/") && strings.HasSuffix(query, "/") {
+		re, err := regexp.Compile(query[1 : len(query)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return re, nil
+	}
+	pattern := regexp.QuoteMeta(query)
+	if !strings.ContainsFunc(query, unicode.IsUpper) {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// grepFiles searches every line of files, in path order, for query (see
+// grepPattern). It stops after grepMaxMatches matching lines, reporting
+// whether it did.
+func grepFiles(files []*modulev1.File, query string) (matches []*grepMatch, truncated bool, err error) {
+	re, err := grepPattern(query)
+	if err != nil {
+		return nil, false, err
+	}
+	files = slices.SortedFunc(slices.Values(files), func(a, b *modulev1.File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	for _, file := range files {
+		scanner := bufio.NewScanner(bytes.NewReader(file.Content))
+		scanner.Buffer(nil, len(file.Content)+1)
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			loc := re.FindStringIndex(text)
+			if loc == nil || loc[0] == loc[1] {
+				continue
+			}
+			if len(matches) == grepMaxMatches {
+				return matches, true, nil
+			}
+			matches = append(matches, &grepMatch{file: file, line: line, text: text, start: loc[0], end: loc[1]})
+		}
+	}
+	return matches, false, nil
+}
+
+// grepMatchViewLine returns the line of the file viewer's rendering of
+// match's file to scroll to. Most files render line for line, but markdown
+// is rendered by glamour, so there it's the first rendered line containing
+// the matched text, if any.
+func grepMatchViewLine(rendered string, match *grepMatch) int {
+	if !strings.HasSuffix(strings.ToLower(match.file.Path), ".md") {
+		return match.line - 1
+	}
+	needle := strings.TrimSpace(match.text[match.start:match.end])
+	for i, line := range strings.Split(ansi.Strip(rendered), "\n") {
+		if strings.Contains(line, needle) {
+			return i
+		}
+	}
+	return 0
+}
+
+// grepStatus summarizes a grep's results for the status bar above them.
+func grepStatus(query string, matches []list.Item, truncated bool) string {
+	if len(matches) == 0 {
+		return fmt.Sprintf("No matches for %q", query)
+	}
+	files := make(map[string]bool)
+	for _, item := range matches {
+		if match, ok := item.(*grepMatch); ok {
+			files[match.file.Path] = true
+		}
+	}
+	status := fmt.Sprintf("%d matching line%s in %d file%s for %q", len(matches), plural(len(matches)), len(files), plural(len(files)), query)
+	if truncated {
+		status += fmt.Sprintf(" (first %d)", grepMaxMatches)
+	}
+	return status
+}
+
+// grepShown reports whether the Files tab shows a grep -- its input or its
+// results -- in place of the tree or list.
+func (m model) grepShown() bool {
+	return m.grepInputActive || m.grepQuery != ""
+}
+
+// runGrep searches the current commit's files for the grep input's query
+// and shows the results, or leaves the input open with the error if the
+// query doesn't compile. An empty query closes the grep.
+func (m *model) runGrep() tea.Cmd {
+	query := m.grepInput.Value()
+	if query == "" {
+		m.resetGrep()
+		return nil
+	}
+	matches, truncated, err := grepFiles(m.currentCommitFiles, query)
+	if err != nil {
+		m.grepErr = err
+		return nil
+	}
+	items := make([]list.Item, len(matches))
+	for i, match := range matches {
+		items[i] = match
+	}
+	m.grepInputActive = false
+	m.grepErr = nil
+	m.grepQuery = query
+	m.grepTruncated = truncated
+	m.grepList.ResetFilter()
+	m.grepList.SetItems(items)
+	m.grepList.ResetSelected()
+	return m.selectGrepMatch()
+}
+
+// resetGrep closes the grep and drops its results.
+func (m *model) resetGrep() {
+	m.grepInputActive = false
+	m.grepErr = nil
+	m.grepQuery = ""
+	m.grepTruncated = false
+	m.grepList.ResetFilter()
+	m.grepList.SetItems(nil)
+}
+
+// selectGrepMatch previews the grep result under the cursor: its file is
+// selected (so yank and browse act on it) and shown scrolled to the match,
+// with a few lines of leading context.
+func (m *model) selectGrepMatch() tea.Cmd {
+	match, ok := m.grepList.SelectedItem().(*grepMatch)
+	if !ok {
+		return nil
+	}
+	m.selectCommitFile(match.file)
+	cmd := m.updateFileView(match.file)
+	m.scrollFileViewTo(grepMatchViewLine(m.fileViewport.GetContent(), match))
+	return cmd
+}
+
+// grepStatusView renders the status bar above the grep results: the input
+// while it's open (with the error from a query that didn't compile), then a
+// summary of the results, temporarily replaced by the result of the last
+// yank/browse. Its geometry is pinned as in depsStatusView.
+func (m model) grepStatusView() string {
+	style := m.listStyles.StatusBar.Padding(0, 0, 1, 2).MaxWidth(m.grepList.Width())
+	switch {
+	case m.grepInputActive:
+		view := "grep " + m.grepInput.View()
+		if m.grepErr != nil {
+			view += " " + lipgloss.NewStyle().Foreground(colorError).Render(m.grepErr.Error())
+		}
+		return style.Render(view)
+	case m.filesStatus != "":
+		return style.Render(m.filesStatus)
+	default:
+		return style.Render(grepStatus(m.grepQuery, m.grepList.Items(), m.grepTruncated))
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+var testGrepFiles = []*modulev1.File{
+	{Path: "b.proto", Content: []byte("syntax = \"proto3\";\n\nmessage Weather {\n  string city = 1;\n}\n")},
+	{Path: "a.proto", Content: []byte("message City {\n  string name = 1;\n}\n")},
+}
+
+func TestGrepFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		query string
+		want  []string
+	}{
+		// Lowercase matches case-insensitively, in path order.
+		{"city", []string{"a.proto:1 City", "b.proto:4 city"}},
+		// An uppercase letter makes it case-sensitive.
+		{"City", []string{"a.proto:1 City"}},
+		// Slashes make it a regular expression.
+		{`/string \w+ = 1/`, []string{"a.proto:2 string name = 1", "b.proto:4 string city = 1"}},
+		{"nothing", nil},
+	} {
+		matches, truncated, err := grepFiles(testGrepFiles, test.query)
+		ok.NoError(t, err)
+		ok.True(t, !truncated)
+		var got []string
+		for _, match := range matches {
+			got = append(got, match.Title()+" "+match.text[match.start:match.end])
+		}
+		ok.Equal(t, strings.Join(got, "\n"), strings.Join(test.want, "\n"), ok.Sprintf("query %q", test.query))
+	}
+
+	_, _, err := grepFiles(testGrepFiles, "/(/")
+	ok.True(t, err != nil, ok.Sprintf("expected an invalid regexp to fail"))
+}
+
+func TestGrepFiles_Truncates(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("match\n", grepMaxMatches+1)
+	matches, truncated, err := grepFiles([]*modulev1.File{{Path: "big.proto", Content: []byte(content)}}, "match")
+	ok.NoError(t, err)
+	ok.True(t, truncated)
+	ok.Equal(t, len(matches), grepMaxMatches)
+}
+
+func TestGrepMatch_DescriptionTrimsToMatch(t *testing.T) {
+	t.Parallel()
+
+	text := "    // " + strings.Repeat("x", 40) + " needle"
+	match := &grepMatch{file: &modulev1.File{Path: "a.proto"}, line: 1, text: text, start: strings.Index(text, "needle")}
+	match.end = match.start + len("needle")
+	description := ansi.Strip(match.Description())
+	ok.True(t, strings.HasPrefix(description, "…"), ok.Sprintf("expected the line trimmed: %q", description))
+	ok.True(t, strings.HasSuffix(description, "needle"), ok.Sprintf("expected the match kept: %q", description))
+
+	short := &grepMatch{file: &modulev1.File{Path: "a.proto"}, line: 1, text: "  string city = 1;", start: 9, end: 13}
+	ok.Equal(t, ansi.Strip(short.Description()), "string city = 1;")
+}
+
+// TestGrep_ResultsOpenFileAtMatch verifies a grep's results replace the
+// tree, preview the file scrolled to the match, and open it on enter.
+func TestGrep_ResultsOpenFileAtMatch(t *testing.T) {
+	t.Parallel()
+
+	m := newFilesTreeTestModel(t)
+	press := func(msg tea.KeyPressMsg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	}
+	typeText := func(text string) {
+		t.Helper()
+		for _, r := range text {
+			press(tea.KeyPressMsg{Code: r, Text: string(r)})
+		}
+	}
+
+	press(tea.KeyPressMsg{Code: 'F', Text: "F"})
+	ok.True(t, m.grepInputActive)
+	// A regexp that doesn't compile keeps the input open.
+	typeText("/(/")
+	press(tea.KeyPressMsg{Code: tea.KeyEnter})
+	ok.True(t, m.grepInputActive)
+	view := ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, "invalid regular expression"), ok.Sprintf("expected the error:\n%s", view))
+
+	for range "/(/" {
+		press(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	typeText("e")
+	press(tea.KeyPressMsg{Code: tea.KeyEnter})
+	ok.True(t, !m.grepInputActive)
+	ok.Equal(t, m.grepQuery, "e")
+	view = ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, "matching line"), ok.Sprintf("expected the results summary:\n%s", view))
+	ok.True(t, strings.Contains(view, "README.md:"), ok.Sprintf("expected a result:\n%s", view))
+
+	// Moving to a result previews its file.
+	press(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	match := m.grepList.SelectedItem().(*grepMatch)
+	selected := m.commitFilesList.SelectedItem().(*commitFile)
+	ok.Equal(t, selected.underlying.Path, match.file.Path)
+
+	press(tea.KeyPressMsg{Code: tea.KeyEnter})
+	ok.Equal(t, m.state, modelStateBrowsingCommitFileContents)
+
+	// esc goes back to the results, then closes them.
+	press(tea.KeyPressMsg{Code: tea.KeyEscape})
+	ok.Equal(t, m.state, modelStateBrowsingCommitContents)
+	ok.Equal(t, m.grepQuery, "e")
+	press(tea.KeyPressMsg{Code: tea.KeyEscape})
+	ok.Equal(t, m.state, modelStateBrowsingCommitContents)
+	ok.Equal(t, m.grepQuery, "")
+	ok.True(t, m.filesTreeShown())
+}
+
+func TestGrepMatchViewLine(t *testing.T) {
+	t.Parallel()
+
+	proto := &grepMatch{file: &modulev1.File{Path: "a.proto"}, line: 7, text: "x", start: 0, end: 1}
+	ok.Equal(t, grepMatchViewLine("", proto), 6)
+
+	markdown := &grepMatch{file: &modulev1.File{Path: "README.md"}, line: 1, text: "# Weather", start: 2, end: 9}
+	ok.Equal(t, grepMatchViewLine("\n\n  \x1b[1mWeather\x1b[0m\n", markdown), 2)
+}
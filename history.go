@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+)
+
+// historyBatchSize is how many commits' contents a history search downloads
+// per request. Contents are whole modules, so a page of commits is fetched
+// in several of these rather than in one response.
+const historyBatchSize = 10
+
+// historyCount is how many times a history search's query occurs across
+// every file in a commit.
+type historyCount struct {
+	commit *modulev1.Commit
+	count  int
+}
+
+// historyStepMsg carries the counts for the next batch of commits a history
+// search has walked, newest first, and where the walk goes next: the rest
+// of the page of commits being downloaded, then the next page.
+type historyStepMsg struct {
+	// id identifies the search, so steps of one that has been closed or
+	// replaced are dropped.
+	id            int
+	counts        []historyCount
+	pending       []*modulev1.Commit
+	nextPageToken string
+}
+
+type historyErrMsg struct {
+	id  int
+	err error
+}
+
+func (e historyErrMsg) Error() string { return e.err.Error() }
+
+// historySearchStep takes the next step of walking a module's commits,
+// newest first, counting pattern's occurrences in each: it lists the page
+// of commits at pageToken if none are pending, then downloads and counts
+// the first historyBatchSize of them.
+func (c *client) historySearchStep(id int, owner, module string, pattern *regexp.Regexp, pending []*modulev1.Commit, pageToken string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		msg := historyStepMsg{id: id, nextPageToken: pageToken}
+		if len(pending) == 0 {
+			response, err := c.listCommitsPage(ctx, owner, module, pageToken)
+			if err != nil {
+				return historyErrMsg{id, fmt.Errorf("getting commits: %w", err)}
+			}
+			pending = response.Msg.Commits
+			msg.nextPageToken = response.Msg.NextPageToken
+		}
+		batch := pending[:min(historyBatchSize, len(pending))]
+		msg.pending = pending[len(batch):]
+		if len(batch) == 0 {
+			return msg
+		}
+		values := make([]*modulev1.DownloadRequest_Value, len(batch))
+		for i, commit := range batch {
+			values[i] = &modulev1.DownloadRequest_Value{
+				ResourceRef: &modulev1.ResourceRef{
+					Value: &modulev1.ResourceRef_Id{Id: commit.Id},
+				},
+			}
+		}
+		response, err := c.downloadServiceClient.Download(ctx, connect.NewRequest(&modulev1.DownloadRequest{Values: values}))
+		if err != nil {
+			return historyErrMsg{id, fmt.Errorf("getting commit contents: %w", err)}
+		}
+		counts := make(map[string]int, len(response.Msg.Contents))
+		for _, content := range response.Msg.Contents {
+			count := 0
+			for _, file := range content.Files {
+				count += len(pattern.FindAllIndex(file.Content, -1))
+			}
+			counts[content.Commit.GetId()] = count
+		}
+		for _, commit := range batch {
+			count, ok := counts[commit.Id]
+			if !ok {
+				return historyErrMsg{id, fmt.Errorf("no contents returned for commit %s", commit.Id)}
+			}
+			msg.counts = append(msg.counts, historyCount{commit: commit, count: count})
+		}
+		return msg
+	}
+}
+
+// historyChange is a commit where the number of occurrences of a history
+// search's query changed from the commit before it.
+type historyChange struct {
+	commit   *modulev1.Commit
+	author   string
+	from, to int
+}
+
+// FilterValue implements [list.Item].
+func (h *historyChange) FilterValue() string {
+	return h.commit.Id + " " + h.author
+}
+
+// Title implements [list.DefaultItem].
+func (h *historyChange) Title() string {
+	switch {
+	case h.from == 0:
+		return fmt.Sprintf("%s added (%d)", h.commit.Id, h.to)
+	case h.to == 0:
+		return fmt.Sprintf("%s removed (%d)", h.commit.Id, h.from)
+	default:
+		return fmt.Sprintf("%s %d → %d", h.commit.Id, h.from, h.to)
+	}
+}
+
+// Description implements [list.DefaultItem].
+func (h *historyChange) Description() string {
+	t := h.commit.CreateTime.AsTime()
+	desc := fmt.Sprintf("%s (%s)", t.Format(time.Stamp), relativeTime(t))
+	if h.author != "" {
+		desc += " · " + h.author
+	}
+	return desc
+}
+
+// historyChanges reduces counts, newest first, to the commits where the
+// count changed from the commit before, newest first. Until the walk is
+// done, the oldest commit walked has no known predecessor, so it's only
+// reported once it's known to be the module's first commit.
+func historyChanges(counts []historyCount, done bool) []*historyChange {
+	var changes []*historyChange
+	for i, count := range counts {
+		from := 0
+		if i+1 < len(counts) {
+			from = counts[i+1].count
+		} else if !done {
+			continue
+		}
+		if count.count == from {
+			continue
+		}
+		changes = append(changes, &historyChange{commit: count.commit, from: from, to: count.count})
+	}
+	return changes
+}
+
+// historyShown reports whether the commits view shows a history search --
+// its input or its results -- in place of the commit list.
+func (m model) historyShown() bool {
+	return m.historyInputActive || m.historyQuery != ""
+}
+
+// startHistorySearch starts walking the module's commits for the history
+// input's query (a grepPattern), or leaves the input open with the error if
+// it doesn't compile. An empty query closes the search.
+func (m *model) startHistorySearch() tea.Cmd {
+	query := m.historyInput.Value()
+	if query == "" {
+		m.resetHistory()
+		return nil
+	}
+	pattern, err := grepPattern(query)
+	if err != nil {
+		m.historyErr = err
+		return nil
+	}
+	m.resetHistory()
+	m.historyQuery = query
+	m.historyPattern = pattern
+	m.historyOwner = m.currentOwner
+	m.historyModule = m.currentModule
+	m.historySearching = true
+	return m.client.historySearchStep(m.historySearchID, m.currentOwner, m.currentModule, pattern, nil, "")
+}
+
+// continueHistorySearch records a step of the history search and takes the
+// next, if there's anything left to walk.
+func (m *model) continueHistorySearch(msg historyStepMsg) tea.Cmd {
+	m.historyCounts = append(m.historyCounts, msg.counts...)
+	done := len(msg.pending) == 0 && msg.nextPageToken == ""
+	m.historySearching = !done
+	changes := historyChanges(m.historyCounts, done)
+	items := make([]list.Item, len(changes))
+	for i, change := range changes {
+		change.author = m.commitAuthors[change.commit.CreatedByUserId]
+		items[i] = change
+	}
+	cmd := m.historyList.SetItems(items)
+	if done {
+		return cmd
+	}
+	return tea.Batch(cmd, m.client.historySearchStep(msg.id, m.historyOwner, m.historyModule, m.historyPattern, msg.pending, msg.nextPageToken))
+}
+
+// openHistoryInput opens the history search input, holding the current
+// search's query to edit.
+func (m *model) openHistoryInput() {
+	m.historyInputActive = true
+	m.historyErr = nil
+	m.historyInput.SetValue(m.historyQuery)
+	m.historyInput.CursorEnd()
+	m.historyInput.Focus()
+}
+
+// resetHistory closes the history search, dropping its results and any
+// steps still in flight.
+func (m *model) resetHistory() {
+	m.historySearchID++
+	m.historyInputActive = false
+	m.historyErr = nil
+	m.historyQuery = ""
+	m.historyPattern = nil
+	m.historyOwner = ""
+	m.historyModule = ""
+	m.historySearching = false
+	m.historyCounts = nil
+	m.historyList.ResetFilter()
+	m.historyList.SetItems(nil)
+}
+
+// historyStatusView renders the line above the history search's results:
+// the input while it's open, then where the query first and last appeared
+// and how far the walk has got.
+func (m model) historyStatusView() string {
+	style := m.listStyles.StatusBar.Padding(0, 0, 1, 2).MaxWidth(m.historyList.Width())
+	if m.historyInputActive {
+		view := "search history " + m.historyInput.View()
+		if m.historyErr != nil {
+			view += " " + lipgloss.NewStyle().Foreground(colorError).Render(m.historyErr.Error())
+		}
+		return style.Render(view)
+	}
+	var status string
+	if m.historyErr != nil {
+		status = lipgloss.NewStyle().Foreground(colorError).Render("Error searching history: " + m.historyErr.Error())
+	} else {
+		status = historySummary(m.historyQuery, m.historyCounts)
+	}
+	if m.historySearching {
+		status = m.spinner.View() + fmt.Sprintf(" Searched %d commits · ", len(m.historyCounts)) + status
+	}
+	return style.Render(status)
+}
+
+// historySummary reports the oldest and newest commits query appears in,
+// among counts (newest first). While the walk is still going, the oldest
+// is only the oldest so far.
+func historySummary(query string, counts []historyCount) string {
+	first, last := -1, -1
+	for i, count := range counts {
+		if count.count > 0 {
+			if last < 0 {
+				last = i
+			}
+			first = i
+		}
+	}
+	if last < 0 {
+		return fmt.Sprintf("%q doesn't appear in any of %d commits", query, len(counts))
+	}
+	lastDesc := "the latest commit"
+	if last > 0 {
+		lastDesc = shortCommitWithAge(counts[last].commit)
+	}
+	return fmt.Sprintf("%q first appears in %s, last in %s", query, shortCommitWithAge(counts[first].commit), lastDesc)
+}
+
+func shortCommitWithAge(commit *modulev1.Commit) string {
+	return fmt.Sprintf("%s (%s)", commit.Id[:min(12, len(commit.Id))], relativeTime(commit.CreateTime.AsTime()))
+}
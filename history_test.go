@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testHistoryCounts(counts ...int) []historyCount {
+	// Newest first, as the walk finds them.
+	history := make([]historyCount, len(counts))
+	for i, count := range counts {
+		history[i] = historyCount{
+			commit: &modulev1.Commit{
+				Id:         string(rune('a'+i)) + "00000000000",
+				CreateTime: timestamppb.New(time.Now().Add(-time.Duration(i) * time.Hour)),
+			},
+			count: count,
+		}
+	}
+	return history
+}
+
+func TestHistoryChanges(t *testing.T) {
+	t.Parallel()
+
+	// Oldest to newest: absent, added, grown, grown again, removed.
+	counts := testHistoryCounts(0, 3, 2, 2, 1, 0)
+	var got []string
+	for _, change := range historyChanges(counts, true) {
+		got = append(got, change.Title())
+	}
+	ok.Equal(t, strings.Join(got, "\n"), strings.Join([]string{
+		"a00000000000 removed (3)",
+		"b00000000000 2 → 3",
+		"d00000000000 1 → 2",
+		"e00000000000 added (1)",
+	}, "\n"))
+
+	// Until the walk is done, the oldest commit so far has nothing to be
+	// compared against.
+	got = nil
+	for _, change := range historyChanges(testHistoryCounts(1, 1, 2), false) {
+		got = append(got, change.Title())
+	}
+	ok.Equal(t, strings.Join(got, "\n"), "b00000000000 2 → 1")
+	// Once done, a query present from the very first commit was added there.
+	ok.Equal(t, historyChanges(testHistoryCounts(1), true)[0].Title(), "a00000000000 added (1)")
+}
+
+func TestHistorySummary(t *testing.T) {
+	t.Parallel()
+
+	ok.Equal(t, historySummary("x", testHistoryCounts(0, 0)), `"x" doesn't appear in any of 2 commits`)
+	summary := historySummary("x", testHistoryCounts(1, 1, 0))
+	ok.True(t, strings.HasPrefix(summary, `"x" first appears in b00000000000 (`), ok.Sprintf("got %q", summary))
+	ok.True(t, strings.HasSuffix(summary, "last in the latest commit"), ok.Sprintf("got %q", summary))
+	summary = historySummary("x", testHistoryCounts(0, 1, 0))
+	ok.True(t, strings.Contains(summary, "last in b00000000000"), ok.Sprintf("got %q", summary))
+}
+
+// TestHistorySearch_WalksCommitsAndOpensChange verifies a history search
+// walks every commit, reports where the query appeared, and opens a commit
+// it changed in.
+func TestHistorySearch_WalksCommitsAndOpensChange(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	updated, _ := m.Update(c.listCommits(m.currentOwner, m.currentModule)())
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingCommits)
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'H', Text: "H"})
+	m = updated.(model)
+	ok.True(t, m.historyInputActive)
+	for _, r := range "module" {
+		updated, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+		m = updated.(model)
+	}
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	ok.True(t, m.historySearching)
+	step := cmd().(historyStepMsg)
+	updated, _ = m.Update(step)
+	m = updated.(model)
+	ok.True(t, !m.historySearching, ok.Sprintf("expected one step to cover the fake's three commits"))
+	ok.Equal(t, len(m.historyCounts), 3)
+
+	view := ansi.Strip(m.View().Content)
+	ok.True(t, strings.Contains(view, `"module" first appears in def456ghi789`), ok.Sprintf("expected the summary:\n%s", view))
+	ok.True(t, strings.Contains(view, "last in the latest commit"), ok.Sprintf("expected the summary:\n%s", view))
+	ok.True(t, strings.Contains(view, "def456ghi789 added (1)"), ok.Sprintf("expected the change:\n%s", view))
+
+	// A step from a search that has since been replaced is dropped.
+	stale := step
+	stale.id--
+	updated, _ = m.Update(stale)
+	m = updated.(model)
+	ok.Equal(t, len(m.historyCounts), 3)
+
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingCommitFileContents)
+	ok.Equal(t, m.currentCommitID, "def456ghi789")
+	ok.True(t, cmd != nil)
+
+	// Coming back to the same module keeps the results.
+	updated, _ = m.Update(c.listCommits(m.currentOwner, m.currentModule)())
+	m = updated.(model)
+	ok.Equal(t, m.historyQuery, "module")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(model)
+	ok.Equal(t, m.historyQuery, "")
+	ok.Equal(t, m.state, modelStateBrowsingCommits)
+}
@@ -32,6 +32,8 @@ type module struct {
 	underlying *modulev1.Module
 	remote     string
 	owner      string
+	// columns lays the module out as a table row, if set.
+	columns *moduleColumns
 }
 
 // FilterValue implements [list.Item].
@@ -41,12 +43,20 @@ func (m *module) FilterValue() string {
 
 // Title implements [list.DefaultItem].
 func (m *module) Title() string {
+	if m.columns != nil {
+		return m.columns.row(m.underlying)
+	}
+	return moduleTitle(m.underlying)
+}
+
+// moduleTitle returns module's name, marked if it's private or deprecated.
+func moduleTitle(module *modulev1.Module) string {
 	var title string
-	if m.underlying.Visibility == modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE {
+	if module.Visibility == modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE {
 		title += "󰎠"
 	}
-	title += m.underlying.Name
-	if m.underlying.State == modulev1.ModuleState_MODULE_STATE_DEPRECATED {
+	title += module.Name
+	if module.State == modulev1.ModuleState_MODULE_STATE_DEPRECATED {
 		title += " (Deprecated)"
 	}
 	return title
@@ -62,22 +72,41 @@ type commit struct {
 	remote     string
 	owner      string
 	moduleName string
+	// author is the name of the user who pushed the commit, once resolved.
+	author string
+	// labels are the names of the labels pointing at the commit.
+	labels []string
 }
 
-// FilterValue implements list.Item.
+// FilterValue implements list.Item. The author and labels are included,
+// prefixed as [commitFilter] expects, so the list can be filtered by them.
 func (m *commit) FilterValue() string {
-	return m.underlying.Id
+	value := m.underlying.Id
+	if m.author != "" {
+		value += " " + commitFilterAuthorPrefix + m.author
+	}
+	for _, label := range m.labels {
+		value += " " + commitFilterLabelPrefix + label
+	}
+	return value
 }
 
 // Title implements list.DefaultItem.
 func (m *commit) Title() string {
-	return m.underlying.Id
+	title := m.underlying.Id
+	for _, label := range m.labels {
+		title += " [" + label + "]"
+	}
+	return title
 }
 
 // Description implements list.DefaultItem.
 func (m *commit) Description() string {
 	t := m.underlying.CreateTime.AsTime()
 	desc := fmt.Sprintf("%s (%s)", t.Format(time.Stamp), relativeTime(t))
+	if m.author != "" {
+		desc += " · " + m.author
+	}
 	if scURL := m.underlying.SourceControlUrl; scURL != "" {
 		desc += " · " + shortenSourceControlURL(scURL)
 	}
@@ -126,6 +155,10 @@ type commitFile struct {
 	owner      string
 	moduleName string
 	commitID   string
+	// diff is how the file differs from the commit the Files tab is diffed
+	// against, if any (see diff.go). A removed file's underlying is the
+	// file as it was there.
+	diff fileDiffStatus
 }
 
 // FilterValue implements list.Item.
@@ -135,7 +168,7 @@ func (m *commitFile) FilterValue() string {
 
 // Title implements list.DefaultItem.
 func (m *commitFile) Title() string {
-	return m.underlying.Path
+	return m.diff.withMarker(m.underlying.Path)
 }
 
 // Description implements list.DefaultItem.
@@ -159,10 +192,14 @@ func (l *labelItem) FilterValue() string {
 
 // Title implements list.DefaultItem.
 func (l *labelItem) Title() string {
+	title := l.underlying.Name
 	if l.isDefault {
-		return l.underlying.Name + " (default)"
+		title += " (default)"
 	}
-	return l.underlying.Name
+	if l.underlying.ArchiveTime != nil {
+		title += " (archived)"
+	}
+	return title
 }
 
 // Description implements list.DefaultItem.
@@ -170,3 +207,37 @@ func (l *labelItem) Description() string {
 	t := l.underlying.UpdateTime.AsTime()
 	return fmt.Sprintf("%s · %s", l.underlying.CommitId[:12], relativeTime(t))
 }
+
+// labelHistoryItem is one commit in a label's history. The BSR doesn't
+// record when a label was moved, only the commits it has pointed to, so the
+// time shown is when the commit was pushed.
+type labelHistoryItem struct {
+	underlying *modulev1.Commit
+	// author is the name of the user who pushed the commit, if known.
+	author string
+	// current is set on the commit the label points to now.
+	current bool
+}
+
+// FilterValue implements list.Item.
+func (l *labelHistoryItem) FilterValue() string {
+	return l.underlying.Id + " " + l.author
+}
+
+// Title implements list.DefaultItem.
+func (l *labelHistoryItem) Title() string {
+	if l.current {
+		return l.underlying.Id + " (current)"
+	}
+	return l.underlying.Id
+}
+
+// Description implements list.DefaultItem.
+func (l *labelHistoryItem) Description() string {
+	t := l.underlying.CreateTime.AsTime()
+	desc := fmt.Sprintf("%s (%s)", t.Format(time.Stamp), relativeTime(t))
+	if l.author != "" {
+		desc += " · " + l.author
+	}
+	return desc
+}
@@ -29,6 +29,39 @@ type keyMap struct {
 	Search     key.Binding
 	SearchNext key.Binding
 	SearchPrev key.Binding
+	Export     key.Binding
+
+	ExportWithDeps key.Binding
+	ExportSPDX     key.Binding
+
+	DescriptorSetFormat     key.Binding
+	DescriptorSetSourceInfo key.Binding
+	DescriptorSetImports    key.Binding
+
+	ToggleArchived key.Binding
+	Sort           key.Binding
+	ToggleTree     key.Binding
+	Grep           key.Binding
+	HistorySearch  key.Binding
+	Blame          key.Binding
+	GotoLine       key.Binding
+	Wrap           key.Binding
+	Diff           key.Binding
+	DiffLayout     key.Binding
+	Plugins        key.Binding
+	OwnerInfo      key.Binding
+	Activity       key.Binding
+	ModuleFilter   key.Binding
+
+	Deprecate  key.Binding
+	Visibility key.Binding
+	EditModule key.Binding
+
+	NewLabel     key.Binding
+	MoveLabel    key.Binding
+	ArchiveLabel key.Binding
+	Confirm      key.Binding
+	Cancel       key.Binding
 }
 
 var keys = keyMap{
@@ -76,7 +109,7 @@ var keys = keyMap{
 	),
 	Yank: key.NewBinding(
 		key.WithKeys("y"),
-		key.WithHelp("y", "copy URL"),
+		key.WithHelp("y", "copy"),
 	),
 	BrowseSCM: key.NewBinding(
 		key.WithKeys("O"),
@@ -102,9 +135,131 @@ var keys = keyMap{
 		key.WithKeys("N"),
 		key.WithHelp("N", "prev match"),
 	),
+	Export: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "export"),
+	),
+	ExportWithDeps: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "export with deps"),
+	),
+	ExportSPDX: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "export SPDX SBOM"),
+	),
+	DescriptorSetFormat: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "format"),
+	),
+	DescriptorSetSourceInfo: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "source info"),
+	),
+	DescriptorSetImports: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("ctrl+o", "imports"),
+	),
+	ToggleArchived: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "toggle archived"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort"),
+	),
+	ToggleTree: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tree / list"),
+	),
+	Grep: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "grep files"),
+	),
+	HistorySearch: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "search history"),
+	),
+	Blame: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "blame"),
+	),
+	GotoLine: key.NewBinding(
+		key.WithKeys(":"),
+		key.WithHelp(":", "go to line"),
+	),
+	Wrap: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "toggle wrap"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "diff against"),
+	),
+	DiffLayout: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "unified / side by side"),
+	),
+	Plugins: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "plugins"),
+	),
+	ModuleFilter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter by visibility/state"),
+	),
+	Activity: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "activity"),
+	),
+	OwnerInfo: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "owner info"),
+	),
+	NewLabel: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "label commit"),
+	),
+	MoveLabel: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "move label here"),
+	),
+	ArchiveLabel: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "(un)archive label"),
+	),
+	Deprecate: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "(un)deprecate"),
+	),
+	Visibility: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "public / private"),
+	),
+	EditModule: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y", "enter"),
+		key.WithHelp("y", "confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("n", "esc"),
+		key.WithHelp("n", "cancel"),
+	),
 }
 
 func (m model) ShortHelp() []key.Binding {
+	if m.descriptorSetInputActive {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "write")),
+			withHelp(keys.Back, "cancel"),
+			keys.DescriptorSetFormat,
+			keys.DescriptorSetSourceInfo,
+			keys.DescriptorSetImports,
+			keys.Help,
+		}
+	}
 	var shortHelp []key.Binding
 	switch m.state {
 	case modelStateBrowsingModules:
@@ -114,44 +269,112 @@ func (m model) ShortHelp() []key.Binding {
 			// Can only go right when modules exist.
 			shortHelp = append(shortHelp, keys.Right)
 		}
+		shortHelp = append(shortHelp, keys.Sort, keys.ModuleFilter, withHelp(keys.ToggleTree, "table / list"), keys.Plugins, keys.Activity, keys.OwnerInfo)
+		if module, ok := m.moduleList.SelectedItem().(*module); ok && m.canWriteModule(module.underlying.Name) {
+			shortHelp = append(shortHelp, keys.Deprecate, keys.Visibility, keys.EditModule)
+		}
+	case modelStateBrowsingOwner:
+		shortHelp = []key.Binding{withHelp(keys.Back, "modules")}
+	case modelStateBrowsingActivity:
+		shortHelp = []key.Binding{keys.Up, keys.Down, withHelp(keys.Back, "modules"), withHelp(keys.Search, "filter")}
+		if len(m.activityList.Items()) != 0 {
+			shortHelp = append(shortHelp, withHelp(keys.Right, "open commit"))
+		}
+	case modelStateBrowsingPlugins:
+		shortHelp = []key.Binding{keys.Up, keys.Down, withHelp(keys.Back, "modules")}
+		if len(m.pluginList.Items()) != 0 {
+			shortHelp = append(shortHelp, withHelp(keys.Right, "commits"))
+		}
+	case modelStateBrowsingPluginCommits:
+		shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, withHelp(keys.Search, "filter")}
 	case modelStateBrowsingCommits:
+		switch {
+		case m.historyInputActive:
+			return []key.Binding{
+				key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "search")),
+				withHelp(keys.Back, "cancel"),
+				keys.Help,
+			}
+		case m.historyQuery != "":
+			return []key.Binding{keys.Up, keys.Down, withHelp(keys.Back, "close search"), withHelp(keys.Right, "open commit"), keys.HistorySearch, keys.Help}
+		}
 		shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, keys.Yank}
 		if commit, ok := m.commitList.SelectedItem().(*commit); ok && commit.underlying.SourceControlUrl != "" {
 			shortHelp = append(shortHelp, keys.BrowseSCM)
 		}
 		if len(m.currentCommits) != 0 {
-			shortHelp = append(shortHelp, keys.Right)
+			shortHelp = append(shortHelp, keys.Right, keys.HistorySearch, withHelp(keys.Diff, "diff against this"), withHelp(keys.Export, "export files"), keys.ExportWithDeps)
+			if m.canWriteLabels() {
+				shortHelp = append(shortHelp, keys.NewLabel)
+			}
 		}
 	case modelStateBrowsingCommitContents:
 		shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, keys.TabLeft, keys.TabRight}
 		switch m.activeCommitTab {
 		case commitTabDocs:
 			if len(m.docsList.Items()) > 0 {
-				shortHelp = append(shortHelp, keys.Right)
+				shortHelp = append(shortHelp, keys.Right, keys.Blame, withHelp(keys.Export, "write descriptor set"))
 			}
 		case commitTabFiles:
-			shortHelp = append(shortHelp, keys.Yank, keys.Right)
+			switch {
+			case m.grepInputActive:
+				return []key.Binding{
+					key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "grep")),
+					withHelp(keys.Back, "cancel"),
+					keys.Help,
+				}
+			case m.grepQuery != "":
+				shortHelp = []key.Binding{keys.Up, keys.Down, withHelp(keys.Back, "close grep"), withHelp(keys.Right, "open"), keys.Grep, keys.Yank}
+			default:
+				shortHelp = append(shortHelp, keys.Yank, keys.Right, withHelp(keys.Search, "filter"), keys.ToggleTree, keys.Grep, keys.Diff)
+				if m.diffing() {
+					shortHelp = append(shortHelp, keys.DiffLayout)
+				}
+			}
 		case commitTabLabels:
 			if len(m.currentLabels) > 0 {
-				shortHelp = append(shortHelp, keys.Right)
+				shortHelp = append(shortHelp, withHelp(keys.Right, "history"), keys.Sort, withHelp(keys.Export, "export files"), keys.ExportWithDeps)
+			}
+			shortHelp = append(shortHelp, keys.ToggleArchived)
+			if m.canWriteLabels() {
+				shortHelp = append(shortHelp, keys.NewLabel)
+				if len(m.currentLabels) > 0 {
+					shortHelp = append(shortHelp, keys.MoveLabel, keys.ArchiveLabel)
+				}
 			}
 		case commitTabDeps:
 			if m.depsLoaded {
-				shortHelp = append(shortHelp, keys.Right, keys.Yank, keys.Browse)
+				shortHelp = append(shortHelp, keys.Right, keys.Yank, keys.Browse, withHelp(keys.Export, "export CycloneDX SBOM"), keys.ExportSPDX)
+			}
+		case commitTabSDKs:
+			if m.sdksLoaded {
+				shortHelp = append(shortHelp, keys.Yank, withHelp(keys.Search, "filter"))
 			}
 		}
 	case modelStateBrowsingCommitFileContents:
-		if m.activeCommitTab == commitTabDocs {
+		if m.activeCommitTab == commitTabLabels {
+			shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, withHelp(keys.Right, "open commit"), keys.TabLeft, keys.TabRight}
+		} else if m.activeCommitTab == commitTabDocs {
 			if m.docsSearchActive {
 				shortHelp = []key.Binding{
 					key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "search")),
 					keys.Back,
 				}
 			} else {
-				shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, keys.Search, keys.SearchNext, keys.SearchPrev, keys.TabLeft, keys.TabRight}
+				shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, keys.Search, keys.SearchNext, keys.SearchPrev, keys.Blame, withHelp(keys.Export, "write descriptor set"), keys.TabLeft, keys.TabRight}
+			}
+		} else if m.fileSearchActive || m.fileGotoActive || m.diffInputActive {
+			return []key.Binding{
+				key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "go")),
+				withHelp(keys.Back, "cancel"),
+				keys.Help,
 			}
 		} else {
-			shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, keys.Yank, keys.TabLeft, keys.TabRight}
+			shortHelp = []key.Binding{keys.Up, keys.Down, keys.Back, keys.Yank, keys.Search, keys.SearchNext, keys.SearchPrev, keys.GotoLine, keys.Wrap, keys.Diff}
+			if m.diffing() {
+				shortHelp = append(shortHelp, keys.DiffLayout)
+			}
+			shortHelp = append(shortHelp, keys.TabLeft, keys.TabRight)
 		}
 	case modelStateNavigating:
 		shortHelp = []key.Binding{keys.Enter, keys.Back}
@@ -180,7 +403,7 @@ func newNavigateInput() textinput.Model {
 	input := textinput.New()
 	input.Validate = func(inputStr string) error {
 		// Try to parse as a complete reference first.
-		if _, _, err := parseReference(inputStr); err == nil {
+		if _, _, _, err := parseReference(inputStr); err == nil {
 			return nil
 		}
 		// A slash indicates a partial reference being typed (e.g. "owner/"
@@ -204,3 +427,56 @@ func newDocsSearchInput() textinput.Model {
 	input.Placeholder = "search docs"
 	return input
 }
+
+func newGrepInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "text, or /regexp/"
+	return input
+}
+
+func newHistoryInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "text, or /regexp/"
+	return input
+}
+
+func newFileSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "search file"
+	return input
+}
+
+func newFileGotoInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "line number"
+	return input
+}
+
+func newDiffInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "commit or label"
+	return input
+}
+
+func newDescriptorSetInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "file.binpb"
+	return input
+}
+
+func newLabelNameInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "label name"
+	return input
+}
+
+func newModuleEditInput() textinput.Model {
+	return textinput.New()
+}
+
+// withHelp returns a copy of binding described as desc, for a key whose
+// action depends on where it's pressed.
+func withHelp(binding key.Binding, desc string) key.Binding {
+	binding.SetHelp(binding.Help().Key, desc)
+	return binding
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"connectrpc.com/connect"
+)
+
+// labelArchiveFilter is which labels the Labels tab (and label suggestions
+// in the navigate input) include. The zero value is the BSR's own default,
+// unarchived labels only.
+type labelArchiveFilter int
+
+const (
+	labelArchiveFilterUnarchived labelArchiveFilter = iota
+	labelArchiveFilterAll
+	labelArchiveFilterArchived
+	labelArchiveFilterCount
+)
+
+func (f labelArchiveFilter) proto() modulev1.ListLabelsRequest_ArchiveFilter {
+	switch f {
+	case labelArchiveFilterAll:
+		return modulev1.ListLabelsRequest_ARCHIVE_FILTER_ALL
+	case labelArchiveFilterArchived:
+		return modulev1.ListLabelsRequest_ARCHIVE_FILTER_ARCHIVED_ONLY
+	default:
+		return modulev1.ListLabelsRequest_ARCHIVE_FILTER_UNARCHIVED_ONLY
+	}
+}
+
+// next cycles unarchived -> all -> archived -> unarchived.
+func (f labelArchiveFilter) next() labelArchiveFilter {
+	return (f + 1) % labelArchiveFilterCount
+}
+
+// itemNames returns the singular and plural names the labels list's status
+// bar counts labels with, so the active filter is always visible.
+func (f labelArchiveFilter) itemNames() (string, string) {
+	switch f {
+	case labelArchiveFilterAll:
+		return "label (incl. archived)", "labels (incl. archived)"
+	case labelArchiveFilterArchived:
+		return "archived label", "archived labels"
+	default:
+		return "label", "labels"
+	}
+}
+
+// labelSort is the order the Labels tab lists labels in. Whatever the
+// order, the module's default label comes first.
+type labelSort int
+
+const (
+	labelSortUpdateTime labelSort = iota
+	labelSortName
+	labelSortCount
+)
+
+func (s labelSort) String() string {
+	switch s {
+	case labelSortName:
+		return "name"
+	default:
+		return "update time"
+	}
+}
+
+func (s labelSort) next() labelSort {
+	return (s + 1) % labelSortCount
+}
+
+// sortLabels returns labels in the order the Labels tab shows them: the
+// default label (if present) first, then the rest by sort -- most recently
+// updated first, or alphabetically.
+func sortLabels(labels []*modulev1.Label, defaultLabelName string, sort labelSort) []*modulev1.Label {
+	sorted := slices.Clone(labels)
+	slices.SortStableFunc(sorted, func(a, b *modulev1.Label) int {
+		if aDefault, bDefault := a.Name == defaultLabelName, b.Name == defaultLabelName; aDefault != bDefault {
+			if aDefault {
+				return -1
+			}
+			return 1
+		}
+		if sort == labelSortName {
+			return strings.Compare(a.Name, b.Name)
+		}
+		return b.UpdateTime.AsTime().Compare(a.UpdateTime.AsTime())
+	})
+	return sorted
+}
+
+// labelAction is a change to a module's labels.
+type labelAction int
+
+const (
+	// labelActionPoint points a label at a commit, creating the label if it
+	// doesn't exist yet or moving it if it does -- the BSR does both with
+	// the one CreateOrUpdateLabels call.
+	labelActionPoint labelAction = iota
+	labelActionArchive
+	labelActionUnarchive
+)
+
+// labelWrite is a change to one of a module's labels, held while the user
+// confirms it.
+type labelWrite struct {
+	action labelAction
+	owner  string
+	module string
+	label  string
+	// commitID is the commit a labelActionPoint points label at.
+	commitID string
+	// fromCommitID is the commit label points at now, if it's known to
+	// exist; a labelActionPoint without one creates the label.
+	fromCommitID string
+}
+
+// prompt asks the user to confirm w.
+func (w labelWrite) prompt() string {
+	switch w.action {
+	case labelActionArchive:
+		return fmt.Sprintf("Archive label %q?", w.label)
+	case labelActionUnarchive:
+		return fmt.Sprintf("Unarchive label %q?", w.label)
+	default:
+		if w.fromCommitID != "" {
+			return fmt.Sprintf("Move label %q from commit %s to %s?", w.label, shortCommitID(w.fromCommitID), shortCommitID(w.commitID))
+		}
+		return fmt.Sprintf("Point label %q at commit %s?", w.label, shortCommitID(w.commitID))
+	}
+}
+
+// done reports that w has been carried out.
+func (w labelWrite) done() string {
+	switch w.action {
+	case labelActionArchive:
+		return fmt.Sprintf("archived label %q", w.label)
+	case labelActionUnarchive:
+		return fmt.Sprintf("unarchived label %q", w.label)
+	default:
+		return fmt.Sprintf("pointed label %q at commit %s", w.label, shortCommitID(w.commitID))
+	}
+}
+
+type labelWrittenMsg struct {
+	write labelWrite
+}
+
+type labelWriteErrMsg struct {
+	write labelWrite
+	err   error
+}
+
+func (e labelWriteErrMsg) Error() string { return e.err.Error() }
+
+// permissionDenied reports whether the write failed because the token
+// isn't allowed to manage the module's labels (or there's no token at all).
+func (e labelWriteErrMsg) permissionDenied() bool {
+	var connectErr *connect.Error
+	if !errors.As(e.err, &connectErr) {
+		return false
+	}
+	return connectErr.Code() == connect.CodePermissionDenied || connectErr.Code() == connect.CodeUnauthenticated
+}
+
+// labelRef refers to w's label.
+func (w labelWrite) labelRef() *modulev1.LabelRef {
+	return &modulev1.LabelRef{
+		Value: &modulev1.LabelRef_Name_{
+			Name: &modulev1.LabelRef_Name{
+				Owner:  w.owner,
+				Module: w.module,
+				Label:  w.label,
+			},
+		},
+	}
+}
+
+// labelPointMsg carries a labelActionPoint to confirm, with its
+// fromCommitID filled in.
+type labelPointMsg struct {
+	write labelWrite
+}
+
+// preparePointLabel looks up the commit w's label points at now, if it
+// exists, so the confirmation can say whether it's created or moved. The
+// label name input is opened from the commit list, where currentLabels may
+// not be loaded, may be another module's, or may leave out archived labels,
+// so they can't be relied on.
+func (c *client) preparePointLabel(w labelWrite) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		response, err := c.labelServiceClient.GetLabels(ctx, connect.NewRequest(&modulev1.GetLabelsRequest{
+			LabelRefs: []*modulev1.LabelRef{w.labelRef()},
+		}))
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return labelPointMsg{w}
+		}
+		if err != nil {
+			return labelWriteErrMsg{w, fmt.Errorf("getting label %q: %w", w.label, err)}
+		}
+		if len(response.Msg.Labels) != 1 {
+			return labelWriteErrMsg{w, fmt.Errorf("getting label %q: expected 1 label, got %d", w.label, len(response.Msg.Labels))}
+		}
+		w.fromCommitID = response.Msg.Labels[0].CommitId
+		return labelPointMsg{w}
+	}
+}
+
+// writeLabel carries out w.
+func (c *client) writeLabel(w labelWrite) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		labelRef := w.labelRef()
+		var err error
+		switch w.action {
+		case labelActionArchive:
+			_, err = c.labelServiceClient.ArchiveLabels(ctx, connect.NewRequest(&modulev1.ArchiveLabelsRequest{
+				LabelRefs: []*modulev1.LabelRef{labelRef},
+			}))
+		case labelActionUnarchive:
+			_, err = c.labelServiceClient.UnarchiveLabels(ctx, connect.NewRequest(&modulev1.UnarchiveLabelsRequest{
+				LabelRefs: []*modulev1.LabelRef{labelRef},
+			}))
+		default:
+			_, err = c.labelServiceClient.CreateOrUpdateLabels(ctx, connect.NewRequest(&modulev1.CreateOrUpdateLabelsRequest{
+				Values: []*modulev1.CreateOrUpdateLabelsRequest_Value{{
+					LabelRef: labelRef,
+					CommitId: w.commitID,
+				}},
+			}))
+		}
+		if err != nil {
+			return labelWriteErrMsg{w, fmt.Errorf("updating label %q: %w", w.label, err)}
+		}
+		return labelWrittenMsg{w}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSortLabels(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	label := func(name string, updatedAgo time.Duration) *modulev1.Label {
+		return &modulev1.Label{Name: name, UpdateTime: timestamppb.New(now.Add(-updatedAgo))}
+	}
+	labels := []*modulev1.Label{
+		label("v1.0.0", 3*time.Hour),
+		label("main", 2*time.Hour),
+		label("dev", time.Hour),
+		label("v2.0.0", 30*time.Minute),
+	}
+	names := func(labels []*modulev1.Label) []string {
+		names := make([]string, len(labels))
+		for i, l := range labels {
+			names[i] = l.Name
+		}
+		return names
+	}
+
+	ok.DeepEqual(t, names(sortLabels(labels, "main", labelSortUpdateTime)), []string{"main", "v2.0.0", "dev", "v1.0.0"})
+	ok.DeepEqual(t, names(sortLabels(labels, "main", labelSortName)), []string{"main", "dev", "v1.0.0", "v2.0.0"})
+	ok.DeepEqual(t, names(sortLabels(labels, "", labelSortName)), []string{"dev", "main", "v1.0.0", "v2.0.0"}, ok.Sprintf("no default label"))
+	ok.Equal(t, labels[0].Name, "v1.0.0", ok.Sprintf("the input shouldn't be reordered"))
+}
+
+func TestLabelArchiveFilter_Next(t *testing.T) {
+	t.Parallel()
+
+	f := labelArchiveFilterUnarchived
+	var seen []modulev1.ListLabelsRequest_ArchiveFilter
+	for range labelArchiveFilterCount {
+		seen = append(seen, f.proto())
+		f = f.next()
+	}
+	ok.Equal(t, f, labelArchiveFilterUnarchived, ok.Sprintf("cycling should come back around"))
+	ok.DeepEqual(t, seen, []modulev1.ListLabelsRequest_ArchiveFilter{
+		modulev1.ListLabelsRequest_ARCHIVE_FILTER_UNARCHIVED_ONLY,
+		modulev1.ListLabelsRequest_ARCHIVE_FILTER_ALL,
+		modulev1.ListLabelsRequest_ARCHIVE_FILTER_ARCHIVED_ONLY,
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"cmp"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -15,6 +17,8 @@ import (
 	"time"
 
 	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
+	pluginv1beta1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/plugin/v1beta1"
 	"buf.build/go/protovalidate"
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
@@ -50,6 +54,15 @@ func main() {
 	}
 }
 
+// subcommandUsage lists the headless subcommands, which run once against the
+// BSR and exit instead of starting the TUI.
+const subcommandUsage = `
+Subcommands:
+  sbom         Write a CycloneDX or SPDX SBOM for a commit's dependency graph
+  export       Write a commit's files, and optionally its dependencies', to a directory
+  descriptors  Write a commit's FileDescriptorSet, as binary, JSON or text
+`
+
 type runFlags struct {
 	remote    string
 	token     string
@@ -58,7 +71,22 @@ type runFlags struct {
 
 func parseRunFlags(args []string) (runFlags, error) {
 	var flags runFlags
-	fs := flag.NewFlagSet("buftui", flag.ContinueOnError)
+	fs := newFlagSet("buftui", &flags)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags]\n       %s <subcommand> [flags]\n", fs.Name(), fs.Name())
+		fs.PrintDefaults()
+		fmt.Fprint(fs.Output(), subcommandUsage)
+	}
+	if err := parseFlagSet(fs, args); err != nil {
+		return runFlags{}, err
+	}
+	return flags, nil
+}
+
+// newFlagSet returns a flag set named name with the connection flags shared
+// by the TUI and every headless subcommand registered into flags.
+func newFlagSet(name string, flags *runFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
 	fs.SetOutput(os.Stdout)
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), "Usage: %s [flags]\n", fs.Name())
@@ -69,46 +97,42 @@ func parseRunFlags(args []string) (runFlags, error) {
 	fs.StringVar(&flags.token, "token", "", "Set token for authentication (default: password for remote in ~/.netrc)")
 	fs.StringVar(&flags.token, "t", "", "Set token for authentication (default: password for remote in ~/.netrc)")
 	// `-r` is for reference, which should generally be preferred.
-	fs.StringVar(&flags.reference, "reference", "", "Set BSR reference to open")
-	fs.StringVar(&flags.reference, "r", "", "Set BSR reference to open")
+	fs.StringVar(&flags.reference, "reference", "", "Set BSR reference to open, optionally as of a time (e.g. owner/module:main@2026-09-01)")
+	fs.StringVar(&flags.reference, "r", "", "Set BSR reference to open, optionally as of a time (e.g. owner/module:main@2026-09-01)")
+	return fs
+}
 
+func parseFlagSet(fs *flag.FlagSet, args []string) error {
 	if err := fs.Parse(args); err != nil {
 		// flag.Parse already invokes Usage for its built-in -h/--help handling.
 		if err != flag.ErrHelp {
 			fs.Usage()
 		}
-		return runFlags{}, err
+		return err
 	}
-	return flags, nil
+	return nil
 }
 
-func run(_ context.Context, args []string) error {
+func run(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "sbom":
+			return runSBOM(ctx, args[1:])
+		case "export":
+			return runExport(ctx, args[1:])
+		case "descriptors":
+			return runDescriptors(ctx, args[1:])
+		}
+	}
+
 	flags, err := parseRunFlags(args)
 	if err != nil {
 		return err
 	}
 
-	parsedRemote, parsedReference, err := parseReference(flags.reference)
+	remote, token, parsedReference, asOf, err := resolveConnection(flags)
 	if err != nil {
-		return fmt.Errorf("parsing reference flag: %w", err)
-	}
-	if parsedRemote != "" && flags.remote != "" && flags.remote != parsedRemote {
-		return fmt.Errorf("cannot provide conflicting `--remote` flag (%s) and reference remote (%s)", flags.remote, parsedRemote)
-	}
-	// We know the remotes at least aren't conflicting, so take whichever is non-empty.
-	remote := cmp.Or(parsedRemote, flags.remote, defaultRemote)
-	// Sanity check for `--remote ""`, or an invalid parsed reference.
-	if remote == "" {
-		return fmt.Errorf("remote cannot be empty")
-	}
-
-	token := flags.token
-	if token == "" {
-		var err error
-		token, err = getTokenFromNetrc(remote)
-		if err != nil {
-			return fmt.Errorf("getting netrc credentials for remote %q: %w", remote, err)
-		}
+		return err
 	}
 
 	httpClient := httplb.NewClient()
@@ -128,6 +152,7 @@ func run(_ context.Context, args []string) error {
 	commitList := list.New(nil, delegate, 20, 20)
 	commitList.SetShowHelp(false)
 	commitList.SetStatusBarItemName("commit", "commits")
+	commitList.Filter = commitFilter
 
 	commitFilesList := list.New(nil, delegate, 20, 20)
 	commitFilesList.SetShowHelp(false)
@@ -139,6 +164,28 @@ func run(_ context.Context, args []string) error {
 	labelsList.SetShowTitle(false)
 	labelsList.SetStatusBarItemName("label", "labels")
 
+	pluginList := list.New(nil, delegate, 20, 20)
+	pluginList.SetShowHelp(false)
+	pluginList.SetStatusBarItemName("plugin", "plugins")
+
+	pluginCommitList := list.New(nil, delegate, 20, 20)
+	pluginCommitList.SetShowHelp(false)
+	pluginCommitList.SetStatusBarItemName("commit", "commits")
+
+	activityList := list.New(nil, delegate, 20, 20)
+	activityList.SetShowHelp(false)
+	activityList.SetStatusBarItemName("commit", "commits")
+	activityList.Filter = commitFilter
+
+	sdkList := list.New(nil, delegate, 20, 20)
+	sdkList.SetShowHelp(false)
+	sdkList.SetShowTitle(false)
+	sdkList.SetStatusBarItemName("SDK", "SDKs")
+
+	labelHistoryList := list.New(nil, delegate, 20, 20)
+	labelHistoryList.SetShowHelp(false)
+	labelHistoryList.SetStatusBarItemName("commit", "commits")
+
 	docsList := list.New(nil, delegate, 20, 20)
 	docsList.SetShowHelp(false)
 	docsList.SetShowTitle(false)
@@ -152,27 +199,62 @@ func run(_ context.Context, args []string) error {
 	// The deps tab renders a navigable tree; the app draws its own help bar.
 	depsTree := tree.New(nil, 0, 0)
 	depsTree.SetShowHelp(false)
+	filesTree := tree.New(nil, 0, 0)
+	filesTree.SetShowHelp(false)
+
+	// Grep results draw their own status bar above the list (see
+	// grepStatusView).
+	grepList := list.New(nil, delegate, 20, 20)
+	grepList.SetShowHelp(false)
+	grepList.SetShowTitle(false)
+	grepList.SetShowStatusBar(false)
+
+	// Likewise history search results (see historyStatusView).
+	historyList := list.New(nil, delegate, 20, 20)
+	historyList.SetShowHelp(false)
+	historyList.SetShowTitle(false)
+	historyList.SetShowStatusBar(false)
 
 	model := model{
-		state:            initialState,
-		spinner:          spinner.New(spinner.WithSpinner(spinner.Dot)),
-		client:           newClient(httpClient, remote, token),
-		help:             help.New(),
-		keys:             keys,
-		currentReference: parsedReference,
-		navigateInput:    newNavigateInput(),
-		docsSearchInput:  newDocsSearchInput(),
-		docsMatchIdx:     -1,
-		remote:           remote,
-		fileViewport:     viewport.New(),
-
-		moduleList:      moduleList,
-		commitList:      commitList,
-		commitFilesList: commitFilesList,
-		labelsList:      labelsList,
-		docsList:        docsList,
-		docsViewport:    docsViewport,
-		depsTree:        depsTree,
+		state:              initialState,
+		spinner:            spinner.New(spinner.WithSpinner(spinner.Dot)),
+		client:             newClient(httpClient, remote, token),
+		help:               help.New(),
+		keys:               keys,
+		currentReference:   parsedReference,
+		referenceAsOf:      asOf,
+		navigateInput:      newNavigateInput(),
+		docsSearchInput:    newDocsSearchInput(),
+		descriptorSetInput: newDescriptorSetInput(),
+		docsMatchIdx:       -1,
+		labelNameInput:     newLabelNameInput(),
+		moduleEditInput:    newModuleEditInput(),
+		grepInput:          newGrepInput(),
+		historyInput:       newHistoryInput(),
+		fileSearchInput:    newFileSearchInput(),
+		fileGotoInput:      newFileGotoInput(),
+		fileMatchIdx:       -1,
+		diffInput:          newDiffInput(),
+		authenticated:      token != "",
+		remote:             remote,
+		fileViewport:       viewport.New(),
+		overviewViewport:   viewport.New(),
+
+		moduleList:       moduleList,
+		commitList:       commitList,
+		commitFilesList:  commitFilesList,
+		labelsList:       labelsList,
+		sdkList:          sdkList,
+		pluginList:       pluginList,
+		pluginCommitList: pluginCommitList,
+		activityList:     activityList,
+		labelHistoryList: labelHistoryList,
+		docsList:         docsList,
+		docsViewport:     docsViewport,
+		depsTree:         depsTree,
+		filesTree:        filesTree,
+		grepList:         grepList,
+		historyList:      historyList,
 	}
 
 	// Style for a dark background up front -- the same assumption list.New
@@ -186,6 +268,34 @@ func run(_ context.Context, args []string) error {
 	return nil
 }
 
+// resolveConnection works out the remote and token to connect with from
+// flags, along with the parsed reference to open (and the time to open it
+// as of), if one was given.
+func resolveConnection(flags runFlags) (remote, token string, reference *modulev1.ResourceRef_Name, asOf time.Time, err error) {
+	parsedRemote, parsedReference, asOf, err := parseReference(flags.reference)
+	if err != nil {
+		return "", "", nil, time.Time{}, fmt.Errorf("parsing reference flag: %w", err)
+	}
+	if parsedRemote != "" && flags.remote != "" && flags.remote != parsedRemote {
+		return "", "", nil, time.Time{}, fmt.Errorf("cannot provide conflicting `--remote` flag (%s) and reference remote (%s)", flags.remote, parsedRemote)
+	}
+	// We know the remotes at least aren't conflicting, so take whichever is non-empty.
+	remote = cmp.Or(parsedRemote, flags.remote, defaultRemote)
+	// Sanity check for `--remote ""`, or an invalid parsed reference.
+	if remote == "" {
+		return "", "", nil, time.Time{}, fmt.Errorf("remote cannot be empty")
+	}
+
+	token = flags.token
+	if token == "" {
+		token, err = getTokenFromNetrc(remote)
+		if err != nil {
+			return "", "", nil, time.Time{}, fmt.Errorf("getting netrc credentials for remote %q: %w", remote, err)
+		}
+	}
+	return remote, token, parsedReference, asOf, nil
+}
+
 type modelState int
 
 const (
@@ -198,6 +308,20 @@ const (
 	modelStateLoadingModules
 	modelStateLoadingCommits
 	modelStateLoadingCommitFileContents
+	// The plugin states browse an owner's plugins rather than its modules
+	// (see plugins.go).
+	modelStateLoadingPlugins
+	modelStateBrowsingPlugins
+	modelStateLoadingPluginCommits
+	modelStateBrowsingPluginCommits
+	// The owner states show the profile of the owner whose modules are
+	// being browsed (see owner.go).
+	modelStateLoadingOwner
+	modelStateBrowsingOwner
+	// The activity states show the latest commits across all of an owner's
+	// modules (see activity.go).
+	modelStateLoadingActivity
+	modelStateBrowsingActivity
 )
 
 type model struct {
@@ -222,16 +346,49 @@ type model struct {
 	currentModule           string
 	currentDefaultLabelName string
 	currentCommitID         string
-	currentModules          modulesMsg
-	currentCommits          []*modulev1.Commit
-	nextCommitsPageToken    string
-	loadingMoreCommits      bool
-	currentCommitFiles      []*modulev1.File
-	currentReference        *modulev1.ResourceRef_Name
-	currentLabels           []*modulev1.Label
-	loadingLabels           bool
-	compiledDocs            *protoregistry.Files
-	loadingDocs             bool
+	// currentCommit is the commit whose contents are being browsed, and
+	// currentCommitAuthor and currentCommitLabels the details the commit
+	// header shows for it once fetched (see getCommitDetails).
+	currentCommit        *modulev1.Commit
+	currentCommitAuthor  string
+	currentCommitLabels  []string
+	loadingCommitDetails bool
+	commitDetailsErr     error
+	currentModules       []*modulev1.Module
+	nextModulesPageToken string
+	loadingMoreModules   bool
+	currentCommits       []*modulev1.Commit
+	nextCommitsPageToken string
+	loadingMoreCommits   bool
+	// commitAuthors (user ID to name) and commitLabels (commit ID to label
+	// names) annotate the commit list's rows; see annotateCommits.
+	commitAuthors      map[string]string
+	commitLabels       map[string][]string
+	currentCommitFiles []*modulev1.File
+	currentReference   *modulev1.ResourceRef_Name
+	// referenceAsOf is the time currentReference was opened as of, if it
+	// was opened as of a time (e.g. "owner/module@2026-09-01").
+	referenceAsOf time.Time
+	currentLabels []*modulev1.Label
+	loadingLabels bool
+	// moduleSort, moduleFilter and moduleTable are the module list's view
+	// options.
+	moduleSort   moduleSort
+	moduleFilter moduleFilter
+	moduleTable  bool
+	// labelArchiveFilter and labelSort are the Labels tab's view options.
+	// They're kept across modules, like a preference.
+	labelArchiveFilter labelArchiveFilter
+	labelSort          labelSort
+	// The label whose history is open in the Labels tab (entered like the
+	// Docs and Files tabs' right-hand panes, via
+	// modelStateBrowsingCommitFileContents), paginated like the commit list.
+	currentHistoryLabel       string
+	loadingLabelHistory       bool
+	loadingMoreLabelHistory   bool
+	nextLabelHistoryPageToken string
+	compiledDocs              *protoregistry.Files
+	loadingDocs               bool
 	// docsErr holds the error from the most recent failed compileDocs
 	// attempt, so the docs tab can show it instead of falling back to the
 	// misleading "No proto files found" (as if the module were genuinely
@@ -257,6 +414,28 @@ type model struct {
 	// testing to compute the wrong line for realistically complex content.
 	docsMatches  [][]int
 	docsMatchIdx int
+	// blame is the docs blame in progress (see blame.go), if any, and
+	// blameCancel cancels its steps. blameID identifies it, so steps of one
+	// that has been canceled are dropped. blameErr is why the last one
+	// stopped short.
+	blame       *blameWalk
+	blameCancel context.CancelFunc
+	blameID     int
+	blameErr    error
+	// descriptorSet is the compiled docs' marshaled FileDescriptorSet (see
+	// descriptors.go). descriptorSetInputActive is true while
+	// descriptorSetInput is visible in the docs search row and capturing
+	// keys, collecting the file to write it to, with descriptorSetOptions;
+	// descriptorSetErr is why the file named can't be written.
+	descriptorSet            []byte
+	descriptorSetInputActive bool
+	descriptorSetInput       textinput.Model
+	descriptorSetOptions     descriptorSetOptions
+	descriptorSetErr         error
+	// yankOptions are the yank menu's ways to copy the selection (see
+	// yank.go); the menu is open, in place of the help bar and capturing
+	// keys, while it's non-nil.
+	yankOptions []yankOption
 
 	// depsLoaded reports whether depsTree holds the dependency graph for the
 	// current commit (see deps.go). It's fetched lazily on first entering the
@@ -275,20 +454,157 @@ type model struct {
 	depsStatus    string
 	depsStatusSeq int
 
+	// currentPlugin is the plugin whose commits pluginCommitList shows.
+	currentPlugin *pluginv1beta1.Plugin
+	// currentOwnerInfo is the owner whose profile ownerView shows.
+	currentOwnerInfo *ownerv1.Owner
+
+	// sdksLoaded reports whether sdkList holds the current commit's
+	// generated SDKs (see sdk.go), fetched lazily like the deps.
+	sdksLoaded  bool
+	loadingSDKs bool
+
+	// filesAsList switches the Files tab from its directory tree (see
+	// files.go) to the flat list of paths. The tree is drawn from
+	// commitFilesList's visible items, so the list's filter narrows it too;
+	// filesStatus and filesStatusSeq work as depsStatus does, for the status
+	// bar drawn above the tree.
+	filesAsList    bool
+	filesStatus    string
+	filesStatusSeq int
+
+	// grepInputActive is true while grepInput is visible and capturing
+	// keys, collecting a search over every file in the commit (see
+	// grep.go); grepErr is why the last query didn't compile. Once a grep
+	// has run, grepQuery is set and the Files tab shows grepList in place of
+	// the tree or list until esc.
+	grepInputActive bool
+	grepInput       textinput.Model
+	grepErr         error
+	grepQuery       string
+	grepTruncated   bool
+
+	// fileSearchActive and fileGotoActive are true while the file viewer's
+	// search or goto-line input is visible and capturing keys (see
+	// fileview.go). fileMatches/fileMatchIdx are the last search's matches,
+	// as docsMatches/docsMatchIdx are the docs search's. fileCurrentLine
+	// (1-indexed, 0 for none) is the line the last goto or search jumped
+	// to, marked in the gutter. fileLines and fileMarkdown describe the file
+	// shown, for the gutter.
+	fileSearchActive bool
+	fileSearchInput  textinput.Model
+	fileSearchQuery  string
+	fileMatches      [][]int
+	fileMatchIdx     int
+	fileGotoActive   bool
+	fileGotoInput    textinput.Model
+	fileGotoErr      error
+	fileCurrentLine  int
+	fileLines        int
+	fileMarkdown     bool
+	fileDiffShown    bool
+
+	// diffRef, when set, is the commit ID or label the Files tab is diffed
+	// against (see diff.go), in diffOwner/diffModule; it carries over to
+	// other commits of that module. diffFiles holds that commit's files by
+	// path once they've loaded, and diffBaseCommitID the commit ref resolved
+	// to. diffInputActive is true while diffInput is visible and capturing
+	// keys, collecting a new ref; diffErr is why the last one didn't load.
+	diffInputActive  bool
+	diffInput        textinput.Model
+	diffErr          error
+	diffRef          string
+	diffOwner        string
+	diffModule       string
+	diffBaseCommitID string
+	diffFiles        map[string]*modulev1.File
+	diffLoading      bool
+	diffSideBySide   bool
+	// fileDiffID identifies the file viewer's latest content, so a diff
+	// rendered for a file since moved off of is dropped.
+	fileDiffID int
+
+	// historyInputActive is true while historyInput is visible and
+	// capturing keys, collecting a query to search the module's history for
+	// (see history.go). Once a search starts, historyQuery is set and the
+	// commits view shows historyList -- the commits where the query's count
+	// changed -- in place of the commit list until esc, filling in as the
+	// search walks back through historyOwner/historyModule's commits.
+	// historySearchID tells the current search's steps from those of one
+	// closed since.
+	historyInputActive bool
+	historyInput       textinput.Model
+	historyErr         error
+	historyQuery       string
+	historyPattern     *regexp.Regexp
+	historyOwner       string
+	historyModule      string
+	historySearchID    int
+	historySearching   bool
+	historyCounts      []historyCount
+
+	// authenticated reports whether a token was configured. Label changes
+	// (see labels.go) are only offered with one -- the BSR has no way to ask
+	// up front whether a token may change a module's labels, so they're
+	// also hidden for a module once it has refused one there
+	// (labelWritesDenied, as "owner/module").
+	authenticated     bool
+	labelWritesDenied string
+	// labelNameInputActive is true while labelNameInput is visible and
+	// capturing keys, collecting the name of a label to point at
+	// labelNameCommitID.
+	labelNameInputActive bool
+	labelNameInput       textinput.Model
+	labelNameCommitID    string
+	// pendingLabelWrite is a label change awaiting the user's confirmation;
+	// while it's set, only the confirm and cancel keys do anything.
+	pendingLabelWrite *labelWrite
+	// Module changes (see moduleadmin.go) are offered on the same terms,
+	// and hidden for a module that has refused one (moduleWritesDenied, as
+	// "owner/module"). moduleEdit is the change the edit input is
+	// collecting the value of, while it's open; pendingModuleWrite is a
+	// change awaiting the user's confirmation.
+	moduleWritesDenied string
+	moduleEdit         *moduleWrite
+	moduleEditInput    textinput.Model
+	pendingModuleWrite *moduleWrite
+
+	// digestStatus is the result of verifying the current commit's files
+	// against its BSR digest (see digest.go), shown as a badge next to the
+	// commit breadcrumb.
+	digestStatus digestStatus
+
+	// currentModuleInfo is the metadata the Overview tab shows for the
+	// module named by currentModuleInfoName ("owner/module"), taken from the
+	// module list or fetched when the tab is opened.
+	currentModuleInfo     *modulev1.Module
+	currentModuleInfoName string
+	loadingModuleInfo     bool
+	moduleInfoErr         error
+
 	// Tab state
 	activeCommitTab commitTab
 
 	// Sub-models
-	moduleList      list.Model
-	commitList      list.Model
-	commitFilesList list.Model
-	labelsList      list.Model
-	docsList        list.Model
-	docsViewport    viewport.Model
-	depsTree        tree.Model
-	fileViewport    viewport.Model
-	navigateInput   textinput.Model
-	help            help.Model
+	moduleList       list.Model
+	commitList       list.Model
+	commitFilesList  list.Model
+	labelsList       list.Model
+	sdkList          list.Model
+	pluginList       list.Model
+	pluginCommitList list.Model
+	activityList     list.Model
+	labelHistoryList list.Model
+	docsList         list.Model
+	docsViewport     viewport.Model
+	depsTree         tree.Model
+	filesTree        tree.Model
+	grepList         list.Model
+	historyList      list.Model
+	fileViewport     viewport.Model
+	overviewViewport viewport.Model
+	navigateInput    textinput.Model
+	help             help.Model
 
 	// Navigate input suggestions state
 	currentSuggestionsKey string
@@ -302,7 +618,7 @@ func (m model) Init() tea.Cmd {
 		tea.RequestBackgroundColor,
 	}
 	if m.currentReference != nil {
-		inits = append(inits, m.client.getResource(m.currentReference))
+		inits = append(inits, m.getReference())
 	}
 	return tea.Batch(inits...)
 }
@@ -311,9 +627,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
 		m.applyStyles(msg.IsDark())
+		m.updateOverviewView()
 
 	case tea.WindowSizeMsg:
 		m.resize(msg.Width, msg.Height)
+		m.updateOverviewView()
 
 	case resourceMsg:
 		switch retrievedResource := msg.retrievedResource.Value.(type) {
@@ -342,15 +660,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case modulesMsg:
 		m.state = modelStateBrowsingModules
-		m.currentModules = msg
+		m.currentModules = msg.modules
+		m.nextModulesPageToken = msg.nextPageToken
+		m.loadingMoreModules = false
 		if len(m.currentModules) == 0 {
 			return m, nil
 		}
-		modules := make([]list.Item, len(m.currentModules))
-		for i, currentModule := range m.currentModules {
-			modules[i] = &module{underlying: currentModule, remote: m.remote, owner: m.currentOwner}
-		}
-		m.moduleList.SetItems(modules)
+		m.setModuleItems()
 		ownerURL := "https://" + m.remote + "/" + m.currentOwner
 		m.moduleList.Title = breadcrumb(
 			m.remote, "https://"+m.remote,
@@ -367,6 +683,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case commitsMsg:
 		m.state = modelStateBrowsingCommits
+		if m.historyOwner != m.currentOwner || m.historyModule != m.currentModule {
+			// A search of another module's history.
+			m.resetHistory()
+		}
 		m.currentCommits = msg.commits
 		m.nextCommitsPageToken = msg.nextPageToken
 		m.loadingMoreCommits = false
@@ -374,12 +694,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.currentLabels = nil
 		m.loadingLabels = false
 		m.labelsList.SetItems(nil)
+		m.currentHistoryLabel = ""
+		m.labelHistoryList.SetItems(nil)
+		m.commitAuthors = nil
+		m.commitLabels = nil
 		if len(m.currentCommits) == 0 {
 			return m, nil
 		}
 		commits := make([]list.Item, len(m.currentCommits))
 		for i, currentCommit := range m.currentCommits {
-			commits[i] = &commit{underlying: currentCommit, remote: m.remote, owner: m.currentOwner, moduleName: m.currentModule}
+			commits[i] = m.newCommitItem(currentCommit)
 		}
 		m.commitList.SetItems(commits)
 		moduleURL := "https://" + m.remote + "/" + m.currentOwner + "/" + m.currentModule
@@ -395,7 +719,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.commitList.AdditionalShortHelpKeys = func() []key.Binding {
 			return []key.Binding{keys.Left, keys.Right}
 		}
-		return m, nil
+		return m, m.client.annotateCommits(m.currentOwner, m.currentModule, m.currentCommits, true)
+
+	case moreModulesMsg:
+		if msg.owner != m.currentOwner || msg.pageToken != m.nextModulesPageToken {
+			// The user has moved on to another owner, or these pages were
+			// already appended.
+			return m, nil
+		}
+		m.nextModulesPageToken = msg.nextPageToken
+		m.loadingMoreModules = false
+		m.currentModules = append(m.currentModules, msg.modules...)
+		return m, tea.Batch(m.setModuleItems(), m.loadMoreModulesIfNeeded())
+
+	case moreModulesErrMsg:
+		if msg.owner != m.currentOwner || msg.pageToken != m.nextModulesPageToken {
+			return m, nil
+		}
+		// Keep the pages that did load. The one that failed is tried again
+		// the next time more modules are needed, not straight away.
+		m.nextModulesPageToken = msg.nextPageToken
+		m.loadingMoreModules = false
+		m.currentModules = append(m.currentModules, msg.modules...)
+		return m, tea.Batch(
+			m.setModuleItems(),
+			m.moduleList.NewStatusMessage(lipgloss.NewStyle().Foreground(colorError).Render(msg.Error())),
+		)
 
 	case moreCommitsMsg:
 		m.nextCommitsPageToken = msg.nextPageToken
@@ -404,9 +753,53 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		existingItems := m.commitList.Items()
 		newItems := make([]list.Item, len(msg.commits))
 		for i, c := range msg.commits {
-			newItems[i] = &commit{underlying: c, remote: m.remote, owner: m.currentOwner, moduleName: m.currentModule}
+			newItems[i] = m.newCommitItem(c)
 		}
-		return m, m.commitList.SetItems(slices.Concat(existingItems, newItems))
+		return m, tea.Batch(
+			m.commitList.SetItems(slices.Concat(existingItems, newItems)),
+			m.client.annotateCommits(m.currentOwner, m.currentModule, msg.commits, false),
+		)
+
+	case commitAnnotationsMsg:
+		if msg.owner != m.currentOwner || msg.module != m.currentModule {
+			// The user has moved on to another module.
+			return m, nil
+		}
+		if m.commitAuthors == nil {
+			m.commitAuthors = make(map[string]string)
+		}
+		maps.Copy(m.commitAuthors, msg.authors)
+		if msg.labels != nil {
+			m.commitLabels = msg.labels
+		}
+		items := m.commitList.Items()
+		for _, item := range items {
+			if c, ok := item.(*commit); ok {
+				c.author = m.commitAuthors[c.underlying.CreatedByUserId]
+				c.labels = m.commitLabels[c.underlying.Id]
+			}
+		}
+		// Set the items again so an active filter sees the new values.
+		return m, m.commitList.SetItems(items)
+
+	case moduleMsg:
+		if msg.owner+"/"+msg.module.Name != m.currentOwner+"/"+m.currentModule {
+			return m, nil
+		}
+		m.loadingModuleInfo = false
+		m.currentModuleInfo = msg.module
+		m.currentModuleInfoName = m.currentOwner + "/" + m.currentModule
+		m.updateOverviewView()
+		return m, nil
+
+	case moduleErrMsg:
+		if msg.owner+"/"+msg.module != m.currentOwner+"/"+m.currentModule {
+			return m, nil
+		}
+		m.loadingModuleInfo = false
+		m.moduleInfoErr = msg
+		m.updateOverviewView()
+		return m, nil
 
 	case contentsMsg:
 		m.state = modelStateBrowsingCommitContents
@@ -428,7 +821,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.docsCancel != nil {
 			m.docsCancel()
 		}
+		m.resetBlame()
 		m.compiledDocs = nil
+		m.descriptorSet = nil
+		m.descriptorSetInputActive = false
 		m.loadingDocs = true
 		m.docsErr = nil
 		m.docsList.SetItems(nil)
@@ -439,12 +835,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.depsCount = 0
 		m.depsStatus = ""
 		m.depsTree.SetNodes(tree.NewNode())
-		commitFiles := make([]list.Item, len(m.currentCommitFiles))
-		for i, currentCommitFile := range m.currentCommitFiles {
-			commitFiles[i] = &commitFile{underlying: currentCommitFile, remote: m.remote, owner: m.currentOwner, moduleName: m.currentModule, commitID: m.currentCommitID}
+		m.sdksLoaded = false
+		m.loadingSDKs = false
+		m.sdkList.SetItems(nil)
+		m.digestStatus = digestPending
+		m.currentCommit = msg.Commit
+		m.currentCommitAuthor = ""
+		m.currentCommitLabels = nil
+		m.loadingCommitDetails = true
+		m.commitDetailsErr = nil
+		if m.diffRef != "" && (m.diffOwner != m.currentOwner || m.diffModule != m.currentModule) {
+			// A diff carries over between commits, but not modules.
+			m.stopDiff()
 		}
-		m.commitFilesList.SetItems(commitFiles)
+		m.commitFilesList.SetItems(nil)
+		m.setCommitFileItems()
 		m.commitFilesList.InfiniteScrolling = false
+		m.filesStatus = ""
+		m.resetGrep()
 		m.commitFilesList.AdditionalFullHelpKeys = func() []key.Binding {
 			return []key.Binding{keys.Left, keys.Right}
 		}
@@ -457,13 +865,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = fmt.Errorf("invalid list item type: expected commitFile")
 			return m, tea.Quit
 		}
-		m.updateFileView(commitFile.underlying)
+		fileViewCmd := m.updateFileView(commitFile.underlying)
+		m.updateOverviewView()
+		m.overviewViewport.GotoTop()
 		ctx, cancel := context.WithTimeout(context.Background(), compileDocsTimeout)
 		m.docsCancel = cancel
-		return m, m.client.compileDocs(ctx, m.currentCommitID, m.currentCommitFiles)
+		cmds := []tea.Cmd{fileViewCmd, m.client.compileDocs(ctx, m.currentCommitID, m.currentCommitFiles)}
+		if msg.Commit != nil {
+			cmds = append(cmds,
+				m.client.verifyCommitDigest(msg.Commit, msg.Files),
+				m.client.getCommitDetails(msg.Commit),
+			)
+		} else {
+			// Nothing to verify against or look up details for.
+			m.digestStatus = digestUnverifiable
+			m.loadingCommitDetails = false
+		}
+		return m, tea.Batch(cmds...)
+
+	case commitDetailsMsg:
+		if msg.commitID == m.currentCommitID {
+			m.loadingCommitDetails = false
+			m.currentCommitAuthor = msg.author
+			m.currentCommitLabels = msg.labels
+		}
+		return m, nil
+
+	case commitDetailsErrMsg:
+		if msg.commitID == m.currentCommitID {
+			m.loadingCommitDetails = false
+			m.commitDetailsErr = msg.err
+		}
+		return m, nil
+
+	case digestVerifiedMsg:
+		if msg.commitID == m.currentCommitID {
+			m.digestStatus = digestVerified
+		}
+		return m, nil
+
+	case digestErrMsg:
+		if msg.commitID == m.currentCommitID {
+			m.digestStatus = digestUnverifiable
+			if _, ok := errors.AsType[*digestMismatchError](msg.err); ok {
+				m.digestStatus = digestMismatch
+			}
+		}
+		return m, nil
 
 	case docsMsg:
 		m.compiledDocs = msg.files
+		m.descriptorSet = msg.descriptorSet
 		m.loadingDocs = false
 		m.docsErr = nil
 		if m.docsCancel != nil {
@@ -505,12 +957,161 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.depsErr = msg.err
 		return m, nil
 
+	case pluginsMsg:
+		if m.state != modelStateLoadingPlugins || msg.owner != m.currentOwner {
+			return m, nil
+		}
+		m.state = modelStateBrowsingPlugins
+		items := make([]list.Item, len(msg.plugins))
+		for i, plugin := range msg.plugins {
+			items[i] = &pluginItem{underlying: plugin}
+		}
+		m.pluginList.ResetSelected()
+		m.pluginList.Title = breadcrumb(
+			m.remote, "https://"+m.remote,
+			msg.owner, "https://"+m.remote+"/"+msg.owner,
+		) + " plugins"
+		return m, m.pluginList.SetItems(items)
+
+	case pluginCommitsMsg:
+		if m.state != modelStateLoadingPluginCommits || msg.owner != m.currentOwner {
+			return m, nil
+		}
+		m.state = modelStateBrowsingPluginCommits
+		m.currentPlugin = msg.plugin
+		m.pluginCommitList.ResetSelected()
+		m.pluginCommitList.ResetFilter()
+		m.pluginCommitList.Title = breadcrumb(
+			m.remote, "https://"+m.remote,
+			m.currentOwner, "https://"+m.remote+"/"+m.currentOwner,
+			msg.plugin.Name, "",
+		)
+		return m, m.pluginCommitList.SetItems(pluginCommitItems(msg))
+
+	case ownerMsg:
+		if m.state != modelStateLoadingOwner || msg.owner != m.currentOwner {
+			return m, nil
+		}
+		m.state = modelStateBrowsingOwner
+		m.currentOwnerInfo = msg.info
+		return m, nil
+
+	case activityMsg:
+		if m.state != modelStateLoadingActivity || msg.owner != m.currentOwner {
+			return m, nil
+		}
+		m.state = modelStateBrowsingActivity
+		m.activityList.ResetSelected()
+		m.activityList.ResetFilter()
+		m.activityList.Title = breadcrumb(
+			m.remote, "https://"+m.remote,
+			msg.owner, "https://"+m.remote+"/"+msg.owner,
+		) + " activity"
+		cmds := []tea.Cmd{m.activityList.SetItems(activityItems(msg.entries))}
+		if msg.failed > 0 {
+			cmds = append(cmds, m.activityList.NewStatusMessage(lipgloss.NewStyle().Foreground(colorError).Render(
+				fmt.Sprintf("couldn't list the commits of %d modules", msg.failed),
+			)))
+		}
+		return m, tea.Batch(cmds...)
+
+	case activityErrMsg:
+		// Like plugins, the feed is a step aside from browsing modules.
+		m.state = modelStateBrowsingModules
+		return m, m.moduleList.NewStatusMessage(lipgloss.NewStyle().Foreground(colorError).Render(msg.Error()))
+
+	case ownerErrMsg:
+		if m.state != modelStateLoadingOwner || msg.owner != m.currentOwner {
+			return m, nil
+		}
+		// Like plugins, the profile is a step aside from browsing modules.
+		m.state = modelStateBrowsingModules
+		return m, m.moduleList.NewStatusMessage(lipgloss.NewStyle().Foreground(colorError).Render(msg.Error()))
+
+	case pluginsErrMsg:
+		if (m.state != modelStateLoadingPlugins && m.state != modelStateLoadingPluginCommits) || msg.owner != m.currentOwner {
+			return m, nil
+		}
+		// Plugins are a step aside from browsing modules, so failing to
+		// list them goes back to where they were asked for rather than
+		// ending the session.
+		errStr := lipgloss.NewStyle().Foreground(colorError).Render(msg.Error())
+		if m.state == modelStateLoadingPluginCommits {
+			m.state = modelStateBrowsingPlugins
+			return m, m.pluginList.NewStatusMessage(errStr)
+		}
+		m.state = modelStateBrowsingModules
+		return m, m.moduleList.NewStatusMessage(errStr)
+
+	case sdksMsg:
+		if msg.commitID != m.currentCommitID {
+			return m, nil
+		}
+		m.loadingSDKs = false
+		m.sdksLoaded = true
+		items := make([]list.Item, len(msg.sdks))
+		for i, sdk := range msg.sdks {
+			item := &sdkItem{sdk: sdk}
+			if sdk.err == nil {
+				item.install = sdk.plugin.installCommand(m.remote, m.currentOwner, m.currentModule, sdk.version)
+			}
+			items[i] = item
+		}
+		return m, m.sdkList.SetItems(items)
+
+	case sbomWrittenMsg:
+		return m, m.setDepsStatus("wrote SBOM to " + msg.path)
+
+	case sbomErrMsg:
+		errStr := lipgloss.NewStyle().Foreground(colorError).Render("exporting SBOM: " + msg.err.Error())
+		return m, m.setDepsStatus(errStr)
+
+	case exportedMsg:
+		status := m.commitList.NewStatusMessage
+		if msg.fromLabels {
+			status = m.labelsList.NewStatusMessage
+		}
+		return m, status(fmt.Sprintf("wrote %d files to %s", msg.files, msg.dir))
+
+	case exportErrMsg:
+		status := m.commitList.NewStatusMessage
+		if msg.fromLabels {
+			status = m.labelsList.NewStatusMessage
+		}
+		return m, status(lipgloss.NewStyle().Foreground(colorError).Render("exporting: " + msg.err.Error()))
+
+	case descriptorSetWrittenMsg:
+		return m, m.docsList.NewStatusMessage("wrote descriptor set to " + msg.path)
+
+	case descriptorSetErrMsg:
+		return m, m.docsList.NewStatusMessage(lipgloss.NewStyle().Foreground(colorError).Render("writing descriptor set: " + msg.err.Error()))
+
 	case depsStatusExpiredMsg:
 		if msg.seq == m.depsStatusSeq {
 			m.depsStatus = ""
 		}
 		return m, nil
 
+	case historyStepMsg:
+		if msg.id != m.historySearchID {
+			return m, nil
+		}
+		return m, m.continueHistorySearch(msg)
+
+	case historyErrMsg:
+		if msg.id != m.historySearchID {
+			return m, nil
+		}
+		m.historySearching = false
+		m.historyErr = msg.err
+		return m, nil
+
+	case filesStatusExpiredMsg:
+		if msg.seq == m.filesStatusSeq {
+			m.filesStatus = ""
+		}
+		return m, nil
+
 	case docsErrMsg:
 		// A dedicated message type, not the generic errMsg: loadingDocs
 		// stays true for up to compileDocsTimeout, during which an
@@ -527,33 +1128,143 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case diffBaseMsg:
+		if msg.ref != m.diffRef || msg.owner != m.diffOwner || msg.module != m.diffModule {
+			return m, nil
+		}
+		m.diffLoading = false
+		m.diffBaseCommitID = msg.content.Commit.GetId()
+		m.diffFiles = make(map[string]*modulev1.File, len(msg.content.Files))
+		for _, file := range msg.content.Files {
+			m.diffFiles[file.Path] = file
+		}
+		if m.currentCommitFiles != nil {
+			m.setCommitFileItems()
+			return m, m.refreshFileView()
+		}
+		return m, nil
+
+	case diffBaseErrMsg:
+		if msg.ref != m.diffRef {
+			return m, nil
+		}
+		cmd := m.stopDiff()
+		m.diffErr = msg.err
+		return m, cmd
+
+	case fileDiffMsg:
+		if msg.id != m.fileDiffID {
+			return m, nil
+		}
+		m.fileViewport.SetContent(msg.content)
+		return m, nil
+
+	case blameStepMsg:
+		if msg.id != m.blameID || m.blame == nil {
+			return m, nil
+		}
+		return m, m.continueBlame(msg)
+
+	case blameErrMsg:
+		if msg.id != m.blameID || m.blame == nil {
+			return m, nil
+		}
+		m.blameErr = msg.err
+		m.finishBlame(true)
+		return m, nil
+
 	case labelsMsg:
+		if msg.archiveFilter != m.labelArchiveFilter {
+			return m, nil
+		}
 		m.loadingLabels = false
-		m.currentLabels = []*modulev1.Label(msg)
-
-		// Default label first, then others sorted by UpdateTime descending.
-		var defaultLabel *modulev1.Label
-		var otherLabels []*modulev1.Label
-		for _, label := range m.currentLabels {
-			if label.Name == m.currentDefaultLabelName {
-				defaultLabel = label
-			} else {
-				otherLabels = append(otherLabels, label)
-			}
+		m.currentLabels = msg.labels
+		return m, m.setLabelItems()
+
+	case labelHistoryMsg:
+		if msg.label != m.currentHistoryLabel {
+			return m, nil
 		}
-		slices.SortStableFunc(otherLabels, func(a, b *modulev1.Label) int {
-			return b.UpdateTime.AsTime().Compare(a.UpdateTime.AsTime())
-		})
-		labels := make([]list.Item, 0, len(m.currentLabels))
-		if defaultLabel != nil {
-			labels = append(labels, &labelItem{underlying: defaultLabel, remote: m.remote, owner: m.currentOwner, moduleName: m.currentModule, isDefault: true})
+		m.loadingLabelHistory = false
+		m.nextLabelHistoryPageToken = msg.nextPageToken
+		m.labelHistoryList.Title = "history of " + msg.label
+		m.labelHistoryList.ResetSelected()
+		return m, m.labelHistoryList.SetItems(m.labelHistoryItems(msg))
+
+	case moreLabelHistoryMsg:
+		if msg.label != m.currentHistoryLabel {
+			return m, nil
+		}
+		m.loadingMoreLabelHistory = false
+		m.nextLabelHistoryPageToken = msg.nextPageToken
+		return m, m.labelHistoryList.SetItems(slices.Concat(m.labelHistoryList.Items(), m.labelHistoryItems(labelHistoryMsg(msg))))
+
+	case labelWrittenMsg:
+		// Every label list is stale now; refetch the one on screen, and let
+		// the Labels tab refetch lazily otherwise.
+		m.currentLabels = nil
+		status := msg.write.done()
+		// The commit list's label badges are stale too.
+		annotate := m.client.annotateCommits(m.currentOwner, m.currentModule, nil, true)
+		if m.state == modelStateBrowsingCommits {
+			return m, tea.Batch(m.commitList.NewStatusMessage(status), annotate)
+		}
+		cmds := []tea.Cmd{m.labelsList.NewStatusMessage(status), annotate}
+		if m.activeCommitTab == commitTabLabels && !m.loadingLabels {
+			m.loadingLabels = true
+			cmds = append(cmds, m.client.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter))
+		}
+		// The labels pointing at the current commit may have changed too.
+		if m.currentCommit != nil {
+			m.loadingCommitDetails = true
+			cmds = append(cmds, m.client.getCommitDetails(m.currentCommit))
 		}
-		for _, label := range otherLabels {
-			labels = append(labels, &labelItem{underlying: label, remote: m.remote, owner: m.currentOwner, moduleName: m.currentModule})
+		return m, tea.Batch(cmds...)
+
+	case labelPointMsg:
+		if msg.write.owner != m.currentOwner || msg.write.module != m.currentModule {
+			return m, nil
+		}
+		if msg.write.fromCommitID == msg.write.commitID {
+			status := fmt.Sprintf("label %q already points at commit %s", msg.write.label, shortCommitID(msg.write.commitID))
+			if m.state == modelStateBrowsingCommits {
+				return m, m.commitList.NewStatusMessage(status)
+			}
+			return m, m.labelsList.NewStatusMessage(status)
 		}
-		m.labelsList.SetItems(labels)
+		m.pendingLabelWrite = &msg.write
 		return m, nil
 
+	case labelWriteErrMsg:
+		errText := msg.err.Error()
+		if msg.permissionDenied() {
+			m.labelWritesDenied = msg.write.owner + "/" + msg.write.module
+			errText = fmt.Sprintf("not permitted to change labels on %s", m.labelWritesDenied)
+		}
+		errStr := lipgloss.NewStyle().Foreground(colorError).Render(errText)
+		if m.state == modelStateBrowsingCommits {
+			return m, m.commitList.NewStatusMessage(errStr)
+		}
+		return m, m.labelsList.NewStatusMessage(errStr)
+
+	case moduleWrittenMsg:
+		if msg.write.owner == m.currentOwner {
+			for i, module := range m.currentModules {
+				if module.Name == msg.write.module {
+					m.currentModules[i] = msg.module
+				}
+			}
+		}
+		return m, tea.Batch(m.setModuleItems(), m.moduleList.NewStatusMessage(msg.write.done()))
+
+	case moduleWriteErrMsg:
+		errText := msg.err.Error()
+		if msg.permissionDenied() {
+			m.moduleWritesDenied = msg.write.owner + "/" + msg.write.module
+			errText = fmt.Sprintf("not permitted to change module %s", m.moduleWritesDenied)
+		}
+		return m, m.moduleList.NewStatusMessage(lipgloss.NewStyle().Foreground(colorError).Render(errText))
+
 	case navigateSuggestionsMsg:
 		m.navigateInput.SetSuggestions([]string(msg))
 		return m, nil
@@ -571,11 +1282,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			modelStateBrowsingCommitContents,
 			modelStateBrowsingCommitFileContents:
 			m.loadingLabels = false
+			m.loadingLabelHistory = false
+			m.loadingMoreLabelHistory = false
 			m.state = modelStateBrowsingCommitContents
 			if m.activeCommitTab == commitTabLabels {
 				return m, m.labelsList.NewStatusMessage(errStr)
 			}
-			return m, m.commitFilesList.NewStatusMessage(errStr)
+			return m, m.filesStatusMessage(errStr)
 		case modelStateLoadingReference, modelStateNavigating:
 			m.state = modelStateNavigating
 			m.navigateErr = msg.err
@@ -592,6 +1305,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if key.Matches(msg, m.keys.Quit) {
 			return m, tea.Quit
 		}
+		// The yank menu takes the next key whatever it is: one of its
+		// options copies that, and anything else just closes it.
+		if m.yankOptions != nil {
+			return m, m.yank(msg.String())
+		}
+		// The descriptor set input owns all keys, its option keys included.
+		if m.descriptorSetInputActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.descriptorSetInputActive = false
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				return m, m.submitDescriptorSetInput()
+			case key.Matches(msg, m.keys.DescriptorSetFormat):
+				m.cycleDescriptorSetFormat()
+				return m, nil
+			case key.Matches(msg, m.keys.DescriptorSetSourceInfo):
+				m.descriptorSetOptions.excludeSourceInfo = !m.descriptorSetOptions.excludeSourceInfo
+				return m, nil
+			case key.Matches(msg, m.keys.DescriptorSetImports):
+				m.descriptorSetOptions.excludeImports = !m.descriptorSetOptions.excludeImports
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.descriptorSetInput, cmd = m.descriptorSetInput.Update(msg)
+			return m, cmd
+		}
 		// While the docs search input is active, it owns all keys except
 		// esc (cancel) and enter (run the search and close the input;
 		// matches persist afterward for n/N to navigate).
@@ -615,11 +1355,178 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.docsSearchInput, cmd = m.docsSearchInput.Update(msg)
 			return m, cmd
 		}
+		// Likewise the file viewer's search and goto-line inputs, except
+		// that a goto to something other than a line number leaves its
+		// input open.
+		if m.fileSearchActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.fileSearchActive = false
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				m.runFileSearch()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.fileSearchInput, cmd = m.fileSearchInput.Update(msg)
+			return m, cmd
+		}
+		if m.fileGotoActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.fileGotoActive = false
+				m.fileGotoErr = nil
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				m.gotoFileLine()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.fileGotoInput, cmd = m.fileGotoInput.Update(msg)
+			return m, cmd
+		}
+		// The diff input likewise owns all keys; enter diffs against the
+		// ref typed, or stops diffing if it's empty.
+		if m.diffInputActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.diffInputActive = false
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				return m, m.startDiff(strings.TrimSpace(m.diffInput.Value()))
+			}
+			var cmd tea.Cmd
+			m.diffInput, cmd = m.diffInput.Update(msg)
+			return m, cmd
+		}
+		// Likewise the grep input, except that a query that doesn't compile
+		// leaves it open.
+		if m.grepInputActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.grepInputActive = false
+				m.grepErr = nil
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				return m, m.runGrep()
+			}
+			var cmd tea.Cmd
+			m.grepInput, cmd = m.grepInput.Update(msg)
+			return m, cmd
+		}
+		if m.historyInputActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.historyInputActive = false
+				m.historyErr = nil
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				return m, m.startHistorySearch()
+			}
+			var cmd tea.Cmd
+			m.historyInput, cmd = m.historyInput.Update(msg)
+			return m, cmd
+		}
+		if m.pendingLabelWrite != nil {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				write := *m.pendingLabelWrite
+				m.pendingLabelWrite = nil
+				return m, m.client.writeLabel(write)
+			case key.Matches(msg, m.keys.Cancel):
+				m.pendingLabelWrite = nil
+			}
+			return m, nil
+		}
+		if m.pendingModuleWrite != nil {
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				write := *m.pendingModuleWrite
+				m.pendingModuleWrite = nil
+				return m, m.client.writeModule(write)
+			case key.Matches(msg, m.keys.Cancel):
+				m.pendingModuleWrite = nil
+			}
+			return m, nil
+		}
+		// The module edit input owns all keys except esc (cancel), enter
+		// (confirm the value) and tab (edit the next field instead).
+		if m.moduleEdit != nil {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.moduleEdit = nil
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				write := *m.moduleEdit
+				write.value = strings.TrimSpace(m.moduleEditInput.Value())
+				m.moduleEdit = nil
+				m.pendingModuleWrite = &write
+				return m, nil
+			case msg.String() == "tab":
+				m.startModuleEdit(m.moduleEdit.field.nextText())
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.moduleEditInput, cmd = m.moduleEditInput.Update(msg)
+			return m, cmd
+		}
+		// Like the docs search input, the label name input owns all keys
+		// except esc (cancel) and enter (confirm the name).
+		if m.labelNameInputActive {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.labelNameInputActive = false
+				return m, nil
+			case key.Matches(msg, m.keys.Enter):
+				name := strings.TrimSpace(m.labelNameInput.Value())
+				if name == "" {
+					return m, nil
+				}
+				m.labelNameInputActive = false
+				return m, m.client.preparePointLabel(labelWrite{
+					action:   labelActionPoint,
+					owner:    m.currentOwner,
+					module:   m.currentModule,
+					label:    name,
+					commitID: m.labelNameCommitID,
+				})
+			}
+			var cmd tea.Cmd
+			m.labelNameInput, cmd = m.labelNameInput.Update(msg)
+			return m, cmd
+		}
 		// When a list is actively filtering, pass all keys through to it
 		// rather than handling our own keybindings.
 		if m.activeListIsFiltering() {
 			break
 		}
+		// History search results stand in for the commit list, and take
+		// over its keys: its commit actions would act on a commit that
+		// isn't on screen.
+		if m.state == modelStateBrowsingCommits && m.historyQuery != "" {
+			switch {
+			case key.Matches(msg, m.keys.Back, m.keys.Left):
+				m.resetHistory()
+				return m, nil
+			case key.Matches(msg, m.keys.Enter, m.keys.Right):
+				change, ok := m.historyList.SelectedItem().(*historyChange)
+				if !ok {
+					return m, nil
+				}
+				m.currentCommitID = change.commit.Id
+				m.state = modelStateLoadingCommitFileContents
+				return m, m.client.getCommitContent(m.currentCommitID)
+			case key.Matches(msg, m.keys.HistorySearch):
+				m.openHistoryInput()
+				return m, nil
+			case key.Matches(msg, m.keys.Help, m.keys.Navigate):
+				// Handled as anywhere else, below.
+			default:
+				var cmd tea.Cmd
+				m.historyList, cmd = m.historyList.Update(msg)
+				return m, cmd
+			}
+		}
 		switch {
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
@@ -631,6 +1538,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.docsSearchInput.Focus()
 				return m, nil
 			}
+			if m.viewingFile() {
+				m.fileSearchActive = true
+				m.fileSearchInput.Reset()
+				m.fileSearchInput.Focus()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.GotoLine):
+			if m.viewingFile() {
+				m.fileGotoActive = true
+				m.fileGotoErr = nil
+				m.fileGotoInput.Reset()
+				m.fileGotoInput.Focus()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Diff):
+			switch {
+			case m.state == modelStateBrowsingCommits && m.historyQuery == "":
+				commit, ok := m.commitList.SelectedItem().(*commit)
+				if !ok {
+					return m, nil
+				}
+				if m.diffRef == commit.underlying.Id {
+					return m, tea.Batch(m.stopDiff(), m.commitList.NewStatusMessage("No longer diffing against "+commit.underlying.Id))
+				}
+				return m, tea.Batch(m.startDiff(commit.underlying.Id), m.commitList.NewStatusMessage("Diffing commits against "+commit.underlying.Id))
+			case (m.state == modelStateBrowsingCommitContents || m.state == modelStateBrowsingCommitFileContents) && m.activeCommitTab == commitTabFiles:
+				m.openDiffInput()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.DiffLayout):
+			if (m.state == modelStateBrowsingCommitContents || m.state == modelStateBrowsingCommitFileContents) && m.activeCommitTab == commitTabFiles && m.diffing() {
+				m.diffSideBySide = !m.diffSideBySide
+				return m, m.refreshFileView()
+			}
+
+		case key.Matches(msg, m.keys.Wrap):
+			if m.viewingFile() {
+				m.fileViewport.SoftWrap = !m.fileViewport.SoftWrap
+				m.fileViewport.SetXOffset(0)
+				if m.fileCurrentLine > 0 {
+					m.scrollFileViewTo(m.fileCurrentLine - 1)
+				} else {
+					m.fileViewport.GotoTop()
+				}
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Blame):
+			if (m.state == modelStateBrowsingCommitContents || m.state == modelStateBrowsingCommitFileContents) && m.activeCommitTab == commitTabDocs {
+				return m, m.toggleBlame()
+			}
 
 		case key.Matches(msg, m.keys.SearchNext):
 			if m.state == modelStateBrowsingCommitFileContents && m.activeCommitTab == commitTabDocs && len(m.docsMatches) > 0 {
@@ -638,6 +1599,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.jumpToDocsMatch()
 				return m, nil
 			}
+			if m.viewingFile() && len(m.fileMatches) > 0 {
+				m.fileMatchIdx = (m.fileMatchIdx + 1) % len(m.fileMatches)
+				m.jumpToFileMatch()
+				return m, nil
+			}
 
 		case key.Matches(msg, m.keys.SearchPrev):
 			if m.state == modelStateBrowsingCommitFileContents && m.activeCommitTab == commitTabDocs && len(m.docsMatches) > 0 {
@@ -645,6 +1611,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.jumpToDocsMatch()
 				return m, nil
 			}
+			if m.viewingFile() && len(m.fileMatches) > 0 {
+				m.fileMatchIdx = (m.fileMatchIdx - 1 + len(m.fileMatches)) % len(m.fileMatches)
+				m.jumpToFileMatch()
+				return m, nil
+			}
 
 		case key.Matches(msg, m.keys.TabLeft):
 			if m.state == modelStateBrowsingCommitContents || m.state == modelStateBrowsingCommitFileContents {
@@ -679,11 +1650,45 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = modelStateLoadingModules
 				m.commitList.ResetSelected()
 				return m, m.client.listModules(m.currentOwner)
+			case modelStateBrowsingPlugins:
+				if m.pluginList.FilterState() != list.Unfiltered {
+					m.pluginList.ResetFilter()
+					return m, nil
+				}
+				m.state = modelStateBrowsingModules
+				return m, nil
+			case modelStateBrowsingPluginCommits:
+				if m.pluginCommitList.FilterState() != list.Unfiltered {
+					m.pluginCommitList.ResetFilter()
+					return m, nil
+				}
+				m.state = modelStateBrowsingPlugins
+				return m, nil
+			case modelStateBrowsingOwner:
+				m.state = modelStateBrowsingModules
+				return m, nil
+			case modelStateBrowsingActivity:
+				if m.activityList.FilterState() != list.Unfiltered {
+					m.activityList.ResetFilter()
+					return m, nil
+				}
+				m.state = modelStateBrowsingModules
+				return m, nil
 			case modelStateBrowsingCommitContents:
+				if m.activeCommitTab == commitTabFiles && m.grepQuery != "" {
+					m.resetGrep()
+					return m, nil
+				}
+				if m.activeCommitTab == commitTabFiles && m.commitFilesList.FilterState() != list.Unfiltered {
+					m.commitFilesList.ResetFilter()
+					m.rebuildFilesTree()
+					return m, nil
+				}
 				if m.docsCancel != nil {
 					m.docsCancel()
 					m.docsCancel = nil
 				}
+				m.resetBlame()
 				m.state = modelStateLoadingCommits
 				m.commitFilesList.ResetSelected()
 				return m, m.client.listCommits(m.currentOwner, m.currentModule)
@@ -692,6 +1697,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case key.Matches(msg, m.keys.Plugins):
+			if m.state == modelStateBrowsingModules && m.currentOwner != "" {
+				m.state = modelStateLoadingPlugins
+				return m, m.client.listPlugins(m.currentOwner)
+			}
+
+		case key.Matches(msg, m.keys.Activity):
+			if m.state == modelStateBrowsingModules && m.currentOwner != "" {
+				m.state = modelStateLoadingActivity
+				return m, m.client.listActivity(m.remote, m.currentOwner)
+			}
+
+		case key.Matches(msg, m.keys.OwnerInfo):
+			if m.state == modelStateBrowsingModules && m.currentOwner != "" {
+				m.state = modelStateLoadingOwner
+				return m, m.client.getOwner(m.currentOwner)
+			}
+
 		case key.Matches(msg, m.keys.Navigate):
 			// From anywhere other than the navigate state, "g"
 			// enters a navigate state.
@@ -714,7 +1737,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				navigateValue := m.navigateInput.Value()
 				// Try to parse as a reference
-				parsedRemote, parsedReference, err := parseReference(navigateValue)
+				parsedRemote, parsedReference, asOf, err := parseReference(navigateValue)
 				if err == nil && parsedReference != nil {
 					// It's a reference, navigate directly to it
 					if parsedRemote != "" && m.remote != parsedRemote && parsedRemote != defaultRemote {
@@ -722,8 +1745,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, nil
 					}
 					m.currentReference = parsedReference
+					m.referenceAsOf = asOf
 					m.state = modelStateLoadingReference
-					return m, m.client.getResource(parsedReference)
+					return m, m.getReference()
 				}
 				// Otherwise, treat it as an owner
 				m.currentOwner = navigateValue
@@ -734,12 +1758,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Right):
 			switch m.state {
+			case modelStateBrowsingActivity:
+				entry, ok := m.activityList.SelectedItem().(*activityEntry)
+				if !ok {
+					return m, nil
+				}
+				m.currentModule = entry.module.Name
+				m.currentDefaultLabelName = entry.module.DefaultLabelName
+				m.currentModuleInfo = entry.module
+				m.currentModuleInfoName = m.currentOwner + "/" + m.currentModule
+				m.moduleInfoErr = nil
+				m.currentCommitID = entry.underlying.Id
+				m.state = modelStateLoadingCommitFileContents
+				return m, m.client.getCommitContent(m.currentCommitID)
+			case modelStateBrowsingPlugins:
+				plugin, ok := m.pluginList.SelectedItem().(*pluginItem)
+				if !ok {
+					return m, nil
+				}
+				m.state = modelStateLoadingPluginCommits
+				return m, m.client.listPluginCommits(m.currentOwner, plugin.underlying)
 			case modelStateBrowsingModules:
 				if len(m.currentModules) == 0 {
 					return m, nil
 				}
-				m.state = modelStateLoadingCommits
 				item := m.moduleList.SelectedItem()
+				if item == nil {
+					// The module filter excludes every module.
+					return m, nil
+				}
+				m.state = modelStateLoadingCommits
 				module, ok := item.(*module)
 				if !ok {
 					m.err = fmt.Errorf("invalid list item type: expected module")
@@ -747,6 +1795,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.currentModule = module.underlying.Name
 				m.currentDefaultLabelName = module.underlying.DefaultLabelName
+				m.currentModuleInfo = module.underlying
+				m.currentModuleInfoName = m.currentOwner + "/" + m.currentModule
+				m.moduleInfoErr = nil
 				return m, m.client.listCommits(m.currentOwner, m.currentModule)
 			case modelStateBrowsingCommits:
 				if len(m.currentCommits) == 0 {
@@ -767,6 +1818,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.state = modelStateBrowsingCommitFileContents
 					return m, nil
 				case commitTabFiles:
+					if m.grepQuery != "" && len(m.grepList.VisibleItems()) == 0 {
+						return m, nil
+					}
+					if m.filesTreeShown() && selectedFilesTreeFile(m.filesTree) == nil {
+						// A directory: let the tree open it.
+						break
+					}
 					m.state = modelStateBrowsingCommitFileContents
 					return m, nil
 				case commitTabLabels:
@@ -779,7 +1837,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.err = fmt.Errorf("invalid list item type: expected labelItem")
 						return m, tea.Quit
 					}
-					m.currentCommitID = label.underlying.CommitId
+					m.state = modelStateBrowsingCommitFileContents
+					m.currentHistoryLabel = label.underlying.Name
+					m.loadingLabelHistory = true
+					m.loadingMoreLabelHistory = false
+					m.labelHistoryList.SetItems(nil)
+					return m, m.client.listLabelHistory(m.currentOwner, m.currentModule, m.currentHistoryLabel, "")
+				}
+			case modelStateBrowsingCommitFileContents:
+				if m.activeCommitTab == commitTabLabels {
+					if m.loadingLabelHistory || len(m.labelHistoryList.Items()) == 0 {
+						return m, nil
+					}
+					entry, ok := m.labelHistoryList.SelectedItem().(*labelHistoryItem)
+					if !ok {
+						m.err = fmt.Errorf("invalid list item type: expected labelHistoryItem")
+						return m, tea.Quit
+					}
+					m.currentCommitID = entry.underlying.Id
 					m.state = modelStateLoadingCommitFileContents
 					return m, m.client.getCommitContent(m.currentCommitID)
 				}
@@ -795,15 +1870,28 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// In the deps tree, ←/h collapses the node under the
 					// cursor; only once it's collapsed does it go back out
 					// to the commit list.
-					if node := selectedDepTreeNode(m.depsTree); node != nil && node.IsOpen() {
+					if node := selectedTreeNode(m.depsTree); node != nil && node.IsOpen() {
 						m.depsTree.CloseCurrentNode()
 						return m, nil
 					}
 				}
+				if m.activeCommitTab == commitTabFiles && m.grepQuery != "" {
+					// ←/h backs out of grep results before the commit.
+					m.resetGrep()
+					return m, nil
+				}
+				if m.activeCommitTab == commitTabFiles && m.filesTreeShown() {
+					// Likewise for directories in the files tree.
+					if node := selectedTreeNode(m.filesTree); node != nil && node.IsOpen() {
+						m.filesTree.CloseCurrentNode()
+						return m, nil
+					}
+				}
 				if m.docsCancel != nil {
 					m.docsCancel()
 					m.docsCancel = nil
 				}
+				m.resetBlame()
 				m.state = modelStateLoadingCommits
 				m.commitFilesList.ResetSelected()
 				return m, m.client.listCommits(m.currentOwner, m.currentModule)
@@ -815,61 +1903,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case key.Matches(msg, m.keys.Yank):
-			var text string
-			var statusList *list.Model
-			switch m.state {
-			case modelStateBrowsingModules:
-				module, ok := m.moduleList.SelectedItem().(*module)
-				if !ok {
-					m.err = fmt.Errorf("invalid list item type: expected module")
-					return m, tea.Quit
-				}
-				text = m.buildBrowserURL("module", module.underlying.Name)
-				statusList = &m.moduleList
-			case modelStateBrowsingCommits:
-				commit, ok := m.commitList.SelectedItem().(*commit)
-				if !ok {
-					m.err = fmt.Errorf("invalid list item type: expected commit")
-					return m, tea.Quit
-				}
-				text = m.buildBrowserURL("tree", commit.underlying.Id)
-				statusList = &m.commitList
-			case modelStateBrowsingCommitContents, modelStateBrowsingCommitFileContents:
-				if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabDeps {
-					dep, ok := selectedDepNode(m.depsTree)
-					if !ok || dep.href == "" {
-						return m, nil
-					}
-					url := strings.TrimPrefix(dep.href, "https://")
-					return m, tea.Batch(tea.SetClipboard(url), m.setDepsStatus("copied "+url))
-				}
-				commitFile, ok := m.commitFilesList.SelectedItem().(*commitFile)
-				if !ok {
-					m.err = fmt.Errorf("invalid list item type: expected commitFile")
-					return m, tea.Quit
-				}
-				if m.state == modelStateBrowsingCommitFileContents {
-					// Copy file content when in the file viewer.
-					text = string(commitFile.underlying.Content)
-				} else {
-					text = m.buildBrowserURL("file", commitFile.underlying.Path)
-				}
-				statusList = &m.commitFilesList
-			}
-			if text != "" && statusList != nil {
-				text = strings.TrimPrefix(text, "https://")
-				return m, tea.Batch(
-					tea.SetClipboard(text),
-					statusList.NewStatusMessage("copied!"),
-				)
-			}
+			m.openYankMenu()
+			return m, nil
 
 		case key.Matches(msg, m.keys.Browse):
 			var url string
 			var list list.Model
+			// inFiles routes the result to the Files tab's status bar, which
+			// isn't the list's while the tree is shown.
+			var inFiles bool
 			switch m.state {
 			case modelStateBrowsingCommitFileContents:
 				list = m.commitFilesList
+				inFiles = true
 				commitFile, ok := m.commitFilesList.SelectedItem().(*commitFile)
 				if !ok {
 					m.err = fmt.Errorf("invalid list item type: expected commitFile")
@@ -888,7 +1934,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					return m, m.setDepsStatus("opened " + renderHyperlink(dep.href, dep.href))
 				}
+				if m.filesTreeShown() && selectedFilesTreeFile(m.filesTree) == nil {
+					return m, nil
+				}
 				list = m.commitFilesList
+				inFiles = true
 				commitFile, ok := m.commitFilesList.SelectedItem().(*commitFile)
 				if !ok {
 					m.err = fmt.Errorf("invalid list item type: expected commitFile")
@@ -904,6 +1954,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				url = m.buildBrowserURL("tree", commit.underlying.Id)
 			case modelStateBrowsingModules:
+				if m.moduleList.SelectedItem() == nil {
+					return m, nil
+				}
 				list = m.moduleList
 				module, ok := m.moduleList.SelectedItem().(*module)
 				if !ok {
@@ -913,11 +1966,185 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				url = m.buildBrowserURL("module", module.underlying.Name)
 			}
 			if url != "" {
+				status := list.NewStatusMessage
+				if inFiles {
+					status = m.filesStatusMessage
+				}
 				if err := browser.OpenURL(url); err != nil {
 					errStr := lipgloss.NewStyle().Foreground(colorError).Render(fmt.Sprintf("opening URL %q: %s", url, err))
-					return m, list.NewStatusMessage(errStr)
+					return m, status(errStr)
+				}
+				return m, status("opened " + lipgloss.NewStyle().Hyperlink(url).Render(url))
+			}
+
+		case key.Matches(msg, m.keys.ExportSPDX):
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabDeps && m.depsLoaded {
+				return m, m.exportSBOM(sbomFormatSPDX)
+			}
+
+		case key.Matches(msg, m.keys.Export, m.keys.ExportWithDeps):
+			if key.Matches(msg, m.keys.Export) && m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabDeps && m.depsLoaded {
+				return m, m.exportSBOM(sbomFormatCycloneDX)
+			}
+			if key.Matches(msg, m.keys.Export) && (m.state == modelStateBrowsingCommitContents || m.state == modelStateBrowsingCommitFileContents) && m.activeCommitTab == commitTabDocs && m.descriptorSet != nil && len(m.docsList.Items()) > 0 {
+				m.openDescriptorSetInput()
+				return m, nil
+			}
+			if cmd := m.exportSelectedCommit(key.Matches(msg, m.keys.ExportWithDeps)); cmd != nil {
+				return m, cmd
+			}
+
+		case key.Matches(msg, m.keys.HistorySearch):
+			if m.state == modelStateBrowsingCommits && len(m.currentCommits) > 0 {
+				m.openHistoryInput()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Grep):
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabFiles {
+				m.grepInputActive = true
+				m.grepErr = nil
+				m.grepInput.SetValue(m.grepQuery)
+				m.grepInput.CursorEnd()
+				m.grepInput.Focus()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.ToggleTree):
+			if m.state == modelStateBrowsingModules {
+				m.moduleTable = !m.moduleTable
+				m.setModuleDelegate()
+				return m, m.setModuleItems()
+			}
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabFiles {
+				m.filesAsList = !m.filesAsList
+				m.filesStatus = ""
+				if m.filesTreeShown() {
+					m.rebuildFilesTree()
+				}
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.ToggleArchived):
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabLabels {
+				m.labelArchiveFilter = m.labelArchiveFilter.next()
+				m.labelsList.SetStatusBarItemName(m.labelArchiveFilter.itemNames())
+				m.labelsList.ResetSelected()
+				m.currentLabels = nil
+				m.loadingLabels = true
+				return m, m.client.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter)
+			}
+
+		case key.Matches(msg, m.keys.ModuleFilter):
+			if m.state == modelStateBrowsingModules {
+				m.moduleFilter = m.moduleFilter.next()
+				m.moduleList.ResetSelected()
+				return m, tea.Batch(m.setModuleItems(), m.moduleList.NewStatusMessage(m.moduleListStatus()), m.loadMoreModulesIfNeeded())
+			}
+
+		case key.Matches(msg, m.keys.Sort):
+			if m.state == modelStateBrowsingModules {
+				m.moduleSort = m.moduleSort.next()
+				return m, tea.Batch(m.setModuleItems(), m.moduleList.NewStatusMessage(m.moduleListStatus()), m.loadMoreModulesIfNeeded())
+			}
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabLabels && !m.loadingLabels {
+				m.labelSort = m.labelSort.next()
+				return m, tea.Batch(
+					m.setLabelItems(),
+					m.labelsList.NewStatusMessage("sorted by "+m.labelSort.String()),
+				)
+			}
+
+		case key.Matches(msg, m.keys.NewLabel):
+			if !m.canWriteLabels() {
+				break
+			}
+			var commitID string
+			switch m.state {
+			case modelStateBrowsingCommits:
+				if commit, ok := m.commitList.SelectedItem().(*commit); ok {
+					commitID = commit.underlying.Id
+				}
+			case modelStateBrowsingCommitContents:
+				if m.activeCommitTab == commitTabLabels {
+					commitID = m.currentCommitID
+				}
+			}
+			if commitID != "" {
+				m.labelNameCommitID = commitID
+				m.labelNameInputActive = true
+				m.labelNameInput.Reset()
+				m.labelNameInput.Focus()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.MoveLabel):
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabLabels && m.canWriteLabels() {
+				label, ok := m.labelsList.SelectedItem().(*labelItem)
+				if !ok {
+					return m, nil
+				}
+				if label.underlying.CommitId == m.currentCommitID {
+					return m, m.labelsList.NewStatusMessage(fmt.Sprintf("label %q already points at this commit", label.underlying.Name))
+				}
+				m.pendingLabelWrite = &labelWrite{
+					action:       labelActionPoint,
+					owner:        m.currentOwner,
+					module:       m.currentModule,
+					label:        label.underlying.Name,
+					commitID:     m.currentCommitID,
+					fromCommitID: label.underlying.CommitId,
+				}
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.ArchiveLabel):
+			if m.state == modelStateBrowsingCommitContents && m.activeCommitTab == commitTabLabels && m.canWriteLabels() {
+				label, ok := m.labelsList.SelectedItem().(*labelItem)
+				if !ok {
+					return m, nil
+				}
+				action := labelActionArchive
+				if label.underlying.ArchiveTime != nil {
+					action = labelActionUnarchive
+				}
+				m.pendingLabelWrite = &labelWrite{
+					action: action,
+					owner:  m.currentOwner,
+					module: m.currentModule,
+					label:  label.underlying.Name,
+				}
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Deprecate):
+			if m.state == modelStateBrowsingModules {
+				if module, write, ok := m.selectedModuleWrite(moduleFieldState); ok {
+					write.state = modulev1.ModuleState_MODULE_STATE_DEPRECATED
+					if module.State == modulev1.ModuleState_MODULE_STATE_DEPRECATED {
+						write.state = modulev1.ModuleState_MODULE_STATE_ACTIVE
+					}
+					m.pendingModuleWrite = &write
+					return m, nil
+				}
+			}
+
+		case key.Matches(msg, m.keys.Visibility):
+			if m.state == modelStateBrowsingModules {
+				if module, write, ok := m.selectedModuleWrite(moduleFieldVisibility); ok {
+					write.visibility = modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE
+					if module.Visibility == modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE {
+						write.visibility = modulev1.ModuleVisibility_MODULE_VISIBILITY_PUBLIC
+					}
+					m.pendingModuleWrite = &write
+					return m, nil
 				}
-				return m, list.NewStatusMessage("opened " + lipgloss.NewStyle().Hyperlink(url).Render(url))
+			}
+
+		case key.Matches(msg, m.keys.EditModule):
+			if m.state == modelStateBrowsingModules {
+				m.startModuleEdit(moduleFieldDescription)
+				return m, nil
 			}
 
 		case key.Matches(msg, m.keys.BrowseSCM):
@@ -947,7 +2174,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.state {
 	case modelStateBrowsingModules:
 		m.moduleList, cmd = m.moduleList.Update(msg)
+		cmd = tea.Batch(cmd, m.loadMoreModulesIfNeeded())
+	case modelStateBrowsingActivity:
+		m.activityList, cmd = m.activityList.Update(msg)
+	case modelStateBrowsingPlugins:
+		m.pluginList, cmd = m.pluginList.Update(msg)
+	case modelStateBrowsingPluginCommits:
+		m.pluginCommitList, cmd = m.pluginCommitList.Update(msg)
 	case modelStateBrowsingCommits:
+		if m.historyQuery != "" {
+			m.historyList, cmd = m.historyList.Update(msg)
+			break
+		}
 		m.commitList, cmd = m.commitList.Update(msg)
 		if !m.loadingMoreCommits &&
 			m.nextCommitsPageToken != "" &&
@@ -958,6 +2196,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case modelStateBrowsingCommitContents:
 		switch m.activeCommitTab {
 		case commitTabFiles:
+			if m.grepQuery != "" {
+				prevIdx := m.grepList.Index()
+				m.grepList, cmd = m.grepList.Update(msg)
+				if m.grepList.Index() != prevIdx {
+					cmd = tea.Batch(cmd, m.selectGrepMatch())
+				}
+				return m, cmd
+			}
+			if m.filesTreeShown() {
+				// The tree takes the navigation keys; the list still gets
+				// everything else, which is how "/" starts its filter and
+				// the filter's results arrive. Whenever those change, the
+				// tree is rebuilt to show just the matching files.
+				if msg, ok := msg.(tea.KeyPressMsg); ok && !m.activeListIsFiltering() && !key.Matches(msg, m.commitFilesList.KeyMap.Filter) {
+					m.filesTree, cmd = m.filesTree.Update(msg)
+					return m, tea.Batch(cmd, m.selectFilesTreeFile())
+				}
+				filterState := m.commitFilesList.FilterState()
+				m.commitFilesList, cmd = m.commitFilesList.Update(msg)
+				if _, ok := msg.(list.FilterMatchesMsg); ok || m.commitFilesList.FilterState() != filterState {
+					m.rebuildFilesTree()
+				}
+				return m, cmd
+			}
 			m.commitFilesList, cmd = m.commitFilesList.Update(msg)
 			item := m.commitFilesList.SelectedItem()
 			commitFile, ok := item.(*commitFile)
@@ -965,12 +2227,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.err = fmt.Errorf("invalid list item type: expected commitFile")
 				return m, tea.Quit
 			}
-			m.updateFileView(commitFile.underlying)
+			cmd = tea.Batch(cmd, m.updateFileView(commitFile.underlying))
 			m.fileViewport.GotoTop()
+		case commitTabOverview:
+			m.overviewViewport, cmd = m.overviewViewport.Update(msg)
 		case commitTabLabels:
 			m.labelsList, cmd = m.labelsList.Update(msg)
 		case commitTabDeps:
 			m.depsTree, cmd = m.depsTree.Update(msg)
+		case commitTabSDKs:
+			m.sdkList, cmd = m.sdkList.Update(msg)
 		case commitTabDocs:
 			prevIdx := m.docsList.Index()
 			m.docsList, cmd = m.docsList.Update(msg)
@@ -983,9 +2249,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case modelStateBrowsingCommitFileContents:
-		if m.activeCommitTab == commitTabDocs {
+		switch m.activeCommitTab {
+		case commitTabDocs:
 			m.docsViewport, cmd = m.docsViewport.Update(msg)
-		} else {
+		case commitTabLabels:
+			m.labelHistoryList, cmd = m.labelHistoryList.Update(msg)
+			if !m.loadingMoreLabelHistory &&
+				m.nextLabelHistoryPageToken != "" &&
+				m.labelHistoryList.Paginator.OnLastPage() {
+				m.loadingMoreLabelHistory = true
+				cmd = tea.Batch(cmd, m.client.listLabelHistory(m.currentOwner, m.currentModule, m.currentHistoryLabel, m.nextLabelHistoryPageToken))
+			}
+		default:
 			m.fileViewport, cmd = m.fileViewport.Update(msg)
 		}
 	case modelStateNavigating:
@@ -995,7 +2270,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key := labelSuggestionsModule(inputValue); key != "" && key != m.currentSuggestionsKey {
 				m.currentSuggestionsKey = key
 				owner, module, _ := strings.Cut(key, "/")
-				cmd = tea.Batch(cmd, m.client.fetchLabelSuggestions(owner, module))
+				cmd = tea.Batch(cmd, m.client.fetchLabelSuggestions(owner, module, m.labelArchiveFilter))
 			}
 		} else {
 			if key := suggestionsOwner(inputValue); key != "" && key != m.currentSuggestionsKey {
@@ -1027,14 +2302,49 @@ func (m model) View() tea.View {
 		} else {
 			view += m.moduleList.View()
 		}
-		view += "\n\n" + m.help.View(m)
+		view += "\n\n" + m.footerView()
+	case modelStateLoadingActivity:
+		view = m.spinner.View() + " Loading recent commits across modules"
+	case modelStateBrowsingActivity:
+		if len(m.activityList.Items()) == 0 {
+			view += "No commits found for owner"
+		} else {
+			view += m.activityList.View()
+		}
+		view += "\n\n" + m.footerView()
+	case modelStateLoadingPlugins:
+		view = m.spinner.View() + " Loading plugins"
+	case modelStateLoadingPluginCommits:
+		view = m.spinner.View() + " Loading plugin commits"
+	case modelStateBrowsingPlugins:
+		if len(m.pluginList.Items()) == 0 {
+			view += fmt.Sprintf("No plugins found for owner; use %s to go back to its modules", keys.Back.Help().Key)
+		} else {
+			view += m.pluginList.View()
+		}
+		view += "\n\n" + m.footerView()
+	case modelStateLoadingOwner:
+		view = m.spinner.View() + " Loading owner"
+	case modelStateBrowsingOwner:
+		view += m.ownerView() + "\n" + m.footerView()
+	case modelStateBrowsingPluginCommits:
+		view += m.pluginDetailsView() + "\n"
+		if len(m.pluginCommitList.Items()) == 0 {
+			view += "No commits found for plugin"
+		} else {
+			view += m.pluginCommitList.View()
+		}
+		view += "\n\n" + m.footerView()
 	case modelStateBrowsingCommits:
-		if len(m.currentCommits) == 0 {
+		switch {
+		case m.historyShown():
+			view += m.historyStatusView() + "\n" + m.historyList.View()
+		case len(m.currentCommits) == 0:
 			view += "No commits found for module"
-		} else {
+		default:
 			view += m.commitList.View()
 		}
-		view += "\n\n" + m.help.View(m)
+		view += "\n\n" + m.footerView()
 	case modelStateBrowsingCommitContents, modelStateBrowsingCommitFileContents:
 		// Render the commit breadcrumb and tab bar as a persistent header.
 		commitURL := "https://" + m.remote + "/" + m.currentOwner + "/" + m.currentModule + "/commits/" + m.currentCommitID
@@ -1043,11 +2353,18 @@ func (m model) View() tea.View {
 			m.currentOwner, "https://"+m.remote+"/"+m.currentOwner,
 			m.currentModule, "https://"+m.remote+"/"+m.currentOwner+"/"+m.currentModule,
 			m.currentCommitID[:12], commitURL,
-		)
+		) + "  " + m.digestBadge()
+		header += "\n" + m.commitDetailsView()
 		tabBar := renderTabBar(m.activeCommitTab, m.isDark)
 
 		var contentView string
 		switch m.activeCommitTab {
+		case commitTabOverview:
+			if m.loadingModuleInfo {
+				contentView = m.spinner.View() + " Loading module"
+			} else {
+				contentView = m.overviewViewport.View()
+			}
 		case commitTabFiles:
 			fileViewStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder(), true)
 			if m.state == modelStateBrowsingCommitFileContents {
@@ -1055,19 +2372,43 @@ func (m model) View() tea.View {
 			} else {
 				fileViewStyle = fileViewStyle.BorderForeground(colorBackground)
 			}
+			filesView := m.commitFilesList.View()
+			switch {
+			case m.grepQuery != "":
+				filesView = lipgloss.NewStyle().Width(m.grepList.Width()).Render(m.grepStatusView() + "\n" + m.grepList.View())
+			case m.grepInputActive:
+				filesView = lipgloss.NewStyle().Width(m.grepList.Width()).Render(m.grepStatusView())
+			case m.filesTreeShown():
+				filesView = lipgloss.NewStyle().Width(m.filesTree.Width()).Render(m.filesStatusView() + "\n" + m.filesTree.View())
+			}
+			// The status row is reserved in resize whether or not there's
+			// anything to show, as in the Docs tab.
 			contentView = lipgloss.JoinHorizontal(
 				lipgloss.Top,
-				m.commitFilesList.View(),
-				fileViewStyle.Render(m.fileViewport.View()),
+				filesView,
+				fileViewStyle.Render(m.fileViewport.View())+"\n"+m.fileStatusView(),
 			)
 		case commitTabLabels:
-			if m.loadingLabels {
+			if m.state == modelStateBrowsingCommitFileContents {
+				if m.loadingLabelHistory {
+					contentView = m.spinner.View() + " Loading history of " + m.currentHistoryLabel
+				} else {
+					contentView = m.labelHistoryList.View()
+				}
+			} else if m.loadingLabels {
 				contentView = m.spinner.View() + " Loading labels"
 			} else if len(m.currentLabels) == 0 {
-				contentView = "No labels found for module"
+				_, labels := m.labelArchiveFilter.itemNames()
+				contentView = fmt.Sprintf("No %s found for module; use %s to change which labels are shown", labels, keys.ToggleArchived.Help().Key)
 			} else {
 				contentView = m.labelsList.View()
 			}
+		case commitTabSDKs:
+			if m.loadingSDKs {
+				contentView = m.spinner.View() + " Working out SDK versions"
+			} else {
+				contentView = m.sdkList.View()
+			}
 		case commitTabDeps:
 			if m.loadingDeps {
 				contentView = m.spinner.View() + " Loading dependency graph"
@@ -1099,8 +2440,11 @@ func (m model) View() tea.View {
 				)
 				// The search row is reserved in resize whether or not the
 				// search is open, so render it either way.
-				searchView := ""
-				if m.docsSearchActive {
+				searchView := m.blameStatusView()
+				switch {
+				case m.descriptorSetInputActive:
+					searchView = m.descriptorSetInputView()
+				case m.docsSearchActive:
 					searchView = "/" + m.docsSearchInput.View()
 				}
 				contentView += "\n" + searchView
@@ -1108,7 +2452,7 @@ func (m model) View() tea.View {
 		}
 
 		view = header + "\n" + tabBar + "\n" + contentView
-		view += "\n\n" + m.help.View(m)
+		view += "\n\n" + m.footerView()
 	case modelStateNavigating:
 		header := "Navigate to owner or reference (e.g., owner/module or owner/module:ref)"
 		borderColor := colorForeground
@@ -1176,9 +2520,15 @@ func highlightFile(filename, fileContents string, isDark bool, width int) (strin
 	// - protobuf
 	// Ref: https://buf.build/bufbuild/registry/docs/main:buf.registry.module.v1#buf.registry.module.v1.FileType
 	// Fallback is for LICENSE files.
-	if lexers.Match(filename) == lexers.Get("markdown") {
+	if isMarkdownFile(filename) {
 		return renderMarkdown(fileContents, isDark, width)
 	}
+	return highlightCode(filename, fileContents, isDark)
+}
+
+// highlightCode syntax-highlights a file's source for the terminal, by the
+// lexer its name matches.
+func highlightCode(filename, fileContents string, isDark bool) (string, error) {
 	lexer := cmp.Or(lexers.Match(filename), lexers.Fallback)
 	style := codeStyleLight
 	if isDark {
@@ -1198,6 +2548,12 @@ func highlightFile(filename, fileContents string, isDark bool, width int) (strin
 	return buffer.String(), nil
 }
 
+// isMarkdownFile reports whether the file viewer renders filename as
+// markdown rather than highlighting its source.
+func isMarkdownFile(filename string) bool {
+	return lexers.Match(filename) == lexers.Get("markdown")
+}
+
 func renderMarkdown(content string, isDark bool, width int) (string, error) {
 	style := glamour.WithStandardStyle("light")
 	if isDark {
@@ -1271,17 +2627,29 @@ func labelSuggestionsModule(input string) string {
 	return parts[0] + "/" + parts[1]
 }
 
-func parseReference(reference string) (remote string, resourceRef *modulev1.ResourceRef_Name, err error) {
+// parseReference parses a reference of the form
+// {<remote>/}<owner>/<module>{:<ref>}{@<time>}. asOf is the zero time unless
+// the reference asks to open the module or label as of a time (see
+// parseAsOf).
+func parseReference(reference string) (remote string, resourceRef *modulev1.ResourceRef_Name, asOf time.Time, err error) {
 	if reference == "" {
 		// Empty reference is fine.
-		return "", nil, nil
+		return "", nil, time.Time{}, nil
+	}
+	// Split off the time first: an RFC 3339 timestamp has colons of its own.
+	if before, after, hasAsOf := strings.Cut(reference, asOfSeparator); hasAsOf {
+		asOf, err = parseAsOf(after)
+		if err != nil {
+			return "", nil, time.Time{}, err
+		}
+		reference = before
 	}
 	slashCount := strings.Count(reference, "/")
 	if slashCount != 1 && slashCount != 2 {
-		return "", nil, fmt.Errorf("expecting reference of form {<remote>/}<owner>/<module>{:<ref>}, got %s", reference)
+		return "", nil, time.Time{}, fmt.Errorf("expecting reference of form {<remote>/}<owner>/<module>{:<ref>}{@<time>}, got %s", reference)
 	}
 	if strings.Count(reference, ":") > 1 {
-		return "", nil, fmt.Errorf(`expecting reference of form {<remote>/}<owner>/<module>{:<ref>}, got multiple ":" in %s`, reference)
+		return "", nil, time.Time{}, fmt.Errorf(`expecting reference of form {<remote>/}<owner>/<module>{:<ref>}{@<time>}, got multiple ":" in %s`, reference)
 	}
 	first, reference, hasReference := strings.Cut(reference, ":")
 	if slashCount == 2 {
@@ -1310,22 +2678,55 @@ func parseReference(reference string) (remote string, resourceRef *modulev1.Reso
 		}
 	}
 	if err := protovalidate.Validate(moduleRef); err != nil {
-		return "", nil, fmt.Errorf("validating reference: %w", err)
+		return "", nil, time.Time{}, fmt.Errorf("validating reference: %w", err)
 	}
 	// TODO: Validate remote
 	// TODO: Can we use protovalidate/cel-go for this?
-	return remote, moduleRef, nil
+	return remote, moduleRef, asOf, nil
+}
+
+// updateOverviewView renders the Overview tab for the current commit, with
+// the module's metadata if it's been loaded for the current module.
+func (m *model) updateOverviewView() {
+	if m.currentCommitFiles == nil {
+		return
+	}
+	var module *modulev1.Module
+	if m.currentModuleInfoName == m.currentOwner+"/"+m.currentModule {
+		module = m.currentModuleInfo
+	}
+	m.overviewViewport.SetContent(renderOverview(module, m.moduleInfoErr, m.currentCommitFiles, m.isDark, m.overviewViewport.Width()))
 }
 
 // updateFileView updates the file viewport with highlighted content for the given file.
-// If highlighting fails, the raw file content is shown as a fallback.
-func (m *model) updateFileView(file *modulev1.File) {
+// If highlighting fails, the raw file content is shown as a fallback. A
+// diffed file's diff can take a while, so it's rendered by the returned
+// command, with a placeholder shown until it arrives.
+func (m *model) updateFileView(file *modulev1.File) tea.Cmd {
+	m.resetFileSearch()
+	m.fileDiffID++
+	if before, after, ok := m.fileDiff(file.Path); ok {
+		m.fileDiffShown = true
+		m.setFileGutter()
+		m.fileViewport.SetContent(lipgloss.NewStyle().Faint(true).Render("Rendering diff…"))
+		return renderFileDiffCmd(m.fileDiffID, file.Path, before, after, m.diffSideBySide, m.isDark, m.fileViewport.Width())
+	}
+	m.fileDiffShown = false
+	m.fileMarkdown = isMarkdownFile(file.Path)
+	m.fileLines = strings.Count(strings.TrimSuffix(string(file.Content), "\n"), "\n") + 1
+	m.setFileGutter()
 	highlighted, err := highlightFile(file.Path, string(file.Content), m.isDark, m.fileViewport.Width())
 	if err != nil {
 		m.fileViewport.SetContent(string(file.Content))
-		return
+		return nil
 	}
 	m.fileViewport.SetContent(highlighted)
+	return nil
+}
+
+// viewingFile reports whether the file viewer has focus.
+func (m model) viewingFile() bool {
+	return m.state == modelStateBrowsingCommitFileContents && m.activeCommitTab == commitTabFiles
 }
 
 // buildBrowserURL constructs a URL for the browser based on the current context.
@@ -1349,18 +2750,37 @@ func (m model) activeListIsFiltering() bool {
 	switch m.state {
 	case modelStateBrowsingModules:
 		return m.moduleList.FilterState() == list.Filtering
+	case modelStateBrowsingActivity:
+		return m.activityList.FilterState() == list.Filtering
+	case modelStateBrowsingPlugins:
+		return m.pluginList.FilterState() == list.Filtering
+	case modelStateBrowsingPluginCommits:
+		return m.pluginCommitList.FilterState() == list.Filtering
 	case modelStateBrowsingCommits:
+		if m.historyQuery != "" {
+			return m.historyList.FilterState() == list.Filtering
+		}
 		return m.commitList.FilterState() == list.Filtering
 	case modelStateBrowsingCommitContents:
 		if m.activeCommitTab == commitTabFiles {
+			if m.grepQuery != "" {
+				return m.grepList.FilterState() == list.Filtering
+			}
 			return m.commitFilesList.FilterState() == list.Filtering
 		}
 		if m.activeCommitTab == commitTabLabels {
 			return m.labelsList.FilterState() == list.Filtering
 		}
+		if m.activeCommitTab == commitTabSDKs {
+			return m.sdkList.FilterState() == list.Filtering
+		}
 		if m.activeCommitTab == commitTabDocs {
 			return m.docsList.FilterState() == list.Filtering
 		}
+	case modelStateBrowsingCommitFileContents:
+		if m.activeCommitTab == commitTabLabels {
+			return m.labelHistoryList.FilterState() == list.Filtering
+		}
 	}
 	return false
 }
@@ -1370,17 +2790,24 @@ func (m model) activeListIsFiltering() bool {
 const (
 	// listChromeHeight is the blank line and help bar under a top-level list.
 	listChromeHeight = 5
-	// commitTabChromeHeight adds the breadcrumb and tab bar that every commit
-	// content tab is drawn beneath.
-	commitTabChromeHeight = listChromeHeight + 2
+	// commitDetailsHeight is the commit header's detail lines (see
+	// commitDetailsView).
+	commitDetailsHeight = 2
+	// commitTabChromeHeight adds the breadcrumb, commit details and tab bar
+	// that every commit content tab is drawn beneath.
+	commitTabChromeHeight = listChromeHeight + 2 + commitDetailsHeight
 	// borderSize is what a rounded border costs a pane, per dimension.
 	borderSize = 2
-	// depsStatusHeight is the deps tab's status bar: its line, plus the list
-	// style's bottom padding.
-	depsStatusHeight = 2
+	// statusBarHeight is a status bar the app draws itself above a tree or
+	// results list (see depsStatusView): its line, plus the list style's
+	// bottom padding.
+	statusBarHeight = 2
 	// docsSearchHeight is the docs tab's search input. It's reserved whether
 	// or not the search is open, so opening it doesn't reflow the docs.
 	docsSearchHeight = 1
+	// fileStatusHeight is the line under the file viewer for its search and
+	// goto-line inputs, reserved like docsSearchHeight.
+	fileStatusHeight = 1
 )
 
 // resize lays out every component for a terminal of this size. Each commit
@@ -1395,19 +2822,34 @@ func (m *model) resize(width, height int) {
 	m.moduleList.SetWidth(width)
 	m.commitList.SetHeight(height - listChromeHeight)
 	m.commitList.SetWidth(width)
+	m.pluginList.SetHeight(height - listChromeHeight)
+	m.pluginList.SetWidth(width)
+	m.pluginCommitList.SetHeight(height - listChromeHeight - pluginDetailsHeight)
+	m.pluginCommitList.SetWidth(width)
+	m.activityList.SetHeight(height - listChromeHeight)
+	m.activityList.SetWidth(width)
+	m.historyList.SetSize(width, height-listChromeHeight-statusBarHeight)
 
 	contentHeight := height - commitTabChromeHeight
 	m.commitFilesList.SetHeight(contentHeight)
 	m.commitFilesList.SetWidth(width / 2)
-	m.fileViewport.SetHeight(contentHeight - borderSize)
+	m.fileViewport.SetHeight(contentHeight - borderSize - fileStatusHeight)
 	m.fileViewport.SetWidth(width/2 - borderSize)
 	m.labelsList.SetHeight(contentHeight)
 	m.labelsList.SetWidth(width)
+	m.sdkList.SetHeight(contentHeight)
+	m.sdkList.SetWidth(width)
+	m.labelHistoryList.SetHeight(contentHeight)
+	m.labelHistoryList.SetWidth(width)
 	m.docsList.SetHeight(contentHeight)
 	m.docsList.SetWidth(width / 3)
 	m.docsViewport.SetHeight(contentHeight - borderSize - docsSearchHeight)
 	m.docsViewport.SetWidth(width*2/3 - borderSize)
-	m.depsTree.SetSize(width, contentHeight-depsStatusHeight)
+	m.depsTree.SetSize(width, contentHeight-statusBarHeight)
+	m.filesTree.SetSize(width/2, contentHeight-statusBarHeight)
+	m.grepList.SetSize(width/2, contentHeight-statusBarHeight)
+	m.overviewViewport.SetHeight(contentHeight)
+	m.overviewViewport.SetWidth(width)
 
 	m.navigateInput.SetWidth(min(width, 50))
 }
@@ -1426,14 +2868,16 @@ func (m *model) applyStyles(isDark bool) {
 	m.commitList.Styles = m.listStyles
 	m.commitFilesList.Styles = m.listStyles
 	m.labelsList.Styles = m.listStyles
+	m.sdkList.Styles = m.listStyles
+	m.pluginList.Styles = m.listStyles
+	m.pluginCommitList.Styles = m.listStyles
+	m.activityList.Styles = m.listStyles
+	m.labelHistoryList.Styles = m.listStyles
 	m.docsList.Styles = m.listStyles
+	m.grepList.Styles = m.listStyles
+	m.historyList.Styles = m.listStyles
 
-	{
-		delegate := list.NewDefaultDelegate()
-		delegate.Styles = m.listItemStyles
-		delegate.ShowDescription = true
-		m.moduleList.SetDelegate(delegate)
-	}
+	m.setModuleDelegate()
 	{
 		delegate := list.NewDefaultDelegate()
 		delegate.Styles = m.listItemStyles
@@ -1451,13 +2895,46 @@ func (m *model) applyStyles(isDark bool) {
 		delegate.Styles = m.listItemStyles
 		m.labelsList.SetDelegate(delegate)
 	}
+	{
+		delegate := list.NewDefaultDelegate()
+		delegate.Styles = m.listItemStyles
+		m.sdkList.SetDelegate(delegate)
+	}
+	{
+		delegate := list.NewDefaultDelegate()
+		delegate.Styles = m.listItemStyles
+		m.pluginList.SetDelegate(delegate)
+		m.pluginCommitList.SetDelegate(delegate)
+	}
+	{
+		delegate := list.NewDefaultDelegate()
+		delegate.Styles = m.listItemStyles
+		m.activityList.SetDelegate(delegate)
+	}
+	{
+		delegate := list.NewDefaultDelegate()
+		delegate.Styles = m.listItemStyles
+		m.labelHistoryList.SetDelegate(delegate)
+	}
 	{
 		delegate := list.NewDefaultDelegate()
 		delegate.Styles = m.listItemStyles
 		m.docsList.SetDelegate(delegate)
 	}
+	{
+		delegate := list.NewDefaultDelegate()
+		delegate.Styles = m.listItemStyles
+		delegate.SetSpacing(0)
+		m.grepList.SetDelegate(delegate)
+	}
+	{
+		delegate := list.NewDefaultDelegate()
+		delegate.Styles = m.listItemStyles
+		m.historyList.SetDelegate(delegate)
+	}
 
 	m.depsTree.SetStyles(depsTreeStyles(isDark))
+	m.filesTree.SetStyles(depsTreeStyles(isDark))
 }
 
 // setDepsStatus shows text in the deps tab's status bar and returns the cmd
@@ -1472,6 +2949,167 @@ func (m *model) setDepsStatus(text string) tea.Cmd {
 	})
 }
 
+// exportSBOM writes the current commit's SBOM in format to the working
+// directory, reporting it in the Deps tab's status bar.
+func (m *model) exportSBOM(format sbomFormat) tea.Cmd {
+	path := sbomFileName(m.currentOwner, m.currentModule, m.currentCommitID, format)
+	return tea.Batch(
+		m.setDepsStatus("exporting SBOM to "+path),
+		m.client.writeSBOM(m.currentCommitID, m.remote, format, path),
+	)
+}
+
+// getReference fetches the resource m.currentReference refers to, as of
+// m.referenceAsOf if that's set.
+func (m model) getReference() tea.Cmd {
+	if !m.referenceAsOf.IsZero() {
+		return m.client.getResourceAsOf(m.currentReference, m.referenceAsOf)
+	}
+	return m.client.getResource(m.currentReference)
+}
+
+// newCommitItem returns the commit list row for c, annotated with whatever
+// is already known about its author and labels.
+func (m model) newCommitItem(c *modulev1.Commit) *commit {
+	return &commit{
+		underlying: c,
+		remote:     m.remote,
+		owner:      m.currentOwner,
+		moduleName: m.currentModule,
+		author:     m.commitAuthors[c.CreatedByUserId],
+		labels:     m.commitLabels[c.Id],
+	}
+}
+
+// canWriteLabels reports whether label changes are offered for the current
+// module (see model.authenticated).
+func (m model) canWriteLabels() bool {
+	return m.authenticated && m.labelWritesDenied != m.currentOwner+"/"+m.currentModule
+}
+
+// footerView renders the help bar, or in its place whichever of the yank
+// menu, the label name input or a label change's confirmation prompt
+// currently owns the keys.
+func (m model) footerView() string {
+	switch {
+	case m.yankOptions != nil:
+		return m.yankMenuView()
+	case m.pendingLabelWrite != nil:
+		return m.pendingLabelWrite.prompt() + " " + m.help.ShortHelpView([]key.Binding{keys.Confirm, keys.Cancel})
+	case m.labelNameInputActive:
+		return "label: " + m.labelNameInput.View()
+	case m.pendingModuleWrite != nil:
+		return m.pendingModuleWrite.prompt() + " " + m.help.ShortHelpView([]key.Binding{keys.Confirm, keys.Cancel})
+	case m.moduleEdit != nil:
+		return m.moduleEdit.field.String() + ": " + m.moduleEditInput.View() + "  " + m.help.ShortHelpView([]key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "set")),
+			key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field")),
+			withHelp(keys.Back, "cancel"),
+		})
+	default:
+		return m.help.View(m)
+	}
+}
+
+// setLabelItems fills labelsList from currentLabels in the current sort
+// order.
+func (m *model) setLabelItems() tea.Cmd {
+	sorted := sortLabels(m.currentLabels, m.currentDefaultLabelName, m.labelSort)
+	items := make([]list.Item, len(sorted))
+	for i, label := range sorted {
+		items[i] = &labelItem{
+			underlying: label,
+			remote:     m.remote,
+			owner:      m.currentOwner,
+			moduleName: m.currentModule,
+			isDefault:  label.Name == m.currentDefaultLabelName,
+		}
+	}
+	return m.labelsList.SetItems(items)
+}
+
+// labelHistoryItems turns a page of label history into list items, marking
+// the commit the label currently points to.
+func (m model) labelHistoryItems(msg labelHistoryMsg) []list.Item {
+	var currentCommitID string
+	for _, label := range m.currentLabels {
+		if label.Name == msg.label {
+			currentCommitID = label.CommitId
+		}
+	}
+	items := make([]list.Item, len(msg.commits))
+	for i, c := range msg.commits {
+		items[i] = &labelHistoryItem{
+			underlying: c,
+			author:     msg.authors[c.CreatedByUserId],
+			current:    c.Id == currentCommitID,
+		}
+	}
+	return items
+}
+
+// commitDetailsView renders the commit header's detail lines: when the
+// commit was pushed and by whom, the labels pointing at it and its source
+// control commit, then its full digest. It's always exactly
+// commitDetailsHeight lines, each cut to the terminal width, so it never
+// pushes the tabs beneath it around.
+func (m model) commitDetailsView() string {
+	if m.currentCommit == nil {
+		return strings.Repeat("\n", commitDetailsHeight-1)
+	}
+	dim := lipgloss.NewStyle().Faint(true)
+	t := m.currentCommit.CreateTime.AsTime()
+	parts := []string{fmt.Sprintf("pushed %s (%s)", t.Format(time.Stamp), relativeTime(t))}
+	switch {
+	case m.currentCommitAuthor != "":
+		parts[0] += " by " + m.currentCommitAuthor
+	case m.currentCommit.CreatedByUserId == "" && !m.loadingCommitDetails:
+		parts[0] += " by a user who no longer exists"
+	}
+	switch {
+	case m.loadingCommitDetails:
+		parts = append(parts, dim.Render("loading labels…"))
+	case m.commitDetailsErr != nil:
+		parts = append(parts, lipgloss.NewStyle().Foreground(colorError).Render(m.commitDetailsErr.Error()))
+	case len(m.currentCommitLabels) > 0:
+		badge := lipgloss.NewStyle().Foreground(colorForeground).Bold(true)
+		badges := make([]string, len(m.currentCommitLabels))
+		for i, label := range m.currentCommitLabels {
+			badges[i] = badge.Render(label)
+		}
+		parts = append(parts, strings.Join(badges, " "))
+	default:
+		parts = append(parts, dim.Render("no labels"))
+	}
+	if scURL := m.currentCommit.SourceControlUrl; scURL != "" {
+		parts = append(parts, renderHyperlink(shortenSourceControlURL(scURL), scURL))
+	}
+	digest := "no digest"
+	if d := m.currentCommit.Digest; d != nil {
+		digest = "digest " + digestString(d)
+	}
+	line := lipgloss.NewStyle().MaxWidth(m.help.Width())
+	return line.Render(strings.Join(parts, " · ")) + "\n" + line.Render(dim.Render(digest))
+}
+
+// digestBadge renders the current commit's digest verification status for
+// the commit header. It's deliberately terse -- the header is a single line
+// -- so a mismatch is only flagged here; `buftui sbom` reports the expected
+// and actual digests.
+func (m model) digestBadge() string {
+	dim := lipgloss.NewStyle().Faint(true)
+	switch m.digestStatus {
+	case digestVerified:
+		return lipgloss.NewStyle().Foreground(colorForeground).Render("✓ digest verified")
+	case digestMismatch:
+		return lipgloss.NewStyle().Foreground(colorError).Bold(true).Render("✗ DIGEST MISMATCH")
+	case digestUnverifiable:
+		return dim.Render("? digest unverified")
+	default:
+		return dim.Render("verifying digest…")
+	}
+}
+
 // depsStatusView renders the deps tab's status bar: the dependency count,
 // temporarily replaced by the result of the last yank/browse. It reuses the
 // list styles so it reads identically to the "N commits" bar the other tabs
@@ -1496,14 +3134,23 @@ func (m model) depsStatusView() string {
 }
 
 func (m *model) loadTabIfNeeded() tea.Cmd {
+	if m.activeCommitTab == commitTabOverview && m.currentModuleInfoName != m.currentOwner+"/"+m.currentModule && !m.loadingModuleInfo {
+		m.loadingModuleInfo = true
+		m.moduleInfoErr = nil
+		return m.client.getModule(m.currentOwner, m.currentModule)
+	}
 	if m.activeCommitTab == commitTabLabels && len(m.currentLabels) == 0 && !m.loadingLabels {
 		m.loadingLabels = true
-		return m.client.listLabels(m.currentOwner, m.currentModule)
+		return m.client.listLabels(m.currentOwner, m.currentModule, m.labelArchiveFilter)
 	}
 	if m.activeCommitTab == commitTabDeps && !m.depsLoaded && !m.loadingDeps && m.depsErr == nil {
 		m.loadingDeps = true
 		return m.client.getDeps(m.currentCommitID, m.remote)
 	}
+	if m.activeCommitTab == commitTabSDKs && !m.sdksLoaded && !m.loadingSDKs && m.currentCommit != nil {
+		m.loadingSDKs = true
+		return m.client.getSDKs(m.remote, m.currentOwner, m.currentModule, m.currentCommit)
+	}
 	return nil
 }
 
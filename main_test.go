@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
 	"buf.build/go/protovalidate"
@@ -47,6 +48,7 @@ func Test_parseReference(t *testing.T) {
 		reference           string
 		wantRemote          string
 		wantResourceRef     *modulev1.ResourceRef_Name
+		wantAsOf            time.Time
 		wantError           bool
 		wantValidationError bool
 	}{
@@ -110,10 +112,36 @@ func Test_parseReference(t *testing.T) {
 			wantError:           true,
 			wantValidationError: true,
 		},
+		{
+			// A date means the end of that day.
+			reference: "bufbuild/registry@2026-09-01",
+			wantResourceRef: &modulev1.ResourceRef_Name{
+				Owner:  "bufbuild",
+				Module: "registry",
+			},
+			wantAsOf: time.Date(2026, 9, 1, 23, 59, 59, 999999999, time.UTC),
+		},
+		{
+			// The timestamp's colons don't count towards the ref's.
+			reference:  "buf.build/bufbuild/registry:main@2026-09-01T12:30:00+02:00",
+			wantRemote: "buf.build",
+			wantResourceRef: &modulev1.ResourceRef_Name{
+				Owner:  "bufbuild",
+				Module: "registry",
+				Child: &modulev1.ResourceRef_Name_Ref{
+					Ref: "main",
+				},
+			},
+			wantAsOf: time.Date(2026, 9, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			reference: "bufbuild/registry@yesterday",
+			wantError: true,
+		},
 	} {
 		t.Run("reference: "+tc.reference, func(t *testing.T) {
 			t.Parallel()
-			gotRemote, gotResourceRef, gotErr := parseReference(tc.reference)
+			gotRemote, gotResourceRef, gotAsOf, gotErr := parseReference(tc.reference)
 			if tc.wantError {
 				ok.Error(t, gotErr)
 				if tc.wantValidationError {
@@ -123,6 +151,7 @@ func Test_parseReference(t *testing.T) {
 			} else {
 				ok.Equal(t, gotRemote, tc.wantRemote)
 				ok.CmpEqual(t, gotResourceRef, tc.wantResourceRef, protocmp.Transform())
+				ok.True(t, gotAsOf.Equal(tc.wantAsOf), ok.Sprintf("got as-of time %v, want %v", gotAsOf, tc.wantAsOf))
 			}
 		})
 	}
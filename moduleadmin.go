@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"connectrpc.com/connect"
+)
+
+// moduleField is one of a module's settings the module list can change.
+type moduleField int
+
+const (
+	moduleFieldState moduleField = iota
+	moduleFieldVisibility
+	moduleFieldDescription
+	moduleFieldURL
+	moduleFieldDefaultLabel
+)
+
+func (f moduleField) String() string {
+	switch f {
+	case moduleFieldState:
+		return "state"
+	case moduleFieldVisibility:
+		return "visibility"
+	case moduleFieldDescription:
+		return "description"
+	case moduleFieldURL:
+		return "URL"
+	default:
+		return "default label"
+	}
+}
+
+// value returns module's current value for one of the text fields.
+func (f moduleField) value(module *modulev1.Module) string {
+	switch f {
+	case moduleFieldDescription:
+		return module.Description
+	case moduleFieldURL:
+		return module.Url
+	case moduleFieldDefaultLabel:
+		return module.DefaultLabelName
+	default:
+		return ""
+	}
+}
+
+// nextText cycles the text fields the edit input sets: description -> URL
+// -> default label -> description.
+func (f moduleField) nextText() moduleField {
+	switch f {
+	case moduleFieldDescription:
+		return moduleFieldURL
+	case moduleFieldURL:
+		return moduleFieldDefaultLabel
+	default:
+		return moduleFieldDescription
+	}
+}
+
+// moduleWrite is a change to one of a module's settings, held while the
+// user confirms it.
+type moduleWrite struct {
+	field  moduleField
+	owner  string
+	module string
+	// state, visibility and value are what a moduleFieldState,
+	// moduleFieldVisibility or text field is set to.
+	state      modulev1.ModuleState
+	visibility modulev1.ModuleVisibility
+	value      string
+}
+
+// prompt asks the user to confirm w. Deprecating says there's no message to
+// go with it, since the registry API has no field for one.
+func (w moduleWrite) prompt() string {
+	switch w.field {
+	case moduleFieldState:
+		if w.state == modulev1.ModuleState_MODULE_STATE_DEPRECATED {
+			return fmt.Sprintf("Deprecate module %q? (the registry API can't set a deprecation message)", w.module)
+		}
+		return fmt.Sprintf("Undeprecate module %q?", w.module)
+	case moduleFieldVisibility:
+		return fmt.Sprintf("Make module %q %s?", w.module, enumName(w.visibility.String(), "MODULE_VISIBILITY_"))
+	default:
+		if w.value == "" {
+			return fmt.Sprintf("Clear the %s of module %q?", w.field, w.module)
+		}
+		return fmt.Sprintf("Set the %s of module %q to %q?", w.field, w.module, w.value)
+	}
+}
+
+// done reports that w has been carried out.
+func (w moduleWrite) done() string {
+	switch w.field {
+	case moduleFieldState:
+		if w.state == modulev1.ModuleState_MODULE_STATE_DEPRECATED {
+			return fmt.Sprintf("deprecated module %q", w.module)
+		}
+		return fmt.Sprintf("undeprecated module %q", w.module)
+	case moduleFieldVisibility:
+		return fmt.Sprintf("made module %q %s", w.module, enumName(w.visibility.String(), "MODULE_VISIBILITY_"))
+	default:
+		return fmt.Sprintf("updated the %s of module %q", w.field, w.module)
+	}
+}
+
+type moduleWrittenMsg struct {
+	write  moduleWrite
+	module *modulev1.Module
+}
+
+type moduleWriteErrMsg struct {
+	write moduleWrite
+	err   error
+}
+
+func (e moduleWriteErrMsg) Error() string { return e.err.Error() }
+
+// permissionDenied reports whether the write failed because the token
+// isn't allowed to manage the module (or there's no token at all).
+func (e moduleWriteErrMsg) permissionDenied() bool {
+	var connectErr *connect.Error
+	if !errors.As(e.err, &connectErr) {
+		return false
+	}
+	return connectErr.Code() == connect.CodePermissionDenied || connectErr.Code() == connect.CodeUnauthenticated
+}
+
+// writeModule carries out w. The v1 registry API has no deprecation
+// message, so deprecating only changes the module's state.
+func (c *client) writeModule(w moduleWrite) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		value := &modulev1.UpdateModulesRequest_Value{
+			ModuleRef: &modulev1.ModuleRef{
+				Value: &modulev1.ModuleRef_Name_{
+					Name: &modulev1.ModuleRef_Name{
+						Owner:  w.owner,
+						Module: w.module,
+					},
+				},
+			},
+		}
+		switch w.field {
+		case moduleFieldState:
+			value.State = &w.state
+		case moduleFieldVisibility:
+			value.Visibility = &w.visibility
+		case moduleFieldDescription:
+			value.Description = &w.value
+		case moduleFieldURL:
+			value.Url = &w.value
+		case moduleFieldDefaultLabel:
+			value.DefaultLabelName = &w.value
+		}
+		response, err := c.moduleServiceClient.UpdateModules(ctx, connect.NewRequest(&modulev1.UpdateModulesRequest{
+			Values: []*modulev1.UpdateModulesRequest_Value{value},
+		}))
+		if err != nil {
+			return moduleWriteErrMsg{w, fmt.Errorf("updating module %q: %w", w.module, err)}
+		}
+		if len(response.Msg.Modules) != 1 {
+			return moduleWriteErrMsg{w, fmt.Errorf("updating module %q: expected 1 module, got %d", w.module, len(response.Msg.Modules))}
+		}
+		return moduleWrittenMsg{w, response.Msg.Modules[0]}
+	}
+}
+
+// canWriteModule reports whether changes are offered for the module named
+// name (see model.authenticated).
+func (m model) canWriteModule(name string) bool {
+	return m.authenticated && m.moduleWritesDenied != m.currentOwner+"/"+name
+}
+
+// selectedModuleWrite returns a change to the selected module's field, with
+// what it's changed to left for the caller, or false if changes to it
+// aren't offered.
+func (m model) selectedModuleWrite(field moduleField) (*modulev1.Module, moduleWrite, bool) {
+	module, ok := m.moduleList.SelectedItem().(*module)
+	if !ok || !m.canWriteModule(module.underlying.Name) {
+		return nil, moduleWrite{}, false
+	}
+	return module.underlying, moduleWrite{field: field, owner: m.currentOwner, module: module.underlying.Name}, true
+}
+
+// startModuleEdit opens the edit input on field of the selected module,
+// filled in with its current value.
+func (m *model) startModuleEdit(field moduleField) {
+	module, write, ok := m.selectedModuleWrite(field)
+	if !ok {
+		return
+	}
+	m.moduleEdit = &write
+	m.moduleEditInput.Reset()
+	m.moduleEditInput.SetValue(field.value(module))
+	m.moduleEditInput.CursorEnd()
+	m.moduleEditInput.Focus()
+}
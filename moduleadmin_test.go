@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+// newModuleAdminTestModel returns an authenticated model browsing bufbuild's
+// modules, sorted by name so bufbuild/protovalidate is selected.
+func newModuleAdminTestModel(t *testing.T) model {
+	t.Helper()
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(160, 40)
+	m.authenticated = true
+	m.currentOwner = "bufbuild"
+	m.moduleSort = moduleSortName
+	updated, _ := m.Update(c.listModules(m.currentOwner)())
+	return updated.(model)
+}
+
+// TestModuleAdmin verifies d, p and e each change the selected module once
+// confirmed, and update its row.
+func TestModuleAdmin(t *testing.T) {
+	t.Parallel()
+
+	m := newModuleAdminTestModel(t)
+	m.moduleList.Select(1) // bufbuild/registry
+	press := func(code rune, text string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+		return cmd
+	}
+	// confirm confirms the pending change and applies the result.
+	confirm := func() {
+		t.Helper()
+		cmd := press('y', "y")
+		ok.True(t, cmd != nil)
+		updated, _ := m.Update(cmd())
+		m = updated.(model)
+	}
+	selected := func() *modulev1.Module {
+		return m.moduleList.SelectedItem().(*module).underlying
+	}
+
+	press('d', "d")
+	ok.Equal(t, ansi.Strip(m.footerView())[:len(`Deprecate module "bufbuild/registry"?`)], `Deprecate module "bufbuild/registry"?`)
+	ok.Equal(t, m.pendingModuleWrite.prompt(), `Deprecate module "bufbuild/registry"? (the registry API can't set a deprecation message)`)
+	// Declining changes nothing.
+	ok.True(t, press('n', "n") == nil)
+	ok.True(t, m.pendingModuleWrite == nil)
+
+	press('d', "d")
+	confirm()
+	ok.Equal(t, selected().State, modulev1.ModuleState_MODULE_STATE_DEPRECATED)
+	ok.True(t, strings.Contains(ansi.Strip(m.moduleList.View()), `deprecated module "bufbuild/registry"`))
+
+	press('p', "p")
+	ok.True(t, strings.HasPrefix(m.pendingModuleWrite.prompt(), `Make module "bufbuild/registry" private?`))
+	confirm()
+	ok.Equal(t, selected().Visibility, modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE)
+
+	// e edits the description; tab moves on to the URL instead.
+	press('e', "e")
+	ok.Equal(t, m.moduleEdit.field, moduleFieldDescription)
+	press(tea.KeyTab, "")
+	ok.Equal(t, m.moduleEdit.field, moduleFieldURL)
+	for _, r := range "https://example.com" {
+		press(r, string(r))
+	}
+	press(tea.KeyEnter, "")
+	ok.True(t, m.moduleEdit == nil)
+	ok.Equal(t, m.pendingModuleWrite.prompt(), `Set the URL of module "bufbuild/registry" to "https://example.com"?`)
+	confirm()
+	ok.Equal(t, selected().Url, "https://example.com")
+}
+
+// TestModuleAdmin_PermissionDenied verifies a refused change is reported and
+// stops changes being offered for that module.
+func TestModuleAdmin_PermissionDenied(t *testing.T) {
+	t.Parallel()
+
+	m := newModuleAdminTestModel(t)
+	ok.Equal(t, m.moduleList.SelectedItem().(*module).underlying.Name, "bufbuild/protovalidate")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m = updated.(model)
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	m = updated.(model)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, strings.Contains(ansi.Strip(m.moduleList.View()), "not permitted to change module "))
+	ok.True(t, m.moduleWritesDenied != "")
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m = updated.(model)
+	ok.True(t, m.pendingModuleWrite == nil)
+
+	// Nor are they offered without a token.
+	m.authenticated = false
+	m.moduleList.Select(1)
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	m = updated.(model)
+	ok.True(t, m.moduleEdit == nil)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// moduleSort is the order the module list lists modules in. The zero value
+// is the order the BSR lists them in, newest first.
+type moduleSort int
+
+const (
+	moduleSortCreateTime moduleSort = iota
+	moduleSortUpdateTime
+	moduleSortName
+	moduleSortCount
+)
+
+func (s moduleSort) String() string {
+	switch s {
+	case moduleSortUpdateTime:
+		return "update time"
+	case moduleSortName:
+		return "name"
+	default:
+		return "create time"
+	}
+}
+
+func (s moduleSort) next() moduleSort {
+	return (s + 1) % moduleSortCount
+}
+
+// moduleFilter is which modules the module list includes, by visibility or
+// state.
+type moduleFilter int
+
+const (
+	moduleFilterAll moduleFilter = iota
+	moduleFilterPrivate
+	moduleFilterPublic
+	moduleFilterDeprecated
+	moduleFilterActive
+	moduleFilterCount
+)
+
+// next cycles all -> private -> public -> deprecated -> active -> all.
+func (f moduleFilter) next() moduleFilter {
+	return (f + 1) % moduleFilterCount
+}
+
+// matches reports whether module is one f includes.
+func (f moduleFilter) matches(module *modulev1.Module) bool {
+	switch f {
+	case moduleFilterPrivate:
+		return module.Visibility == modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE
+	case moduleFilterPublic:
+		return module.Visibility == modulev1.ModuleVisibility_MODULE_VISIBILITY_PUBLIC
+	case moduleFilterDeprecated:
+		return module.State == modulev1.ModuleState_MODULE_STATE_DEPRECATED
+	case moduleFilterActive:
+		return module.State != modulev1.ModuleState_MODULE_STATE_DEPRECATED
+	default:
+		return true
+	}
+}
+
+// itemNames returns the singular and plural names the module list's status
+// bar counts modules with, so the active filter is always visible.
+func (f moduleFilter) itemNames() (string, string) {
+	switch f {
+	case moduleFilterPrivate:
+		return "private module", "private modules"
+	case moduleFilterPublic:
+		return "public module", "public modules"
+	case moduleFilterDeprecated:
+		return "deprecated module", "deprecated modules"
+	case moduleFilterActive:
+		return "active module", "active modules"
+	default:
+		return "module", "modules"
+	}
+}
+
+// sortModules returns the modules filter includes, in the order sort puts
+// them: newest or most recently updated first, or alphabetically.
+func sortModules(modules []*modulev1.Module, sort moduleSort, filter moduleFilter) []*modulev1.Module {
+	var sorted []*modulev1.Module
+	for _, module := range modules {
+		if filter.matches(module) {
+			sorted = append(sorted, module)
+		}
+	}
+	slices.SortStableFunc(sorted, func(a, b *modulev1.Module) int {
+		switch sort {
+		case moduleSortName:
+			return strings.Compare(a.Name, b.Name)
+		case moduleSortUpdateTime:
+			return b.UpdateTime.AsTime().Compare(a.UpdateTime.AsTime())
+		default:
+			return b.CreateTime.AsTime().Compare(a.CreateTime.AsTime())
+		}
+	})
+	return sorted
+}
+
+// moduleColumns are the widths of the module list's table layout columns
+// other than the last, sized to fit the widest value in each.
+type moduleColumns struct {
+	name, visibility, state, label int
+}
+
+// newModuleColumns sizes the table layout's columns to fit modules.
+func newModuleColumns(modules []*modulev1.Module) *moduleColumns {
+	columns := &moduleColumns{}
+	for _, module := range modules {
+		columns.name = max(columns.name, ansi.StringWidth(moduleTitle(module)))
+		columns.visibility = max(columns.visibility, len(enumName(module.Visibility.String(), "MODULE_VISIBILITY_")))
+		columns.state = max(columns.state, len(enumName(module.State.String(), "MODULE_STATE_")))
+		columns.label = max(columns.label, len(module.DefaultLabelName))
+	}
+	return columns
+}
+
+// row renders module as a row of the table layout: its name, visibility,
+// state, default label and when it was last updated.
+func (c *moduleColumns) row(module *modulev1.Module) string {
+	pad := func(s string, width int) string {
+		if width == 0 {
+			// A column no module has a value for.
+			return ""
+		}
+		return s + strings.Repeat(" ", max(0, width-ansi.StringWidth(s))+2)
+	}
+	return pad(moduleTitle(module), c.name) +
+		pad(enumName(module.Visibility.String(), "MODULE_VISIBILITY_"), c.visibility) +
+		pad(enumName(module.State.String(), "MODULE_STATE_"), c.state) +
+		pad(module.DefaultLabelName, c.label) +
+		"updated " + relativeTime(module.UpdateTime.AsTime())
+}
+
+// setModuleItems fills moduleList from currentModules in the current sort
+// order, filter and layout.
+func (m *model) setModuleItems() tea.Cmd {
+	sorted := sortModules(m.currentModules, m.moduleSort, m.moduleFilter)
+	var columns *moduleColumns
+	if m.moduleTable {
+		columns = newModuleColumns(sorted)
+	}
+	items := make([]list.Item, len(sorted))
+	for i, currentModule := range sorted {
+		items[i] = &module{underlying: currentModule, remote: m.remote, owner: m.currentOwner, columns: columns}
+	}
+	m.moduleList.SetStatusBarItemName(m.moduleFilter.itemNames())
+	return m.moduleList.SetItems(items)
+}
+
+// setModuleDelegate gives moduleList the delegate for its layout: a table
+// layout's rows are a line each, without descriptions.
+func (m *model) setModuleDelegate() {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles = m.listItemStyles
+	delegate.ShowDescription = !m.moduleTable
+	if m.moduleTable {
+		delegate.SetSpacing(0)
+	}
+	m.moduleList.SetDelegate(delegate)
+}
+
+// moduleListStatus describes the module list's view options for a status
+// message after one changes.
+func (m model) moduleListStatus() string {
+	name, _ := m.moduleFilter.itemNames()
+	return fmt.Sprintf("%ss sorted by %s", name, m.moduleSort)
+}
+
+// loadMoreModulesIfNeeded fetches more of the owner's modules if there are
+// more and they're needed: the next page once the last one loaded is
+// scrolled to, or all of them once the list is filtered, re-sorted or
+// narrowed by the module filter, any of which would otherwise only apply to
+// the modules loaded so far.
+func (m *model) loadMoreModulesIfNeeded() tea.Cmd {
+	if m.loadingMoreModules || m.nextModulesPageToken == "" {
+		return nil
+	}
+	all := m.moduleList.FilterState() != list.Unfiltered ||
+		m.moduleFilter != moduleFilterAll ||
+		m.moduleSort != moduleSortCreateTime
+	if !all && !m.moduleList.Paginator.OnLastPage() {
+		return nil
+	}
+	m.loadingMoreModules = true
+	return m.client.listMoreModules(m.currentOwner, m.nextModulesPageToken, all)
+}
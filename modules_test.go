@@ -0,0 +1,171 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSortModules(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	modules := []*modulev1.Module{
+		{Name: "b", CreateTime: timestamppb.New(now.Add(-time.Hour)), UpdateTime: timestamppb.New(now.Add(-3 * time.Hour))},
+		{Name: "c", CreateTime: timestamppb.New(now.Add(-2 * time.Hour)), UpdateTime: timestamppb.New(now), Visibility: modulev1.ModuleVisibility_MODULE_VISIBILITY_PRIVATE},
+		{Name: "a", CreateTime: timestamppb.New(now.Add(-3 * time.Hour)), UpdateTime: timestamppb.New(now.Add(-time.Hour)), State: modulev1.ModuleState_MODULE_STATE_DEPRECATED},
+	}
+	names := func(sort moduleSort, filter moduleFilter) string {
+		var names []string
+		for _, module := range sortModules(modules, sort, filter) {
+			names = append(names, module.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	ok.Equal(t, names(moduleSortCreateTime, moduleFilterAll), "b,c,a")
+	ok.Equal(t, names(moduleSortUpdateTime, moduleFilterAll), "c,a,b")
+	ok.Equal(t, names(moduleSortName, moduleFilterAll), "a,b,c")
+	ok.Equal(t, names(moduleSortName, moduleFilterPrivate), "c")
+	ok.Equal(t, names(moduleSortName, moduleFilterPublic), "")
+	ok.Equal(t, names(moduleSortName, moduleFilterDeprecated), "a")
+	ok.Equal(t, names(moduleSortName, moduleFilterActive), "b,c")
+}
+
+// TestModuleListOptions verifies s, f and t sort, filter and lay out the
+// module list.
+func TestModuleListOptions(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	updated, _ := m.Update(c.listModules(m.currentOwner)())
+	m = updated.(model)
+	press := func(code rune, text string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+	}
+	names := func() string {
+		var names []string
+		for _, item := range m.moduleList.Items() {
+			names = append(names, item.(*module).underlying.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	ok.Equal(t, names(), "bufbuild/registry,bufbuild/protovalidate")
+	press('s', "s") // update time
+	press('s', "s") // name
+	ok.Equal(t, m.moduleSort, moduleSortName)
+	ok.Equal(t, names(), "bufbuild/protovalidate,bufbuild/registry")
+
+	press('f', "f")
+	ok.Equal(t, m.moduleFilter, moduleFilterPrivate)
+	ok.Equal(t, names(), "")
+	ok.True(t, strings.Contains(ansi.Strip(m.moduleList.View()), "No private modules"))
+	// With nothing selected there's nothing to go into.
+	press('l', "l")
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+	press('f', "f")
+	ok.Equal(t, names(), "bufbuild/protovalidate,bufbuild/registry")
+
+	press('t', "t")
+	ok.True(t, m.moduleTable)
+	row := m.moduleList.Items()[0].(*module).Title()
+	ok.Equal(t, row, "bufbuild/protovalidate  public  active  updated just now")
+}
+
+// TestModulePages verifies the module list shows the first page of an
+// owner's modules, fetches the next once it's scrolled to, and the rest at
+// once when the list is filtered.
+func TestModulePages(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "bigowner"
+	// update runs msg and hands back the listing of more modules it asks
+	// for, if any.
+	update := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(model)
+		var more tea.Cmd
+		var find func(cmd tea.Cmd)
+		find = func(cmd tea.Cmd) {
+			if cmd == nil {
+				return
+			}
+			switch msg := cmd().(type) {
+			case moreModulesMsg:
+				more = func() tea.Msg { return msg }
+			case tea.BatchMsg:
+				for _, cmd := range msg {
+					find(cmd)
+				}
+			}
+		}
+		find(cmd)
+		return more
+	}
+
+	update(c.listModules(m.currentOwner)())
+	ok.Equal(t, len(m.moduleList.Items()), 10)
+	ok.Equal(t, m.nextModulesPageToken, "10")
+
+	more := update(tea.KeyPressMsg{Code: 'G', Text: "G"})
+	ok.True(t, more != nil && m.loadingMoreModules)
+	// Pages for another owner are dropped.
+	update(moreModulesMsg{owner: "other", pageToken: "10", modules: []*modulev1.Module{{Name: "other"}}})
+	ok.Equal(t, len(m.currentModules), 10)
+	update(more())
+	ok.Equal(t, len(m.moduleList.Items()), 20)
+	ok.Equal(t, m.nextModulesPageToken, "20")
+
+	more = update(tea.KeyPressMsg{Code: '/', Text: "/"})
+	ok.True(t, more != nil)
+	update(more())
+	ok.Equal(t, len(m.currentModules), 25)
+	ok.Equal(t, m.nextModulesPageToken, "")
+	ok.True(t, !m.loadingMoreModules)
+}
+
+// TestModulePages_Error verifies that a page of modules that fails to load
+// keeps the pages before it, reports the error, and is tried again the next
+// time more modules are needed.
+func TestModulePages_Error(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "flakyowner"
+	updated, _ := m.Update(c.listModules(m.currentOwner)())
+	m = updated.(model)
+	ok.Equal(t, len(m.moduleList.Items()), 10)
+
+	// Filtering loads every page, which stops at the third.
+	m.moduleFilter = moduleFilterPublic
+	more := m.loadMoreModulesIfNeeded()
+	ok.True(t, more != nil && m.loadingMoreModules)
+	msg, isErr := more().(moreModulesErrMsg)
+	ok.True(t, isErr)
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.Equal(t, len(m.currentModules), 20)
+	ok.Equal(t, m.nextModulesPageToken, "20")
+	ok.True(t, !m.loadingMoreModules)
+	ok.True(t, strings.Contains(m.moduleList.View(), "try again later"))
+
+	more = m.loadMoreModulesIfNeeded()
+	ok.True(t, more != nil && m.loadingMoreModules)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+)
+
+// readmePaths are the files the Overview tab renders as a module's README,
+// in order of preference. buf.md predates README.md support in the BSR.
+var readmePaths = []string{"README.md", "buf.md"}
+
+// licensePath is where a module's license lives.
+const licensePath = "LICENSE"
+
+// overviewKeyStyle styles the Overview tab's "key  value" lines, wide
+// enough to align the values after the longest key.
+var overviewKeyStyle = lipgloss.NewStyle().Bold(true).Width(len("Default label") + 2)
+
+type moduleMsg struct {
+	owner  string
+	module *modulev1.Module
+}
+
+type moduleErrMsg struct {
+	owner  string
+	module string
+	err    error
+}
+
+func (e moduleErrMsg) Error() string { return e.err.Error() }
+
+// getModule fetches a module's metadata, for the Overview tab of a module
+// that wasn't opened from the module list.
+func (c *client) getModule(owner, module string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		response, err := c.moduleServiceClient.GetModules(ctx, connect.NewRequest(&modulev1.GetModulesRequest{
+			ModuleRefs: []*modulev1.ModuleRef{{
+				Value: &modulev1.ModuleRef_Name_{
+					Name: &modulev1.ModuleRef_Name{Owner: owner, Module: module},
+				},
+			}},
+		}))
+		if err != nil {
+			return moduleErrMsg{owner, module, fmt.Errorf("getting module: %w", err)}
+		}
+		if len(response.Msg.Modules) != 1 {
+			return moduleErrMsg{owner, module, fmt.Errorf("requested 1 module, got %v", len(response.Msg.Modules))}
+		}
+		return moduleMsg{owner: owner, module: response.Msg.Modules[0]}
+	}
+}
+
+// renderOverview renders the Overview tab: the module's metadata (or
+// moduleErr, if it couldn't be fetched), the license its commit carries,
+// and its rendered README. The README and license come from the commit's
+// files, so they're shown either way.
+func renderOverview(module *modulev1.Module, moduleErr error, files []*modulev1.File, isDark bool, width int) string {
+	var sections []string
+	switch {
+	case moduleErr != nil:
+		sections = append(sections, lipgloss.NewStyle().Foreground(colorError).Render("Error loading module: "+moduleErr.Error()))
+	case module != nil:
+		sections = append(sections, renderModuleMetadata(module))
+	}
+	sections = append(sections, renderLicenseLine(files))
+
+	dim := lipgloss.NewStyle().Faint(true)
+	readme := findFile(files, readmePaths...)
+	if readme == nil {
+		sections = append(sections, dim.Render("No README.md or buf.md in this commit"))
+		return strings.Join(sections, "\n\n")
+	}
+	rendered, err := renderMarkdown(string(readme.Content), isDark, width)
+	if err != nil {
+		// Fall back to the raw markdown, which is readable enough.
+		rendered = string(readme.Content)
+	}
+	sections = append(sections, dim.Render(readme.Path)+"\n"+rendered)
+	return strings.Join(sections, "\n\n")
+}
+
+// renderModuleMetadata renders a module's metadata as aligned
+// "key  value" lines, skipping anything unset.
+func renderModuleMetadata(module *modulev1.Module) string {
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, overviewKeyStyle.Render(key)+value)
+		}
+	}
+	add("Description", module.Description)
+	if module.Url != "" {
+		add("URL", renderHyperlink(module.Url, module.Url))
+	}
+	add("Visibility", enumName(module.Visibility.String(), "MODULE_VISIBILITY_"))
+	state := enumName(module.State.String(), "MODULE_STATE_")
+	if module.State == modulev1.ModuleState_MODULE_STATE_DEPRECATED {
+		state = lipgloss.NewStyle().Foreground(colorError).Render(state)
+	}
+	add("State", state)
+	add("Default label", module.DefaultLabelName)
+	if module.CreateTime != nil {
+		add("Created", formatTimestamp(module.CreateTime.AsTime()))
+	}
+	if module.UpdateTime != nil {
+		add("Updated", formatTimestamp(module.UpdateTime.AsTime()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderLicenseLine reports the license in a commit's LICENSE file, by SPDX
+// identifier where detectLicense recognizes it.
+func renderLicenseLine(files []*modulev1.File) string {
+	key := overviewKeyStyle.Render("License")
+	license := findFile(files, licensePath)
+	if license == nil {
+		return key + lipgloss.NewStyle().Faint(true).Render("none")
+	}
+	switch id, exact := detectLicense(string(license.Content)); {
+	case exact:
+		return key + id
+	case id != "":
+		return key + id + lipgloss.NewStyle().Faint(true).Render(" (-only or -or-later not stated)")
+	default:
+		return key + "unrecognized (see LICENSE in the Files tab)"
+	}
+}
+
+// findFile returns the first of paths present in files, or nil.
+func findFile(files []*modulev1.File, paths ...string) *modulev1.File {
+	for _, path := range paths {
+		for _, file := range files {
+			if file.Path == path {
+				return file
+			}
+		}
+	}
+	return nil
+}
+
+// enumName turns a protobuf enum value name into a lowercase word, e.g.
+// "MODULE_VISIBILITY_PUBLIC" into "public".
+func enumName(name, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
+
+func formatTimestamp(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Format(time.Stamp), relativeTime(t))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ownerMsg carries the profile of owner, the owner whose profile is being
+// shown.
+type ownerMsg struct {
+	owner string
+	info  *ownerv1.Owner
+}
+
+type ownerErrMsg struct {
+	owner string
+	err   error
+}
+
+func (e ownerErrMsg) Error() string { return e.err.Error() }
+
+// getOwner fetches the user or organization named name.
+func (c *client) getOwner(name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		response, err := c.ownerServiceClient.GetOwners(ctx, connect.NewRequest(&ownerv1.GetOwnersRequest{
+			OwnerRefs: []*ownerv1.OwnerRef{{Value: &ownerv1.OwnerRef_Name{Name: name}}},
+		}))
+		if err != nil {
+			return ownerErrMsg{name, fmt.Errorf("getting owner: %w", err)}
+		}
+		if len(response.Msg.Owners) != 1 {
+			return ownerErrMsg{name, fmt.Errorf("getting owner: expected 1 owner, got %d", len(response.Msg.Owners))}
+		}
+		return ownerMsg{owner: name, info: response.Msg.Owners[0]}
+	}
+}
+
+// ownerView renders the current owner's profile under a breadcrumb to it.
+func (m model) ownerView() string {
+	name := ownerName(m.currentOwnerInfo)
+	return breadcrumb(m.remote, "https://"+m.remote, name, "https://"+m.remote+"/"+name) +
+		"\n\n" + renderOwnerMetadata(m.currentOwnerInfo) + "\n"
+}
+
+// renderOwnerMetadata renders an owner's profile as aligned "key  value"
+// lines, like renderModuleMetadata: whether it's a user or an organization,
+// its description and URL, and whether it's verified. An organization's
+// members are noted as unavailable, since the registry API's owner services
+// don't expose organization membership.
+func renderOwnerMetadata(owner *ownerv1.Owner) string {
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, overviewKeyStyle.Render(key)+value)
+		}
+	}
+	var created *timestamppb.Timestamp
+	switch v := owner.GetValue().(type) {
+	case *ownerv1.Owner_User:
+		kind := "user"
+		switch v.User.Type {
+		case ownerv1.UserType_USER_TYPE_BOT:
+			kind = "bot user"
+		case ownerv1.UserType_USER_TYPE_SYSTEM:
+			kind = "system user"
+		}
+		if v.User.State == ownerv1.UserState_USER_STATE_INACTIVE {
+			kind = "inactive " + kind
+		}
+		add("Type", kind)
+		add("Description", v.User.Description)
+		if v.User.Url != "" {
+			add("URL", renderHyperlink(v.User.Url, v.User.Url))
+		}
+		add("Verification", enumName(v.User.VerificationStatus.String(), "USER_VERIFICATION_STATUS_"))
+		created = v.User.CreateTime
+	case *ownerv1.Owner_Organization:
+		add("Type", "organization")
+		add("Description", v.Organization.Description)
+		if v.Organization.Url != "" {
+			add("URL", renderHyperlink(v.Organization.Url, v.Organization.Url))
+		}
+		add("Visibility", enumName(v.Organization.Visibility.String(), "ORGANIZATION_VISIBILITY_"))
+		add("Verification", enumName(v.Organization.VerificationStatus.String(), "ORGANIZATION_VERIFICATION_STATUS_"))
+		add("Members", lipgloss.NewStyle().Faint(true).Render("not available through the registry API"))
+		created = v.Organization.CreateTime
+	}
+	if created != nil {
+		add("Created", formatTimestamp(created.AsTime()))
+	}
+	return strings.Join(lines, "\n")
+}
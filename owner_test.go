@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+// TestOwnerProfile verifies i shows the profile of the owner whose modules
+// are listed, and esc goes back to them.
+func TestOwnerProfile(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.remote = "buf.build"
+	m.currentOwner = "bufbuild"
+	m.state = modelStateBrowsingModules
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'i', Text: "i"})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingOwner)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingOwner)
+	view := ansi.Strip(m.ownerView())
+	for _, want := range []string{"Type           organization", "The Buf team.", "https://buf.build", "Visibility     public", "Verification   official", "Members        not available through the registry API"} {
+		ok.True(t, strings.Contains(view, want), ok.Sprintf("expected %q in:\n%s", want, view))
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+
+	// An owner that can't be found goes back to its modules with the error.
+	m.currentOwner = "nobody"
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: 'i', Text: "i"})
+	m = updated.(model)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+	ok.True(t, strings.Contains(ansi.Strip(m.moduleList.View()), "getting owner"))
+}
+
+// TestOwnerProfile_Stale verifies a profile or error that arrives after
+// it's stopped being waited for is dropped.
+func TestOwnerProfile_Stale(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	m.state = modelStateBrowsingModules
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'i', Text: "i"})
+	m = updated.(model)
+	msg := cmd()
+	// Gone back to the module list before the profile came in.
+	m.state = modelStateBrowsingModules
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+	ok.True(t, m.currentOwnerInfo == nil)
+
+	// Moved on to another owner before the profile came in.
+	m.state = modelStateLoadingOwner
+	m.currentOwner = "acme"
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingOwner)
+	updated, _ = m.Update(ownerErrMsg{owner: "bufbuild", err: errors.New("getting owner: boom")})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingOwner)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
+	pluginv1beta1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/plugin/v1beta1"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"connectrpc.com/connect"
+)
+
+// pluginsMsg carries the plugins of the owner whose plugins are being
+// listed.
+type pluginsMsg struct {
+	owner   string
+	plugins []*pluginv1beta1.Plugin
+}
+
+// pluginCommitsMsg carries a plugin's commits, newest first, with the
+// labels pointing at them and the names of the users who pushed them.
+type pluginCommitsMsg struct {
+	owner   string
+	plugin  *pluginv1beta1.Plugin
+	commits []*pluginv1beta1.Commit
+	labels  []*pluginv1beta1.Label
+	authors map[string]string
+}
+
+// pluginsErrMsg is a failure listing owner's plugins or one of their
+// commits.
+type pluginsErrMsg struct {
+	owner string
+	err   error
+}
+
+func (e pluginsErrMsg) Error() string { return e.err.Error() }
+
+// listPlugins lists all of owner's plugins.
+func (c *client) listPlugins(owner string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		var plugins []*pluginv1beta1.Plugin
+		pageToken := ""
+		for {
+			response, err := c.pluginServiceClient.ListPlugins(ctx, connect.NewRequest(&pluginv1beta1.ListPluginsRequest{
+				PageSize:  pageSize,
+				PageToken: pageToken,
+				OwnerRefs: []*ownerv1.OwnerRef{{Value: &ownerv1.OwnerRef_Name{Name: owner}}},
+			}))
+			if err != nil {
+				return pluginsErrMsg{owner, fmt.Errorf("listing plugins: %w", err)}
+			}
+			plugins = append(plugins, response.Msg.Plugins...)
+			if response.Msg.NextPageToken == "" {
+				break
+			}
+			pageToken = response.Msg.NextPageToken
+		}
+		return pluginsMsg{owner: owner, plugins: plugins}
+	}
+}
+
+// listPluginCommits fetches the first page of plugin's commits, along with
+// all of its unarchived labels and the names of the commits' authors.
+func (c *client) listPluginCommits(owner string, plugin *pluginv1beta1.Plugin) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		resourceRef := &pluginv1beta1.ResourceRef{Value: &pluginv1beta1.ResourceRef_Name_{
+			Name: &pluginv1beta1.ResourceRef_Name{Owner: owner, Plugin: plugin.Name},
+		}}
+		commits, err := c.pluginCommitServiceClient.ListCommits(ctx, connect.NewRequest(&pluginv1beta1.ListCommitsRequest{
+			PageSize:    pageSize,
+			ResourceRef: resourceRef,
+		}))
+		if err != nil {
+			return pluginsErrMsg{owner, fmt.Errorf("listing plugin commits: %w", err)}
+		}
+		var labels []*pluginv1beta1.Label
+		pageToken := ""
+		for {
+			response, err := c.pluginLabelServiceClient.ListLabels(ctx, connect.NewRequest(&pluginv1beta1.ListLabelsRequest{
+				PageSize:    pageSize,
+				PageToken:   pageToken,
+				ResourceRef: resourceRef,
+			}))
+			if err != nil {
+				return pluginsErrMsg{owner, fmt.Errorf("listing plugin labels: %w", err)}
+			}
+			labels = append(labels, response.Msg.Labels...)
+			if response.Msg.NextPageToken == "" {
+				break
+			}
+			pageToken = response.Msg.NextPageToken
+		}
+		var userIDs []string
+		seen := make(map[string]bool)
+		for _, commit := range commits.Msg.Commits {
+			if id := commit.CreatedByUserId; id != "" && !seen[id] {
+				seen[id] = true
+				userIDs = append(userIDs, id)
+			}
+		}
+		// Authors are only decoration; the commits are still worth showing
+		// without them.
+		authors, _ := c.resolveUserNames(ctx, userIDs)
+		return pluginCommitsMsg{owner: owner, plugin: plugin, commits: commits.Msg.Commits, labels: labels, authors: authors}
+	}
+}
+
+// pluginItem is a row of the plugin list.
+type pluginItem struct {
+	underlying *pluginv1beta1.Plugin
+}
+
+// FilterValue implements [list.Item].
+func (p *pluginItem) FilterValue() string {
+	return p.underlying.Name
+}
+
+// Title implements [list.DefaultItem].
+func (p *pluginItem) Title() string {
+	var title string
+	if p.underlying.Visibility == pluginv1beta1.PluginVisibility_PLUGIN_VISIBILITY_PRIVATE {
+		title += "󰎠"
+	}
+	title += p.underlying.Name
+	if p.underlying.State == pluginv1beta1.PluginState_PLUGIN_STATE_DEPRECATED {
+		title += " (Deprecated)"
+	}
+	return title
+}
+
+// Description implements [list.DefaultItem].
+func (p *pluginItem) Description() string {
+	desc := pluginTypeName(p.underlying.Type) + " plugin"
+	if p.underlying.Description != "" {
+		desc += " · " + p.underlying.Description
+	}
+	return desc
+}
+
+// pluginCommitItem is a row of a plugin's commit list.
+type pluginCommitItem struct {
+	underlying *pluginv1beta1.Commit
+	// author is the name of the user who pushed the commit, if known.
+	author string
+	// labels are the names of the labels pointing at the commit.
+	labels []string
+}
+
+// FilterValue implements [list.Item]. The labels are included so the list
+// can be filtered down to a version.
+func (p *pluginCommitItem) FilterValue() string {
+	return strings.Join(append([]string{p.underlying.Id}, p.labels...), " ")
+}
+
+// Title implements [list.DefaultItem].
+func (p *pluginCommitItem) Title() string {
+	title := p.underlying.Id
+	for _, label := range p.labels {
+		title += " [" + label + "]"
+	}
+	return title
+}
+
+// Description implements [list.DefaultItem].
+func (p *pluginCommitItem) Description() string {
+	t := p.underlying.CreateTime.AsTime()
+	desc := fmt.Sprintf("%s (%s)", t.Format(time.Stamp), relativeTime(t))
+	if p.author != "" {
+		desc += " · " + p.author
+	}
+	if license := p.underlying.GetPluginInfo().GetLicense().GetSpdxLicenseId(); license != "" {
+		desc += " · " + license
+	}
+	if scURL := p.underlying.SourceControlUrl; scURL != "" {
+		desc += " · " + shortenSourceControlURL(scURL)
+	}
+	return desc
+}
+
+// pluginTypeName returns how a plugin type is shown, e.g. "check".
+func pluginTypeName(t pluginv1beta1.PluginType) string {
+	if t == pluginv1beta1.PluginType_PLUGIN_TYPE_UNSPECIFIED {
+		return "unknown"
+	}
+	return strings.ToLower(strings.TrimPrefix(t.String(), "PLUGIN_TYPE_"))
+}
+
+// pluginCommitItems turns msg into the plugin commit list's rows.
+func pluginCommitItems(msg pluginCommitsMsg) []list.Item {
+	labels := make(map[string][]string)
+	for _, label := range msg.labels {
+		if label.ArchiveTime == nil {
+			labels[label.CommitId] = append(labels[label.CommitId], label.Name)
+		}
+	}
+	items := make([]list.Item, len(msg.commits))
+	for i, commit := range msg.commits {
+		items[i] = &pluginCommitItem{
+			underlying: commit,
+			author:     msg.authors[commit.CreatedByUserId],
+			labels:     labels[commit.Id],
+		}
+	}
+	return items
+}
+
+// pluginDetailsHeight is how many lines pluginDetailsView takes.
+const pluginDetailsHeight = 3
+
+// pluginDetailsView renders the current plugin's metadata above its
+// commits: its type, visibility and state, its description -- or without
+// one, the first line of the documentation its latest commit declares --
+// and where its source is.
+func (m model) pluginDetailsView() string {
+	plugin := m.currentPlugin
+	if plugin == nil {
+		return strings.Repeat("\n", pluginDetailsHeight-1)
+	}
+	dim := lipgloss.NewStyle().Faint(true)
+	visibility := "public"
+	if plugin.Visibility == pluginv1beta1.PluginVisibility_PLUGIN_VISIBILITY_PRIVATE {
+		visibility = "private"
+	}
+	state := "active"
+	if plugin.State == pluginv1beta1.PluginState_PLUGIN_STATE_DEPRECATED {
+		state = "deprecated"
+	}
+	updated := plugin.UpdateTime.AsTime()
+	lines := []string{
+		fmt.Sprintf("%s plugin · %s · %s · updated %s", pluginTypeName(plugin.Type), visibility, state, relativeTime(updated)),
+		plugin.Description,
+		plugin.SourceUrl,
+	}
+	if len(m.pluginCommitList.Items()) > 0 {
+		if latest, ok := m.pluginCommitList.Items()[0].(*pluginCommitItem); ok {
+			if doc, _, _ := strings.Cut(latest.underlying.GetPluginInfo().GetDocumentation(), "\n"); doc != "" && lines[1] == "" {
+				lines[1] = doc
+			}
+		}
+	}
+	line := lipgloss.NewStyle().MaxWidth(m.help.Width())
+	lines[0] = line.Render(lines[0])
+	lines[1] = line.Render(lines[1])
+	if lines[2] != "" {
+		lines[2] = line.Render(dim.Render(renderHyperlink(lines[2], lines[2])))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/plugin/v1beta1/pluginv1beta1connect"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+)
+
+// TestBrowsePlugins verifies P lists the owner's plugins, enter lists a
+// plugin's commits with their labels, authors and licenses, and esc goes
+// back the way it came.
+func TestBrowsePlugins(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.remote = "buf.build"
+	m.currentOwner = "bufbuild"
+	m.state = modelStateBrowsingModules
+	press := func(code rune, text string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+		return cmd
+	}
+	run := func(cmd tea.Cmd) {
+		t.Helper()
+		updated, _ := m.Update(cmd())
+		m = updated.(model)
+	}
+
+	run(press('P', "P"))
+	ok.Equal(t, m.state, modelStateBrowsingPlugins)
+	ok.Equal(t, len(m.pluginList.Items()), 1)
+	plugin := m.pluginList.Items()[0].(*pluginItem)
+	ok.Equal(t, plugin.Title(), "buf-plugin-rpc")
+	ok.Equal(t, plugin.Description(), "check plugin · Lint rules for RPCs.")
+
+	run(press(tea.KeyEnter, ""))
+	ok.Equal(t, m.state, modelStateBrowsingPluginCommits)
+	var titles []string
+	for _, item := range m.pluginCommitList.Items() {
+		titles = append(titles, item.(list.DefaultItem).Title())
+	}
+	// The archived v1.0.0 label isn't shown.
+	ok.Equal(t, strings.Join(titles, "\n"), "p2 [main] [v1.1.0]\np1")
+	desc := m.pluginCommitList.Items()[0].(*pluginCommitItem).Description()
+	ok.True(t, strings.HasSuffix(desc, " · alice · Apache-2.0"), ok.Sprintf("unexpected description %q", desc))
+	details := ansi.Strip(m.pluginDetailsView())
+	ok.True(t, strings.Contains(details, "check plugin · public · active"), ok.Sprintf("unexpected details:\n%s", details))
+	ok.True(t, strings.Contains(details, "https://github.com/bufbuild/buf-plugin-rpc"))
+
+	press(tea.KeyEscape, "")
+	ok.Equal(t, m.state, modelStateBrowsingPlugins)
+	press(tea.KeyEscape, "")
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+}
+
+// TestBrowsePluginsError verifies failing to list plugins goes back to the
+// module list with the error rather than ending the session.
+func TestBrowsePluginsError(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	// A server without the plugin services.
+	c.pluginServiceClient = pluginv1beta1connect.NewPluginServiceClient(inMemoryClient(t, http.NewServeMux()), "https://example.com")
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	m.state = modelStateBrowsingModules
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'P', Text: "P"})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingPlugins)
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+	ok.True(t, m.err == nil)
+	ok.True(t, strings.Contains(ansi.Strip(m.moduleList.View()), "listing plugins"))
+}
+
+// TestBrowsePlugins_Stale verifies plugins, plugin commits and plugin
+// errors that arrive after they've stopped being waited for are dropped.
+func TestBrowsePlugins_Stale(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	m.state = modelStateBrowsingModules
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'P', Text: "P"})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingPlugins)
+	msg := cmd()
+	// Gone back to the module list before the plugins came in.
+	m.state = modelStateBrowsingModules
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateBrowsingModules)
+
+	// Moved on to another owner before the plugins came in.
+	m.state = modelStateLoadingPlugins
+	m.currentOwner = "acme"
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingPlugins)
+	updated, _ = m.Update(pluginsErrMsg{owner: "bufbuild", err: errors.New("listing plugins: boom")})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingPlugins)
+
+	m.state = modelStateLoadingPluginCommits
+	updated, _ = m.Update(pluginCommitsMsg{owner: "bufbuild"})
+	m = updated.(model)
+	ok.Equal(t, m.state, modelStateLoadingPluginCommits)
+}
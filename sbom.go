@@ -0,0 +1,624 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/bufbuild/httplb"
+)
+
+// sbomFormat is a software bill of materials document format.
+type sbomFormat string
+
+const (
+	sbomFormatCycloneDX sbomFormat = "cyclonedx"
+	sbomFormatSPDX      sbomFormat = "spdx"
+)
+
+// fileExtension returns the conventional file extension for a document in
+// this format.
+func (f sbomFormat) fileExtension() string {
+	if f == sbomFormatSPDX {
+		return ".spdx.json"
+	}
+	return ".cdx.json"
+}
+
+// sbomComponent is a single commit in a dependency graph, as it's described
+// in an SBOM.
+type sbomComponent struct {
+	commitID string
+	owner    string
+	module   string
+	// digest is the commit's digest as "type:hex" (see digestString), or ""
+	// if the graph didn't carry one.
+	digest  string
+	created time.Time
+	href    string
+	// license is the SPDX identifier detectLicense recognized in the
+	// commit's LICENSE file. licenseFamily is the GNU license it recognized
+	// instead, without an -only or -or-later variant, which isn't declared
+	// (see detectLicense). licenseText is the file itself, kept only when
+	// detectLicense didn't recognize it, so the document can still carry the
+	// terms verbatim instead of dropping them.
+	license       string
+	licenseFamily string
+	licenseText   string
+}
+
+// name returns the component's "owner/module" name, falling back to its
+// commit ID for a commit whose module couldn't be resolved.
+func (s sbomComponent) name() string {
+	if s.module == "" {
+		return s.commitID
+	}
+	return s.owner + "/" + s.module
+}
+
+// sbom is a commit's dependency graph, flattened into the shape both SBOM
+// formats share: a list of components and who depends on whom.
+type sbom struct {
+	root       string
+	components []sbomComponent
+	// dependsOn maps a commit ID to the IDs of the commits it depends on
+	// directly.
+	dependsOn map[string][]string
+}
+
+type sbomWrittenMsg struct{ path string }
+
+type sbomErrMsg struct{ err error }
+
+func (e sbomErrMsg) Error() string { return e.err.Error() }
+
+// writeSBOM builds the SBOM for commitID and writes it to path in format,
+// for the deps tab's export action.
+func (c *client) writeSBOM(commitID, remote string, format sbomFormat, path string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		doc, err := c.fetchSBOM(ctx, commitID, remote)
+		if err != nil {
+			return sbomErrMsg{err}
+		}
+		data, err := doc.marshal(format, time.Now())
+		if err != nil {
+			return sbomErrMsg{err}
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return sbomErrMsg{fmt.Errorf("writing SBOM: %w", err)}
+		}
+		return sbomWrittenMsg{path: path}
+	}
+}
+
+// fetchSBOM fetches commitID's dependency graph (see fetchDepGraph) and the
+// verified content of every commit in it (see downloadVerified), and
+// assembles the graph and each commit's LICENSE file into an sbom.
+func (c *client) fetchSBOM(ctx context.Context, commitID, remote string) (sbom, error) {
+	deps, err := c.fetchDepGraph(ctx, commitID)
+	if err != nil {
+		return sbom{}, err
+	}
+	// The digests recorded in the SBOM are only worth anything if the
+	// content behind them is what was actually pushed, so verify every
+	// commit rather than just downloading LICENSE files.
+	files, err := c.downloadVerified(ctx, deps.graph)
+	if err != nil {
+		return sbom{}, err
+	}
+	licenses := make(map[string]string)
+	for commitID, commitFiles := range files {
+		for _, f := range commitFiles {
+			if f.Path == "LICENSE" {
+				licenses[commitID] = string(f.Content)
+			}
+		}
+	}
+	return newSBOM(remote, commitID, deps, licenses), nil
+}
+
+// newSBOM flattens a resolved dependency graph rooted at rootCommitID into
+// an sbom, with the root first and every other component sorted by name.
+func newSBOM(remote, rootCommitID string, deps depGraph, licenses map[string]string) sbom {
+	nodes := commitDepNodes(remote, deps.graph.Commits, deps.modules, deps.owners)
+	moduleByID := make(map[string]*modulev1.Module, len(deps.modules))
+	for _, m := range deps.modules {
+		moduleByID[m.Id] = m
+	}
+	ownerNameByID := make(map[string]string, len(deps.owners))
+	for _, o := range deps.owners {
+		ownerNameByID[ownerIDOf(o)] = ownerName(o)
+	}
+
+	components := make([]sbomComponent, 0, len(deps.graph.Commits))
+	for _, commit := range deps.graph.Commits {
+		component := sbomComponent{
+			commitID: commit.Id,
+			digest:   digestString(commit.Digest),
+			created:  commit.CreateTime.AsTime(),
+			href:     nodes[commit.Id].href,
+		}
+		if module := moduleByID[commit.ModuleId]; module != nil {
+			component.owner = ownerNameByID[module.OwnerId]
+			component.module = module.Name
+		}
+		if text, ok := licenses[commit.Id]; ok {
+			switch id, exact := detectLicense(text); {
+			case exact:
+				component.license = id
+			case id != "":
+				component.licenseFamily = id
+			default:
+				component.licenseText = text
+			}
+		}
+		components = append(components, component)
+	}
+	slices.SortStableFunc(components, func(a, b sbomComponent) int {
+		switch {
+		case a.commitID == rootCommitID:
+			return -1
+		case b.commitID == rootCommitID:
+			return 1
+		}
+		return cmp.Or(strings.Compare(a.name(), b.name()), strings.Compare(a.commitID, b.commitID))
+	})
+
+	dependsOn := make(map[string][]string)
+	for _, e := range deps.graph.Edges {
+		dependsOn[e.FromNode.CommitId] = append(dependsOn[e.FromNode.CommitId], e.ToNode.CommitId)
+	}
+	for _, ids := range dependsOn {
+		slices.Sort(ids)
+	}
+	return sbom{root: rootCommitID, components: components, dependsOn: dependsOn}
+}
+
+// marshal renders the SBOM as a JSON document in format, stamped with now.
+func (s sbom) marshal(format sbomFormat, now time.Time) ([]byte, error) {
+	var doc any
+	switch format {
+	case sbomFormatCycloneDX:
+		doc = s.cycloneDX(now)
+	case sbomFormatSPDX:
+		doc = s.spdx(now)
+	default:
+		return nil, fmt.Errorf("unknown SBOM format %q (expected %q or %q)", format, sbomFormatCycloneDX, sbomFormatSPDX)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding SBOM: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// The CycloneDX 1.5 JSON schema, trimmed to the parts buftui fills in.
+// Ref: https://cyclonedx.org/docs/1.5/json/
+type (
+	cdxDocument struct {
+		BOMFormat    string          `json:"bomFormat"`
+		SpecVersion  string          `json:"specVersion"`
+		SerialNumber string          `json:"serialNumber"`
+		Version      int             `json:"version"`
+		Metadata     cdxMetadata     `json:"metadata"`
+		Components   []cdxComponent  `json:"components"`
+		Dependencies []cdxDependency `json:"dependencies"`
+	}
+	cdxMetadata struct {
+		Timestamp string        `json:"timestamp"`
+		Tools     cdxTools      `json:"tools"`
+		Component *cdxComponent `json:"component,omitempty"`
+	}
+	cdxTools struct {
+		Components []cdxComponent `json:"components"`
+	}
+	cdxComponent struct {
+		Type               string           `json:"type"`
+		BOMRef             string           `json:"bom-ref,omitempty"`
+		Group              string           `json:"group,omitempty"`
+		Name               string           `json:"name"`
+		Version            string           `json:"version,omitempty"`
+		Licenses           []cdxLicense     `json:"licenses,omitempty"`
+		ExternalReferences []cdxExternalRef `json:"externalReferences,omitempty"`
+		Properties         []cdxProperty    `json:"properties,omitempty"`
+	}
+	cdxLicense struct {
+		License cdxLicenseChoice `json:"license"`
+	}
+	cdxLicenseChoice struct {
+		ID   string          `json:"id,omitempty"`
+		Name string          `json:"name,omitempty"`
+		Text *cdxLicenseText `json:"text,omitempty"`
+	}
+	cdxLicenseText struct {
+		ContentType string `json:"contentType"`
+		Content     string `json:"content"`
+	}
+	cdxExternalRef struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	}
+	cdxProperty struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	cdxDependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn"`
+	}
+)
+
+func (s sbom) cycloneDX(now time.Time) cdxDocument {
+	doc := cdxDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: "buftui"},
+			}},
+		},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+	for _, c := range s.components {
+		component := cdxComponent{
+			Type:    "library",
+			BOMRef:  c.commitID,
+			Group:   c.owner,
+			Name:    cmp.Or(c.module, c.commitID),
+			Version: c.commitID,
+		}
+		switch {
+		case c.license != "":
+			component.Licenses = []cdxLicense{{License: cdxLicenseChoice{ID: c.license}}}
+		case c.licenseText != "":
+			component.Licenses = []cdxLicense{{License: cdxLicenseChoice{
+				Name: "LICENSE",
+				Text: &cdxLicenseText{ContentType: "text/plain", Content: c.licenseText},
+			}}}
+		}
+		if c.href != "" {
+			component.ExternalReferences = []cdxExternalRef{{Type: "website", URL: c.href}}
+		}
+		if c.digest != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "buf:digest", Value: c.digest})
+		}
+		if !c.created.IsZero() {
+			component.Properties = append(component.Properties, cdxProperty{Name: "buf:create_time", Value: c.created.UTC().Format(time.RFC3339)})
+		}
+		if c.commitID == s.root {
+			doc.Metadata.Component = &component
+		} else {
+			doc.Components = append(doc.Components, component)
+		}
+		doc.Dependencies = append(doc.Dependencies, cdxDependency{
+			Ref:       c.commitID,
+			DependsOn: append([]string{}, s.dependsOn[c.commitID]...),
+		})
+	}
+	return doc
+}
+
+// The SPDX 2.3 JSON schema, trimmed to the parts buftui fills in.
+// Ref: https://spdx.github.io/spdx-spec/v2.3/
+type (
+	spdxDocument struct {
+		SPDXVersion       string                 `json:"spdxVersion"`
+		DataLicense       string                 `json:"dataLicense"`
+		SPDXID            string                 `json:"SPDXID"`
+		Name              string                 `json:"name"`
+		DocumentNamespace string                 `json:"documentNamespace"`
+		CreationInfo      spdxCreationInfo       `json:"creationInfo"`
+		Packages          []spdxPackage          `json:"packages"`
+		Relationships     []spdxRelationship     `json:"relationships"`
+		ExtractedLicenses []spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+	}
+	spdxCreationInfo struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	}
+	spdxPackage struct {
+		Name             string                `json:"name"`
+		SPDXID           string                `json:"SPDXID"`
+		VersionInfo      string                `json:"versionInfo"`
+		Supplier         string                `json:"supplier,omitempty"`
+		DownloadLocation string                `json:"downloadLocation"`
+		FilesAnalyzed    bool                  `json:"filesAnalyzed"`
+		LicenseConcluded string                `json:"licenseConcluded"`
+		LicenseDeclared  string                `json:"licenseDeclared"`
+		LicenseComments  string                `json:"licenseComments,omitempty"`
+		CopyrightText    string                `json:"copyrightText"`
+		Checksums        []spdxChecksum        `json:"checksums,omitempty"`
+		ExternalRefs     []spdxExternalRef     `json:"externalRefs,omitempty"`
+		Annotations      []spdxPackageAnnotate `json:"annotations,omitempty"`
+	}
+	spdxChecksum struct {
+		Algorithm string `json:"algorithm"`
+		Value     string `json:"checksumValue"`
+	}
+	spdxExternalRef struct {
+		Category string `json:"referenceCategory"`
+		Type     string `json:"referenceType"`
+		Locator  string `json:"referenceLocator"`
+	}
+	spdxPackageAnnotate struct {
+		Date      string `json:"annotationDate"`
+		Type      string `json:"annotationType"`
+		Annotator string `json:"annotator"`
+		Comment   string `json:"comment"`
+	}
+	spdxRelationship struct {
+		Element string `json:"spdxElementId"`
+		Type    string `json:"relationshipType"`
+		Related string `json:"relatedSpdxElement"`
+	}
+	spdxExtractedLicense struct {
+		LicenseID     string `json:"licenseId"`
+		ExtractedText string `json:"extractedText"`
+		Name          string `json:"name"`
+	}
+)
+
+func (s sbom) spdx(now time.Time) spdxDocument {
+	rootName := s.root
+	for _, c := range s.components {
+		if c.commitID == s.root {
+			rootName = c.name()
+		}
+	}
+	created := now.UTC().Format(time.RFC3339)
+	doc := spdxDocument{
+		SPDXVersion: "SPDX-2.3",
+		DataLicense: "CC0-1.0",
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Name:        rootName + "@" + s.root,
+		// The namespace only has to be a unique URI; it's never fetched.
+		DocumentNamespace: "https://spdx.org/spdxdocs/buftui/" + s.root + "-" + newUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  created,
+			Creators: []string{"Tool: buftui"},
+		},
+		Packages: []spdxPackage{},
+		Relationships: []spdxRelationship{{
+			Element: "SPDXRef-DOCUMENT",
+			Type:    "DESCRIBES",
+			Related: spdxID(s.root),
+		}},
+	}
+	for _, c := range s.components {
+		pkg := spdxPackage{
+			Name:             c.name(),
+			SPDXID:           spdxID(c.commitID),
+			VersionInfo:      c.commitID,
+			DownloadLocation: cmp.Or(c.href, "NOASSERTION"),
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+		}
+		if c.owner != "" {
+			pkg.Supplier = "Organization: " + c.owner
+		}
+		switch {
+		case c.license != "":
+			pkg.LicenseDeclared = c.license
+		case c.licenseFamily != "":
+			pkg.LicenseComments = fmt.Sprintf("LICENSE is the %s text, which doesn't say whether %s-only or %s-or-later applies", c.licenseFamily, c.licenseFamily, c.licenseFamily)
+		case c.licenseText != "":
+			// An unrecognized license still has to be declared: SPDX carries
+			// its text under a document-local LicenseRef instead.
+			ref := "LicenseRef-" + c.commitID
+			pkg.LicenseDeclared = ref
+			doc.ExtractedLicenses = append(doc.ExtractedLicenses, spdxExtractedLicense{
+				LicenseID:     ref,
+				ExtractedText: c.licenseText,
+				Name:          c.name() + " LICENSE",
+			})
+		}
+		if c.href != "" {
+			pkg.ExternalRefs = []spdxExternalRef{{Category: "OTHER", Type: "bsr-commit", Locator: c.href}}
+		}
+		if c.digest != "" {
+			// No SPDX checksum algorithm matches a BSR digest (b5 is a SHAKE256
+			// over a manifest of the files, not over a single artifact), so it
+			// travels as an annotation rather than a checksum.
+			pkg.Annotations = []spdxPackageAnnotate{{
+				Date:      created,
+				Type:      "OTHER",
+				Annotator: "Tool: buftui",
+				Comment:   "buf:digest=" + c.digest,
+			}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		for _, dep := range s.dependsOn[c.commitID] {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				Element: spdxID(c.commitID),
+				Type:    "DEPENDS_ON",
+				Related: spdxID(dep),
+			})
+		}
+	}
+	return doc
+}
+
+// spdxID returns the SPDX element ID for a commit. Commit IDs are hex, which
+// is already within SPDX's [A-Za-z0-9.-] ID alphabet.
+func spdxID(commitID string) string {
+	return "SPDXRef-Commit-" + commitID
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// digestString renders a module digest the way buf.lock does, e.g.
+// "b5:3c1f...", or "" for a nil digest.
+func digestString(d *modulev1.Digest) string {
+	if d == nil || len(d.Value) == 0 {
+		return ""
+	}
+	name := strings.ToLower(strings.TrimPrefix(d.Type.String(), "DIGEST_TYPE_"))
+	return name + ":" + hex.EncodeToString(d.Value)
+}
+
+// knownLicenses pairs SPDX license identifiers with phrases that, taken
+// together, identify the license text. Order matters: more specific
+// licenses come before ones whose phrases they also contain (BSD-3-Clause
+// before BSD-2-Clause).
+var knownLicenses = []struct {
+	id      string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name of"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+}
+
+// gnuLicenses pairs the GNU licenses, without the -only or -or-later SPDX
+// identifiers end in, with their titles. They're recognized by title alone:
+// their texts name one another (the GPL-3.0 ends by suggesting the LGPL),
+// and "version 2" is a prefix of the LGPL-2.1's "version 2.1".
+var gnuLicenses = []struct {
+	id    string
+	title string
+}{
+	{"GPL-2.0", "gnu general public license version 2,"},
+	{"GPL-3.0", "gnu general public license version 3,"},
+	{"LGPL-2.0", "gnu library general public license version 2,"},
+	{"LGPL-2.1", "gnu lesser general public license version 2.1,"},
+	{"LGPL-3.0", "gnu lesser general public license version 3,"},
+	{"AGPL-3.0", "gnu affero general public license version 3,"},
+}
+
+// licenseHeaderLines is how many of a LICENSE file's first non-blank lines
+// are searched for a GNU license's title, which spans two of them and may
+// follow a copyright line.
+const licenseHeaderLines = 3
+
+// detectLicense returns the SPDX identifier of the license in a LICENSE
+// file's text, or "" if it isn't one of knownLicenses or gnuLicenses.
+// Matching is on case- and whitespace-insensitive phrases, since license
+// files are routinely rewrapped and re-indented.
+//
+// exact is false for a GNU license, whose identifier is returned without
+// its -only or -or-later suffix: which applies is said in each source
+// file's notice, not in the license text, so it isn't a valid SPDX
+// identifier on its own.
+func detectLicense(text string) (id string, exact bool) {
+	var header []string
+	for line := range strings.Lines(text) {
+		if strings.TrimSpace(line) != "" {
+			header = append(header, line)
+		}
+		if len(header) == licenseHeaderLines {
+			break
+		}
+	}
+	normalizedHeader := strings.Join(strings.Fields(strings.ToLower(strings.Join(header, " "))), " ")
+	for _, known := range gnuLicenses {
+		if strings.Contains(normalizedHeader, known.title) {
+			return known.id, false
+		}
+	}
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, known := range knownLicenses {
+		matched := true
+		for _, phrase := range known.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return known.id, true
+		}
+	}
+	return "", false
+}
+
+// sbomFileName returns the file name the deps tab exports an SBOM to.
+func sbomFileName(owner, module, commitID string, format sbomFormat) string {
+	return fmt.Sprintf("%s-%s-%s%s", owner, module, shortCommitID(commitID), format.fileExtension())
+}
+
+// shortCommitID returns the 12-character prefix commit IDs are displayed as.
+func shortCommitID(commitID string) string {
+	if len(commitID) > 12 {
+		return commitID[:12]
+	}
+	return commitID
+}
+
+// runSBOM implements the headless "sbom" subcommand: it writes the SBOM for
+// the commit a reference resolves to, then exits.
+func runSBOM(ctx context.Context, args []string) error {
+	var flags runFlags
+	var format, output string
+	fs := newFlagSet("buftui sbom", &flags)
+	fs.StringVar(&format, "format", string(sbomFormatCycloneDX), "SBOM format: cyclonedx or spdx")
+	fs.StringVar(&output, "output", "-", "File to write the SBOM to, or - for stdout")
+	fs.StringVar(&output, "o", "-", "File to write the SBOM to, or - for stdout")
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+	remote, token, reference, asOf, err := resolveConnection(flags)
+	if err != nil {
+		return err
+	}
+	if reference == nil {
+		return fmt.Errorf("sbom requires a reference (-r owner/module{:ref}{@time})")
+	}
+
+	httpClient := httplb.NewClient()
+	defer httpClient.Close()
+	c := newClient(httpClient, remote, token)
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+	commit, err := c.resolveReferenceCommit(ctx, reference, asOf)
+	if err != nil {
+		return err
+	}
+	doc, err := c.fetchSBOM(ctx, commit.Id, remote)
+	if err != nil {
+		return err
+	}
+	data, err := doc.marshal(sbomFormat(format), time.Now())
+	if err != nil {
+		return err
+	}
+	return writeOutput(output, data)
+}
+
+// writeOutput writes data to the file at path, or to stdout for "-".
+func writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
+	tea "charm.land/bubbletea/v2"
+	"go.vanburen.xyz/ok"
+)
+
+// testSBOMGraph is registry -> protovalidate, with registry's LICENSE an
+// Apache license and protovalidate's an unrecognized one.
+func testSBOMGraph() (depGraph, map[string]string) {
+	edge := func(from, to string) *modulev1.Graph_Edge {
+		return &modulev1.Graph_Edge{
+			FromNode: &modulev1.Graph_Node{CommitId: from},
+			ToNode:   &modulev1.Graph_Node{CommitId: to},
+		}
+	}
+	deps := depGraph{
+		graph: &modulev1.Graph{
+			Commits: []*modulev1.Commit{
+				{Id: "pv0000000000", ModuleId: "mod-pv"},
+				{Id: "reg000000000", ModuleId: "mod-reg", Digest: &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0xab, 0xcd}}},
+			},
+			Edges: []*modulev1.Graph_Edge{edge("reg000000000", "pv0000000000")},
+		},
+		modules: []*modulev1.Module{
+			{Id: "mod-reg", Name: "registry", OwnerId: "org-bufbuild"},
+			{Id: "mod-pv", Name: "protovalidate", OwnerId: "org-bufbuild"},
+		},
+		owners: []*ownerv1.Owner{
+			{Value: &ownerv1.Owner_Organization{Organization: &ownerv1.Organization{Id: "org-bufbuild", Name: "bufbuild"}}},
+		},
+	}
+	licenses := map[string]string{
+		"reg000000000": "                                 Apache License\n                           Version 2.0, January 2004\n",
+		"pv0000000000": "All rights reserved.",
+	}
+	return deps, licenses
+}
+
+func TestNewSBOM(t *testing.T) {
+	t.Parallel()
+
+	deps, licenses := testSBOMGraph()
+	doc := newSBOM("buf.build", "reg000000000", deps, licenses)
+
+	ok.Equal(t, len(doc.components), 2)
+	root := doc.components[0]
+	ok.Equal(t, root.commitID, "reg000000000", ok.Sprintf("the root should come first"))
+	ok.Equal(t, root.name(), "bufbuild/registry")
+	ok.Equal(t, root.license, "Apache-2.0")
+	ok.Equal(t, root.digest, "b5:abcd")
+	ok.Equal(t, root.href, "https://buf.build/bufbuild/registry/commits/reg000000000")
+
+	dep := doc.components[1]
+	ok.Equal(t, dep.name(), "bufbuild/protovalidate")
+	ok.Equal(t, dep.license, "", ok.Sprintf("an unrecognized license has no SPDX identifier"))
+	ok.Equal(t, dep.licenseText, "All rights reserved.", ok.Sprintf("an unrecognized license keeps its text"))
+
+	ok.DeepEqual(t, doc.dependsOn["reg000000000"], []string{"pv0000000000"})
+}
+
+func TestSBOMMarshal_CycloneDX(t *testing.T) {
+	t.Parallel()
+
+	deps, licenses := testSBOMGraph()
+	data, err := newSBOM("buf.build", "reg000000000", deps, licenses).marshal(sbomFormatCycloneDX, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	ok.MustNoError(t, err)
+
+	var doc cdxDocument
+	ok.MustNoError(t, json.Unmarshal(data, &doc))
+	ok.Equal(t, doc.BOMFormat, "CycloneDX")
+	ok.True(t, strings.HasPrefix(doc.SerialNumber, "urn:uuid:"), ok.Sprintf("serial number %q should be a UUID URN", doc.SerialNumber))
+	ok.Equal(t, doc.Metadata.Timestamp, "2026-09-01T00:00:00Z")
+	ok.True(t, doc.Metadata.Component != nil, ok.Sprintf("the root commit should be the document's subject"))
+	ok.Equal(t, doc.Metadata.Component.Name, "registry")
+	ok.Equal(t, doc.Metadata.Component.Group, "bufbuild")
+	ok.Equal(t, doc.Metadata.Component.Licenses[0].License.ID, "Apache-2.0")
+
+	ok.Equal(t, len(doc.Components), 1, ok.Sprintf("only dependencies are listed as components"))
+	ok.Equal(t, doc.Components[0].Name, "protovalidate")
+	ok.Equal(t, doc.Components[0].Licenses[0].License.Text.Content, "All rights reserved.")
+
+	ok.Equal(t, len(doc.Dependencies), 2, ok.Sprintf("every component gets a dependency entry, even a leaf"))
+	ok.DeepEqual(t, doc.Dependencies[0].DependsOn, []string{"pv0000000000"})
+	ok.DeepEqual(t, doc.Dependencies[1].DependsOn, []string{})
+}
+
+func TestSBOMMarshal_SPDX(t *testing.T) {
+	t.Parallel()
+
+	deps, licenses := testSBOMGraph()
+	data, err := newSBOM("buf.build", "reg000000000", deps, licenses).marshal(sbomFormatSPDX, time.Now())
+	ok.MustNoError(t, err)
+
+	var doc spdxDocument
+	ok.MustNoError(t, json.Unmarshal(data, &doc))
+	ok.Equal(t, doc.SPDXVersion, "SPDX-2.3")
+	ok.Equal(t, doc.Name, "bufbuild/registry@reg000000000")
+	ok.Equal(t, len(doc.Packages), 2)
+	ok.Equal(t, doc.Packages[0].LicenseDeclared, "Apache-2.0")
+	ok.Equal(t, doc.Packages[1].LicenseDeclared, "LicenseRef-pv0000000000")
+	ok.Equal(t, len(doc.ExtractedLicenses), 1)
+	ok.Equal(t, doc.ExtractedLicenses[0].ExtractedText, "All rights reserved.")
+
+	ok.DeepEqual(t, doc.Relationships, []spdxRelationship{
+		{Element: "SPDXRef-DOCUMENT", Type: "DESCRIBES", Related: "SPDXRef-Commit-reg000000000"},
+		{Element: "SPDXRef-Commit-reg000000000", Type: "DEPENDS_ON", Related: "SPDXRef-Commit-pv0000000000"},
+	})
+}
+
+func TestSBOMMarshal_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	deps, licenses := testSBOMGraph()
+	_, err := newSBOM("buf.build", "reg000000000", deps, licenses).marshal("xml", time.Now())
+	ok.Error(t, err)
+}
+
+// TestDepsTab_ExportSBOM verifies x in the Deps tab exports a CycloneDX
+// SBOM and S an SPDX one.
+func TestDepsTab_ExportSBOM(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.state = modelStateBrowsingCommitContents
+	m.activeCommitTab = commitTabDeps
+	m.depsLoaded = true
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	m.currentCommitID = "abc123def456"
+	for _, test := range []struct {
+		key  rune
+		path string
+	}{
+		{'x', "bufbuild-registry-abc123def456.cdx.json"},
+		{'S', "bufbuild-registry-abc123def456.spdx.json"},
+	} {
+		// The command isn't run, so nothing is written.
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: test.key, Text: string(test.key)})
+		m = updated.(model)
+		ok.True(t, cmd != nil)
+		ok.Equal(t, m.depsStatus, "exporting SBOM to "+test.path)
+	}
+}
+
+func TestDetectLicense(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		text  string
+		want  string
+		exact bool
+	}{
+		{
+			name:  "apache, rewrapped",
+			text:  "Apache   License\n\tVersion 2.0, January 2004",
+			want:  "Apache-2.0",
+			exact: true,
+		},
+		{
+			name:  "mit",
+			text:  "MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy",
+			want:  "MIT",
+			exact: true,
+		},
+		{
+			name:  "bsd-3-clause is preferred over bsd-2-clause",
+			text:  "Redistribution and use in source and binary forms, with or without modification... Neither the name of the copyright holder",
+			want:  "BSD-3-Clause",
+			exact: true,
+		},
+		{
+			name:  "bsd-2-clause",
+			text:  "Redistribution and use in source and binary forms, with or without modification",
+			want:  "BSD-2-Clause",
+			exact: true,
+		},
+		{
+			name: "gnu title after a copyright line",
+			text: "Copyright 2026 Example Corp.\n\n    GNU AFFERO GENERAL PUBLIC LICENSE\n       Version 3, 19 November 2007\n",
+			want: "AGPL-3.0",
+		},
+		{
+			name: "gnu license named past the title",
+			text: "Copyright Example Corp.\n\nSee the GNU General Public License, version 3, for details.\n",
+			want: "",
+		},
+		{
+			name: "unknown",
+			text: "Copyright Example Corp. All rights reserved.",
+			want: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			id, exact := detectLicense(tc.text)
+			ok.Equal(t, id, tc.want)
+			ok.Equal(t, exact, tc.exact)
+		})
+	}
+}
+
+// TestDetectLicense_GNUTexts verifies the GNU licenses are told apart by
+// their full texts, which mention one another, and that which of -only and
+// -or-later applies is left undetermined.
+func TestDetectLicense_GNUTexts(t *testing.T) {
+	t.Parallel()
+
+	for file, want := range map[string]string{
+		"GPL-2.0.txt":  "GPL-2.0",
+		"GPL-3.0.txt":  "GPL-3.0",
+		"LGPL-2.1.txt": "LGPL-2.1",
+		"LGPL-3.0.txt": "LGPL-3.0",
+	} {
+		text, err := os.ReadFile(filepath.Join("testdata", "licenses", file))
+		ok.MustNoError(t, err)
+		id, exact := detectLicense(string(text))
+		ok.Equal(t, id, want, ok.Sprintf("%s", file))
+		ok.True(t, !exact, ok.Sprintf("%s", file))
+	}
+}
+
+// TestSBOMMarshal_GNULicense verifies a GNU license, whose -only or
+// -or-later variant isn't known, isn't declared: SPDX says NOASSERTION,
+// with a comment naming it, and CycloneDX leaves it out.
+func TestSBOMMarshal_GNULicense(t *testing.T) {
+	t.Parallel()
+
+	deps, licenses := testSBOMGraph()
+	text, err := os.ReadFile(filepath.Join("testdata", "licenses", "GPL-3.0.txt"))
+	ok.MustNoError(t, err)
+	licenses["reg000000000"] = string(text)
+	doc := newSBOM("buf.build", "reg000000000", deps, licenses)
+	ok.Equal(t, doc.components[0].licenseFamily, "GPL-3.0")
+
+	data, err := doc.marshal(sbomFormatSPDX, time.Now())
+	ok.MustNoError(t, err)
+	var spdxDoc spdxDocument
+	ok.MustNoError(t, json.Unmarshal(data, &spdxDoc))
+	ok.Equal(t, spdxDoc.Packages[0].LicenseDeclared, "NOASSERTION")
+	ok.True(t, strings.Contains(spdxDoc.Packages[0].LicenseComments, "GPL-3.0-only or GPL-3.0-or-later"))
+	ok.Equal(t, len(spdxDoc.ExtractedLicenses), 1, ok.Sprintf("only the unrecognized license is extracted"))
+
+	data, err = doc.marshal(sbomFormatCycloneDX, time.Now())
+	ok.MustNoError(t, err)
+	var cdxDoc cdxDocument
+	ok.MustNoError(t, json.Unmarshal(data, &cdxDoc))
+	ok.Equal(t, len(cdxDoc.Metadata.Component.Licenses), 0)
+}
+
+func TestDigestString(t *testing.T) {
+	t.Parallel()
+
+	ok.Equal(t, digestString(nil), "")
+	ok.Equal(t, digestString(&modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0x01, 0xff}}), "b5:01ff")
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+)
+
+// sdkEcosystem is a package registry the BSR generates SDKs into.
+type sdkEcosystem int
+
+const (
+	sdkGo sdkEcosystem = iota
+	sdkNPM
+	sdkMaven
+	sdkPython
+)
+
+func (e sdkEcosystem) String() string {
+	switch e {
+	case sdkGo:
+		return "Go"
+	case sdkNPM:
+		return "npm"
+	case sdkMaven:
+		return "Maven"
+	case sdkPython:
+		return "PyPI"
+	default:
+		return ""
+	}
+}
+
+// sdkPlugin is a remote plugin the SDK tab shows a module's generated SDK
+// for.
+type sdkPlugin struct {
+	ecosystem sdkEcosystem
+	owner     string
+	name      string
+}
+
+// sdkPlugins are the plugins the SDK tab lists. The registry API has no way
+// to list which plugins a module's SDKs are generated with -- the BSR
+// generates any of them on demand -- so these are the common ones for each
+// ecosystem.
+var sdkPlugins = []sdkPlugin{
+	{sdkGo, "protocolbuffers", "go"},
+	{sdkGo, "connectrpc", "go"},
+	{sdkGo, "grpc", "go"},
+	{sdkNPM, "bufbuild", "es"},
+	{sdkMaven, "protocolbuffers", "java"},
+	{sdkMaven, "grpc", "java"},
+	{sdkPython, "protocolbuffers", "python"},
+	{sdkPython, "grpc", "python"},
+}
+
+// packageName returns the name of the plugin's SDK for owner/module in its
+// ecosystem: a Go module path, npm package, Maven artifact ID or Python
+// distribution.
+func (p sdkPlugin) packageName(remote, owner, module string) string {
+	switch p.ecosystem {
+	case sdkGo:
+		return fmt.Sprintf("%s/gen/go/%s/%s/%s/%s", remote, owner, module, p.owner, p.name)
+	case sdkNPM:
+		return fmt.Sprintf("@buf/%s_%s.%s_%s", owner, module, p.owner, p.name)
+	case sdkMaven:
+		return fmt.Sprintf("%s_%s_%s_%s", owner, module, p.owner, p.name)
+	case sdkPython:
+		return fmt.Sprintf("%s-%s-%s-%s", owner, module, p.owner, p.name)
+	default:
+		return ""
+	}
+}
+
+// installCommand returns how to depend on version of the plugin's SDK for
+// owner/module.
+func (p sdkPlugin) installCommand(remote, owner, module, version string) string {
+	name := p.packageName(remote, owner, module)
+	switch p.ecosystem {
+	case sdkGo:
+		return "go get " + name + "@" + version
+	case sdkNPM:
+		return "npm install " + name + "@" + version
+	case sdkMaven:
+		return "build.buf.gen:" + name + ":" + version
+	case sdkPython:
+		return fmt.Sprintf("pip install %s==%s --extra-index-url https://%s/gen/python", name, version, remote)
+	default:
+		return ""
+	}
+}
+
+// sdkVersionPatterns match each ecosystem's SDK versions, capturing the
+// plugin version, plugin revision, commit time and short commit ID, e.g.
+// v1.36.11-20260713175918-10d915f5b43b.1 in Go.
+var sdkVersionPatterns = map[sdkEcosystem]*regexp.Regexp{
+	sdkGo:     regexp.MustCompile(`^v(.+)-(\d{14})-([0-9a-f]{12})\.(\d+)$`),
+	sdkNPM:    regexp.MustCompile(`^(.+)-(\d{14})-([0-9a-f]{12})\.(\d+)$`),
+	sdkMaven:  regexp.MustCompile(`^(.+)\.(\d+)\.(\d{14})\.([0-9a-f]{12})$`),
+	sdkPython: regexp.MustCompile(`^(.+)\.(\d+)\.(\d{14})\+([0-9a-f]{12})$`),
+}
+
+// parseSDKVersion splits an SDK version in ecosystem e into the plugin
+// version and revision it was generated with, or reports false if it isn't
+// one.
+func parseSDKVersion(e sdkEcosystem, version string) (pluginVersion, revision string, ok bool) {
+	match := sdkVersionPatterns[e].FindStringSubmatch(version)
+	if match == nil {
+		return "", "", false
+	}
+	switch e {
+	case sdkGo, sdkNPM:
+		return match[1], match[4], true
+	default:
+		return match[1], match[2], true
+	}
+}
+
+// sdkVersion returns the version of an SDK generated for commit with the
+// given plugin version and revision, in ecosystem e -- the pseudo-version
+// that ends up in a go.mod, for instance.
+func sdkVersion(e sdkEcosystem, pluginVersion, revision string, commit *modulev1.Commit) string {
+	timestamp := commit.CreateTime.AsTime().UTC().Format("20060102150405")
+	commitID := commit.Id[:min(len(commit.Id), 12)]
+	switch e {
+	case sdkGo:
+		return fmt.Sprintf("v%s-%s-%s.%s", pluginVersion, timestamp, commitID, revision)
+	case sdkNPM:
+		return fmt.Sprintf("%s-%s-%s.%s", pluginVersion, timestamp, commitID, revision)
+	case sdkMaven:
+		return fmt.Sprintf("%s.%s.%s.%s", pluginVersion, revision, timestamp, commitID)
+	case sdkPython:
+		return fmt.Sprintf("%s.%s.%s+%s", pluginVersion, revision, timestamp, commitID)
+	default:
+		return ""
+	}
+}
+
+// sdk is one row of the SDK tab: the plugin's SDK for the current commit,
+// at version, or err if its version couldn't be worked out.
+type sdk struct {
+	plugin  sdkPlugin
+	version string
+	err     error
+}
+
+// sdksMsg carries the SDKs of the commit with ID commitID, so a response
+// for a commit since left can be dropped.
+type sdksMsg struct {
+	commitID string
+	sdks     []sdk
+}
+
+// getSDKs works out the version of each of sdkPlugins' SDKs for commit of
+// owner/module on remote, concurrently.
+//
+// An SDK's version is built from the plugin version and revision it's
+// generated with and the commit's time and ID. The registry API doesn't
+// say which plugin version and revision are current, so they're taken from
+// the latest version in the SDK's package registry -- the BSR generates an
+// SDK for any commit at any plugin version on demand, so that version built
+// from this commit is one the registry will serve.
+func (c *client) getSDKs(remote, owner, module string, commit *modulev1.Commit) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		sdks := make([]sdk, len(sdkPlugins))
+		var wg sync.WaitGroup
+		for i, plugin := range sdkPlugins {
+			wg.Go(func() {
+				sdks[i] = sdk{plugin: plugin}
+				latest, err := c.latestSDKVersion(ctx, plugin, remote, owner, module)
+				if err != nil {
+					sdks[i].err = err
+					return
+				}
+				pluginVersion, revision, ok := parseSDKVersion(plugin.ecosystem, latest)
+				if !ok {
+					sdks[i].err = fmt.Errorf("unrecognized version %q", latest)
+					return
+				}
+				sdks[i].version = sdkVersion(plugin.ecosystem, pluginVersion, revision, commit)
+			})
+		}
+		wg.Wait()
+		return sdksMsg{commitID: commit.Id, sdks: sdks}
+	}
+}
+
+var (
+	mavenReleasePattern   = regexp.MustCompile(`<release>([^<]+)</release>`)
+	pythonVersionsPattern = regexp.MustCompile(`\d[0-9.]*\.\d{14}(?:\+|%2[Bb])[0-9a-f]{12}`)
+)
+
+// latestSDKVersion fetches the latest version of the plugin's SDK for
+// owner/module from its package registry on the remote.
+func (c *client) latestSDKVersion(ctx context.Context, plugin sdkPlugin, remote, owner, module string) (string, error) {
+	name := plugin.packageName(remote, owner, module)
+	switch plugin.ecosystem {
+	case sdkGo:
+		body, err := c.getRegistry(ctx, fmt.Sprintf("%s/gen/go/%s/%s/%s/%s/@latest", c.address, owner, module, plugin.owner, plugin.name))
+		if err != nil {
+			return "", err
+		}
+		var info struct{ Version string }
+		if err := json.Unmarshal(body, &info); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", name, err)
+		}
+		return info.Version, nil
+	case sdkNPM:
+		body, err := c.getRegistry(ctx, c.address+"/gen/npm/v1/"+strings.Replace(name, "/", "%2f", 1))
+		if err != nil {
+			return "", err
+		}
+		var packument struct {
+			DistTags struct {
+				Latest string `json:"latest"`
+			} `json:"dist-tags"`
+		}
+		if err := json.Unmarshal(body, &packument); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", name, err)
+		}
+		return packument.DistTags.Latest, nil
+	case sdkMaven:
+		body, err := c.getRegistry(ctx, c.address+"/gen/maven/build/buf/gen/"+name+"/maven-metadata.xml")
+		if err != nil {
+			return "", err
+		}
+		match := mavenReleasePattern.FindSubmatch(body)
+		if match == nil {
+			return "", fmt.Errorf("no release of %s", name)
+		}
+		return string(match[1]), nil
+	case sdkPython:
+		body, err := c.getRegistry(ctx, c.address+"/gen/python/"+name+"/")
+		if err != nil {
+			return "", err
+		}
+		// A simple index lists every file of every version, in no
+		// particular order; the latest is the one generated from the
+		// newest commit.
+		var latest string
+		for _, match := range pythonVersionsPattern.FindAll(body, -1) {
+			version, err := url.PathUnescape(string(match))
+			if err != nil {
+				continue
+			}
+			if latest == "" || sdkVersionTime(sdkPython, version) >= sdkVersionTime(sdkPython, latest) {
+				latest = version
+			}
+		}
+		if latest == "" {
+			return "", fmt.Errorf("no release of %s", name)
+		}
+		return latest, nil
+	default:
+		return "", fmt.Errorf("unknown ecosystem %v", plugin.ecosystem)
+	}
+}
+
+// sdkVersionTime returns the commit time of an SDK version, as the
+// YYYYMMDDhhmmss string it's written in.
+func sdkVersionTime(e sdkEcosystem, version string) string {
+	match := sdkVersionPatterns[e].FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	switch e {
+	case sdkGo, sdkNPM:
+		return match[2]
+	default:
+		return match[3]
+	}
+}
+
+// getRegistry GETs target from one of the remote's package registries.
+func (c *client) getRegistry(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("not available")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+}
+
+// sdkItem is a row of the SDK tab's list.
+type sdkItem struct {
+	sdk     sdk
+	install string
+}
+
+// FilterValue implements list.Item.
+func (s *sdkItem) FilterValue() string {
+	return s.sdk.plugin.ecosystem.String() + " " + s.sdk.plugin.owner + "/" + s.sdk.plugin.name
+}
+
+// Title implements list.DefaultItem.
+func (s *sdkItem) Title() string {
+	return fmt.Sprintf("%s · %s/%s", s.sdk.plugin.ecosystem, s.sdk.plugin.owner, s.sdk.plugin.name)
+}
+
+// Description implements list.DefaultItem.
+func (s *sdkItem) Description() string {
+	if s.sdk.err != nil {
+		return "unavailable: " + s.sdk.err.Error()
+	}
+	return s.install
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSDKVersion(t *testing.T) {
+	t.Parallel()
+
+	commit := &modulev1.Commit{
+		Id:         "7a6bc1e3207144b38e9066861e1de0ff",
+		CreateTime: timestamppb.New(time.Date(2026, 9, 1, 12, 30, 5, 0, time.FixedZone("", -7*60*60))),
+	}
+	for _, test := range []struct {
+		ecosystem sdkEcosystem
+		latest    string
+		want      string
+	}{
+		{sdkGo, "v1.36.11-20260713175918-10d915f5b43b.1", "v1.36.11-20260901193005-7a6bc1e32071.1"},
+		{sdkNPM, "2.10.2-20260713175918-10d915f5b43b.3", "2.10.2-20260901193005-7a6bc1e32071.3"},
+		{sdkMaven, "33.1.0.2.20260713175918.10d915f5b43b", "33.1.0.2.20260901193005.7a6bc1e32071"},
+		{sdkPython, "33.1.0.1.20260713175918+10d915f5b43b", "33.1.0.1.20260901193005+7a6bc1e32071"},
+	} {
+		pluginVersion, revision, parsed := parseSDKVersion(test.ecosystem, test.latest)
+		ok.True(t, parsed, ok.Sprintf("%v: %q", test.ecosystem, test.latest))
+		ok.Equal(t, sdkVersion(test.ecosystem, pluginVersion, revision, commit), test.want)
+	}
+
+	_, _, parsed := parseSDKVersion(sdkGo, "v1.36.11")
+	ok.True(t, !parsed)
+}
+
+func TestInstallCommand(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		plugin sdkPlugin
+		want   string
+	}{
+		{sdkPlugin{sdkGo, "connectrpc", "go"}, "go get buf.build/gen/go/acme/petapis/connectrpc/go@V"},
+		{sdkPlugin{sdkNPM, "bufbuild", "es"}, "npm install @buf/acme_petapis.bufbuild_es@V"},
+		{sdkPlugin{sdkMaven, "grpc", "java"}, "build.buf.gen:acme_petapis_grpc_java:V"},
+		{sdkPlugin{sdkPython, "protocolbuffers", "python"}, "pip install acme-petapis-protocolbuffers-python==V --extra-index-url https://buf.build/gen/python"},
+	} {
+		ok.Equal(t, test.plugin.installCommand("buf.build", "acme", "petapis", "V"), test.want)
+	}
+}
+
+// TestSDKsTab verifies the SDK tab works out each SDK's version for the
+// commit from its registry's latest one, and says which aren't available.
+func TestSDKsTab(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModel(startFakeServer(t))
+	m.resize(160, 40)
+	m.remote = "buf.build"
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	m.currentCommitID = "7a6bc1e3207144b38e9066861e1de0ff"
+	m.currentCommit = &modulev1.Commit{
+		Id:         m.currentCommitID,
+		CreateTime: timestamppb.New(time.Date(2026, 9, 1, 19, 30, 5, 0, time.UTC)),
+	}
+	m.state = modelStateBrowsingCommitContents
+	m.activeCommitTab = commitTabSDKs
+
+	cmd := m.loadTabIfNeeded()
+	ok.True(t, m.loadingSDKs)
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+	ok.True(t, m.sdksLoaded)
+
+	var rows []string
+	for _, item := range m.sdkList.Items() {
+		rows = append(rows, item.(*sdkItem).Description())
+	}
+	ok.Equal(t, strings.Join(rows, "\n"), strings.Join([]string{
+		"go get buf.build/gen/go/bufbuild/registry/protocolbuffers/go@v1.36.11-20260901193005-7a6bc1e32071.1",
+		"go get buf.build/gen/go/bufbuild/registry/connectrpc/go@v1.20.0-20260901193005-7a6bc1e32071.1",
+		"go get buf.build/gen/go/bufbuild/registry/grpc/go@v1.6.0-20260901193005-7a6bc1e32071.2",
+		"npm install @buf/bufbuild_registry.bufbuild_es@2.10.2-20260901193005-7a6bc1e32071.1",
+		"build.buf.gen:bufbuild_registry_protocolbuffers_java:33.1.0.1.20260901193005.7a6bc1e32071",
+		"unavailable: not available",
+		"pip install bufbuild-registry-protocolbuffers-python==33.1.0.1.20260901193005+7a6bc1e32071 --extra-index-url https://buf.build/gen/python",
+		"pip install bufbuild-registry-grpc-python==1.76.0.1.20260901193005+7a6bc1e32071 --extra-index-url https://buf.build/gen/python",
+	}, "\n"))
+
+	// The version is one of the ways to copy an SDK.
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	m = updated.(model)
+	ok.True(t, strings.Contains(ansi.Strip(m.footerView()), "v version"))
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	m = updated.(model)
+	ok.True(t, cmd != nil)
+	ok.True(t, strings.Contains(ansi.Strip(m.sdkList.View()), "copied version"))
+
+	// A response for a commit since left is dropped.
+	updated, _ = m.Update(sdksMsg{commitID: "other"})
+	m = updated.(model)
+	ok.Equal(t, len(m.sdkList.Items()), len(sdkPlugins))
+}
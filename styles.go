@@ -34,6 +34,20 @@ var (
 		Light: lipgloss.Color(errorRed),
 		Dark:  lipgloss.Color(errorPink),
 	}
+	// colorDiffAdded, colorDiffRemoved and colorDiffModified mark diffed
+	// lines and files (see diff.go).
+	colorDiffAdded = compat.AdaptiveColor{
+		Light: lipgloss.Color("#1a7f37"),
+		Dark:  lipgloss.Color("#56d364"),
+	}
+	colorDiffRemoved = compat.AdaptiveColor{
+		Light: lipgloss.Color(errorRed),
+		Dark:  lipgloss.Color(errorPink),
+	}
+	colorDiffModified = compat.AdaptiveColor{
+		Light: lipgloss.Color("#9a6700"),
+		Dark:  lipgloss.Color("#e3b341"),
+	}
 	codeStyleLight = chromastyles.Get("modus-operandi")
 	codeStyleDark  = chromastyles.Get("modus-vivendi")
 )
@@ -10,15 +10,19 @@ import (
 type commitTab int
 
 const (
-	commitTabDocs commitTab = iota
+	commitTabOverview commitTab = iota
+	commitTabDocs
 	commitTabFiles
 	commitTabLabels
 	commitTabDeps
+	commitTabSDKs
 	commitTabCount // sentinel for wrapping
 )
 
 func (t commitTab) String() string {
 	switch t {
+	case commitTabOverview:
+		return "Overview"
 	case commitTabDocs:
 		return "Docs"
 	case commitTabFiles:
@@ -27,16 +31,20 @@ func (t commitTab) String() string {
 		return "Labels"
 	case commitTabDeps:
 		return "Deps"
+	case commitTabSDKs:
+		return "SDKs"
 	default:
 		return ""
 	}
 }
 
 var allCommitTabs = []commitTab{
+	commitTabOverview,
 	commitTabDocs,
 	commitTabFiles,
 	commitTabLabels,
 	commitTabDeps,
+	commitTabSDKs,
 }
 
 // renderTabBar renders a horizontal tab bar with the active tab highlighted.
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"connectrpc.com/connect"
+)
+
+// asOfSeparator separates a reference from the time to open it as of, e.g.
+// "owner/module@2026-09-01" or "owner/module:main@2026-09-01T12:00:00Z".
+const asOfSeparator = "@"
+
+// parseAsOf parses the time a reference is opened as of: either an RFC 3339
+// timestamp, or a date, which means the end of that day in UTC -- so
+// "@2026-09-01" includes everything pushed on the 1st.
+func parseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expecting a date (%s) or RFC 3339 timestamp after %q, got %q", time.DateOnly, asOfSeparator, value)
+	}
+	return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// getResourceAsOf resolves reference as of asOf (see resolveCommitAsOf),
+// reporting the commit found as the resource retrieved, as getResource
+// would for a commit reference.
+func (c *client) getResourceAsOf(reference *modulev1.ResourceRef_Name, asOf time.Time) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		commit, err := c.resolveCommitAsOf(ctx, reference, asOf)
+		if err != nil {
+			return errMsg{err}
+		}
+		return resourceMsg{
+			requestedResource: reference,
+			retrievedResource: &modulev1.Resource{
+				Value: &modulev1.Resource_Commit{Commit: commit},
+			},
+		}
+	}
+}
+
+// resolveReferenceCommit returns the commit reference resolves to now (see
+// resolveCommit), or as of asOf unless it's the zero time (see
+// resolveCommitAsOf), for the subcommands that take a -r reference.
+func (c *client) resolveReferenceCommit(ctx context.Context, reference *modulev1.ResourceRef_Name, asOf time.Time) (*modulev1.Commit, error) {
+	if asOf.IsZero() {
+		return c.resolveCommit(ctx, reference)
+	}
+	return c.resolveCommitAsOf(ctx, reference, asOf)
+}
+
+// resolveCommitAsOf returns the commit reference resolved to at asOf. For a
+// module, that's the latest commit pushed at or before asOf; for a label,
+// it's the latest commit in the label's history pushed at or before asOf.
+// The BSR doesn't record when a label was moved, so the latter assumes it
+// was moved to each commit as it was pushed.
+//
+// Both page back through history from the newest commit, so resolving a
+// time long ago on a busy module takes a while. A commit is what it is at
+// any time, so a ref that isn't a label -- a commit ID -- is an error.
+func (c *client) resolveCommitAsOf(ctx context.Context, reference *modulev1.ResourceRef_Name, asOf time.Time) (*modulev1.Commit, error) {
+	var (
+		commit *modulev1.Commit
+		err    error
+	)
+	if label := reference.GetRef(); label != "" {
+		if err := c.checkLabel(ctx, reference.Owner, reference.Module, label); err != nil {
+			return nil, err
+		}
+		commit, err = c.labelCommitAsOf(ctx, reference.Owner, reference.Module, label, asOf)
+	} else {
+		commit, err = c.moduleCommitAsOf(ctx, reference.Owner, reference.Module, asOf)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		name := reference.Owner + "/" + reference.Module
+		if label := reference.GetRef(); label != "" {
+			name += ":" + label
+		}
+		return nil, fmt.Errorf("%s has no commits at or before %s", name, asOf.Format(time.RFC3339))
+	}
+	return commit, nil
+}
+
+// checkLabel returns an error unless label is one of owner/module's labels.
+func (c *client) checkLabel(ctx context.Context, owner, module, label string) error {
+	_, err := c.labelServiceClient.GetLabels(ctx, connect.NewRequest(&modulev1.GetLabelsRequest{
+		LabelRefs: []*modulev1.LabelRef{{
+			Value: &modulev1.LabelRef_Name_{
+				Name: &modulev1.LabelRef_Name{
+					Owner:  owner,
+					Module: module,
+					Label:  label,
+				},
+			},
+		}},
+	}))
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		return fmt.Errorf("%s/%s has no label %q: only a module or label can be opened as of a time, not a commit", owner, module, label)
+	}
+	if err != nil {
+		return fmt.Errorf("getting label %q: %w", label, err)
+	}
+	return nil
+}
+
+func (c *client) moduleCommitAsOf(ctx context.Context, owner, module string, asOf time.Time) (*modulev1.Commit, error) {
+	pageToken := ""
+	for {
+		response, err := c.commitServiceClient.ListCommits(ctx, connect.NewRequest(&modulev1.ListCommitsRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+			ResourceRef: &modulev1.ResourceRef{
+				Value: &modulev1.ResourceRef_Name_{
+					Name: &modulev1.ResourceRef_Name{
+						Owner:  owner,
+						Module: module,
+					},
+				},
+			},
+			Order: modulev1.ListCommitsRequest_ORDER_CREATE_TIME_DESC,
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("listing commits: %w", err)
+		}
+		if commit := firstCommitAsOf(response.Msg.Commits, asOf); commit != nil {
+			return commit, nil
+		}
+		if response.Msg.NextPageToken == "" {
+			return nil, nil
+		}
+		pageToken = response.Msg.NextPageToken
+	}
+}
+
+func (c *client) labelCommitAsOf(ctx context.Context, owner, module, label string, asOf time.Time) (*modulev1.Commit, error) {
+	pageToken := ""
+	for {
+		response, err := c.labelServiceClient.ListLabelHistory(ctx, connect.NewRequest(&modulev1.ListLabelHistoryRequest{
+			PageSize:  pageSize,
+			PageToken: pageToken,
+			LabelRef: &modulev1.LabelRef{
+				Value: &modulev1.LabelRef_Name_{
+					Name: &modulev1.LabelRef_Name{
+						Owner:  owner,
+						Module: module,
+						Label:  label,
+					},
+				},
+			},
+			Order: modulev1.ListLabelHistoryRequest_ORDER_DESC,
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("listing label history: %w", err)
+		}
+		commits := make([]*modulev1.Commit, len(response.Msg.Values))
+		for i, value := range response.Msg.Values {
+			commits[i] = value.Commit
+		}
+		if commit := firstCommitAsOf(commits, asOf); commit != nil {
+			return commit, nil
+		}
+		if response.Msg.NextPageToken == "" {
+			return nil, nil
+		}
+		pageToken = response.Msg.NextPageToken
+	}
+}
+
+// firstCommitAsOf returns the first of commits, which are newest first,
+// pushed at or before asOf.
+func firstCommitAsOf(commits []*modulev1.Commit, asOf time.Time) *modulev1.Commit {
+	for _, commit := range commits {
+		if !commit.CreateTime.AsTime().After(asOf) {
+			return commit
+		}
+	}
+	return nil
+}
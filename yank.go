@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// yankOption is one of the ways the yank menu offers to copy the
+// selection: key picks it, label describes it in the footer, and text is
+// what gets copied.
+type yankOption struct {
+	key   string
+	label string
+	text  string
+}
+
+// yankTarget is what the yank menu was opened on. ref is the label or
+// commit ID to reference the module at, if any; commit, file, message and
+// sdk are set when the selection has one. contentsFirst lists the file's
+// contents first, as in the file viewer, where that's what y alone used to
+// copy.
+type yankTarget struct {
+	url     string
+	remote  string
+	owner   string
+	module  string
+	ref     string
+	commit  *modulev1.Commit
+	file    *modulev1.File
+	message string
+	sdk     *sdkItem
+
+	contentsFirst bool
+}
+
+// yankOptions returns the ways target can be copied, in the order the menu
+// lists them.
+func yankOptions(target yankTarget) []yankOption {
+	var options []yankOption
+	if target.url != "" {
+		options = append(options, yankOption{"u", "URL", strings.TrimPrefix(target.url, "https://")})
+	}
+	if target.owner != "" && target.module != "" {
+		ref := target.ref
+		if ref == "" && target.commit != nil {
+			ref = target.commit.Id
+		}
+		name := target.remote + "/" + target.owner + "/" + target.module
+		reference := target.owner + "/" + target.module
+		if target.remote != defaultRemote {
+			reference = name
+		}
+		if ref != "" {
+			reference += ":" + ref
+		}
+		dep := name
+		if ref != "" {
+			dep += ":" + ref
+		}
+		options = append(options,
+			yankOption{"r", "reference", reference},
+			yankOption{"d", "buf.yaml dep", "- " + dep},
+		)
+		if target.commit != nil && target.commit.Digest != nil {
+			options = append(options, yankOption{"l", "buf.lock entry", fmt.Sprintf(
+				"  - name: %s\n    commit: %s\n    digest: %s", name, target.commit.Id, digestString(target.commit.Digest),
+			)})
+		}
+		goVersion, npmVersion := "latest", "latest"
+		if target.commit != nil {
+			goVersion, npmVersion = target.commit.Id, "commit-"+target.commit.Id
+		}
+		options = append(options,
+			yankOption{"g", "go get", fmt.Sprintf("go get %s/gen/go/%s/%s/protocolbuffers/go@%s", target.remote, target.owner, target.module, goVersion)},
+			yankOption{"n", "npm install", fmt.Sprintf("npm install @buf/%s_%s.bufbuild_es@%s", target.owner, target.module, npmVersion)},
+		)
+	}
+	if target.file != nil {
+		contents := yankOption{"c", "contents", string(target.file.Content)}
+		if target.contentsFirst {
+			options = append([]yankOption{contents}, options...)
+		} else {
+			options = append(options, contents)
+		}
+	}
+	if target.message != "" {
+		options = append(options, yankOption{"p", ".proto", target.message})
+	}
+	if target.sdk != nil {
+		options = append(options,
+			yankOption{"i", "install command", target.sdk.install},
+			yankOption{"v", "version", target.sdk.sdk.version},
+		)
+	}
+	return options
+}
+
+// yankTarget returns what the yank menu should offer to copy in the current
+// state and tab, or false if nothing there can be copied.
+func (m model) yankTarget() (yankTarget, bool) {
+	target := yankTarget{remote: m.remote, owner: m.currentOwner, module: m.currentModule}
+	switch m.state {
+	case modelStateBrowsingModules:
+		module, ok := m.moduleList.SelectedItem().(*module)
+		if !ok {
+			return target, false
+		}
+		target.module = module.underlying.Name
+		target.url = m.buildBrowserURL("module", module.underlying.Name)
+		return target, true
+	case modelStateBrowsingCommits:
+		if m.historyQuery != "" {
+			change, ok := m.historyList.SelectedItem().(*historyChange)
+			if !ok {
+				return target, false
+			}
+			target.commit = change.commit
+		} else {
+			commit, ok := m.commitList.SelectedItem().(*commit)
+			if !ok {
+				return target, false
+			}
+			target.commit = commit.underlying
+		}
+		target.url = m.buildBrowserURL("tree", target.commit.Id)
+		return target, true
+	case modelStateBrowsingCommitContents, modelStateBrowsingCommitFileContents:
+	default:
+		return target, false
+	}
+
+	target.commit = m.currentCommit
+	if target.commit == nil || target.commit.Id != m.currentCommitID {
+		target.commit = &modulev1.Commit{Id: m.currentCommitID}
+	}
+	target.url = m.buildBrowserURL("tree", m.currentCommitID)
+	switch m.activeCommitTab {
+	case commitTabFiles:
+		if m.state == modelStateBrowsingCommitContents && m.filesTreeShown() && selectedFilesTreeFile(m.filesTree) == nil {
+			// Nothing to copy for a directory.
+			return target, false
+		}
+		commitFile, ok := m.commitFilesList.SelectedItem().(*commitFile)
+		if !ok {
+			return target, false
+		}
+		target.url = m.buildBrowserURL("file", commitFile.underlying.Path)
+		target.file = commitFile.underlying
+		target.contentsFirst = m.state == modelStateBrowsingCommitFileContents
+	case commitTabLabels:
+		label, ok := m.labelsList.SelectedItem().(*labelItem)
+		if !ok {
+			return target, false
+		}
+		target.ref = label.underlying.Name
+		target.commit = &modulev1.Commit{Id: label.underlying.CommitId}
+		for _, c := range m.currentCommits {
+			if c.Id == label.underlying.CommitId {
+				target.commit = c
+			}
+		}
+		target.url = m.buildBrowserURL("tree", label.underlying.CommitId)
+	case commitTabDeps:
+		dep, ok := selectedDepNode(m.depsTree)
+		if !ok || dep.href == "" {
+			return target, false
+		}
+		target = yankTarget{url: dep.href, remote: m.remote, owner: dep.owner, module: dep.module, commit: dep.commit}
+	case commitTabDocs:
+		if msg := m.docsMessageInView(); msg != nil {
+			target.message = messageSource(msg, m.currentCommitFiles)
+		}
+	case commitTabSDKs:
+		if sdk, ok := m.sdkList.SelectedItem().(*sdkItem); ok && sdk.sdk.err == nil {
+			target.sdk = sdk
+		}
+	}
+	return target, true
+}
+
+// openYankMenu opens the yank menu on the current selection, if there's
+// anything to copy.
+func (m *model) openYankMenu() {
+	if target, ok := m.yankTarget(); ok {
+		m.yankOptions = yankOptions(target)
+	}
+}
+
+// yank closes the yank menu, copying the option picked with pick if there's
+// one ("y" again picks the first, so yy copies what y alone used to: a URL,
+// or in the file viewer the file's contents), and reports what was copied
+// where the selection is shown.
+func (m *model) yank(pick string) tea.Cmd {
+	options := m.yankOptions
+	m.yankOptions = nil
+	for i, option := range options {
+		if option.key != pick && (pick != "y" || i != 0) {
+			continue
+		}
+		status := "copied " + option.label
+		var statusCmd tea.Cmd
+		switch {
+		case m.state == modelStateBrowsingModules:
+			statusCmd = m.moduleList.NewStatusMessage(status)
+		case m.state == modelStateBrowsingCommits && m.historyQuery != "":
+			statusCmd = m.historyList.NewStatusMessage(status)
+		case m.state == modelStateBrowsingCommits:
+			statusCmd = m.commitList.NewStatusMessage(status)
+		case m.activeCommitTab == commitTabFiles:
+			statusCmd = m.filesStatusMessage(status)
+		case m.activeCommitTab == commitTabLabels:
+			statusCmd = m.labelsList.NewStatusMessage(status)
+		case m.activeCommitTab == commitTabDeps:
+			statusCmd = m.setDepsStatus(status)
+		case m.activeCommitTab == commitTabDocs:
+			statusCmd = m.docsList.NewStatusMessage(status)
+		case m.activeCommitTab == commitTabSDKs:
+			statusCmd = m.sdkList.NewStatusMessage(status)
+		}
+		return tea.Batch(tea.SetClipboard(option.text), statusCmd)
+	}
+	return nil
+}
+
+// yankMenuView renders the yank menu's options for the footer.
+func (m model) yankMenuView() string {
+	bindings := make([]key.Binding, 0, len(m.yankOptions)+1)
+	for _, option := range m.yankOptions {
+		bindings = append(bindings, key.NewBinding(key.WithKeys(option.key), key.WithHelp(option.key, option.label)))
+	}
+	bindings = append(bindings, key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")))
+	return "copy " + m.help.ShortHelpView(bindings)
+}
+
+// docsMessageInView returns the message whose section of the docs page is
+// at the current search match, or else at the top of the viewport, or nil
+// if that's not a message's section.
+func (m model) docsMessageInView() protoreflect.MessageDescriptor {
+	pkg, ok := m.docsList.SelectedItem().(*docsPackage)
+	if !ok {
+		return nil
+	}
+	content := m.docsViewport.GetContent()
+	line := m.docsViewport.YOffset()
+	if m.docsMatchIdx >= 0 && m.docsMatchIdx < len(m.docsMatches) {
+		line = docsMatchLine(content, m.docsMatches[m.docsMatchIdx][0])
+	}
+	return docsMessageAt(pkg, ansi.Strip(content), line)
+}
+
+// docsMessageAt returns the message whose section of a renderPackage page,
+// stripped of ANSI escapes, contains the given line, or nil if that's not a
+// message's section. A section starts at its header, the line above a rule
+// of "─"; a nested message's header is its dotted path, e.g. "Outer.Inner".
+func docsMessageAt(pkg *docsPackage, page string, line int) protoreflect.MessageDescriptor {
+	lines := strings.Split(page, "\n")
+	line = min(line, len(lines)-1)
+	for i := line; i >= 0; i-- {
+		if i+1 >= len(lines) || lines[i+1] == "" || strings.Trim(lines[i+1], "─") != "" {
+			continue
+		}
+		path := strings.Fields(lines[i])
+		if len(path) == 0 {
+			return nil
+		}
+		var msg protoreflect.MessageDescriptor
+		for n, name := range strings.Split(path[0], ".") {
+			if n == 0 {
+				for _, top := range pkg.messages {
+					if string(top.Name()) == name {
+						msg = top
+					}
+				}
+			} else if msg != nil {
+				msg = msg.Messages().ByName(protoreflect.Name(name))
+			}
+			if msg == nil {
+				return nil
+			}
+		}
+		return msg
+	}
+	return nil
+}
+
+// messageSource returns msg's definition as written in its .proto file among
+// files, with its leading comment and without the indentation it's nested
+// at, or "" if the file or its source info isn't available.
+func messageSource(msg protoreflect.MessageDescriptor, files []*modulev1.File) string {
+	loc := msg.ParentFile().SourceLocations().ByDescriptor(msg)
+	if loc.EndLine == 0 && loc.StartLine == 0 && loc.EndColumn == 0 {
+		return ""
+	}
+	var content []byte
+	for _, file := range files {
+		if file.Path == msg.ParentFile().Path() {
+			content = file.Content
+		}
+	}
+	lines := strings.Split(string(content), "\n")
+	if loc.EndLine >= len(lines) || loc.StartColumn > len(lines[loc.StartLine]) || loc.EndColumn > len(lines[loc.EndLine]) {
+		return ""
+	}
+	var b strings.Builder
+	if comment := strings.TrimSuffix(loc.LeadingComments, "\n"); comment != "" {
+		for line := range strings.SplitSeq(comment, "\n") {
+			b.WriteString("//" + line + "\n")
+		}
+	}
+	for i := loc.StartLine; i <= loc.EndLine; i++ {
+		line := lines[i]
+		if i == loc.EndLine {
+			line = line[:loc.EndColumn]
+		}
+		if i == loc.StartLine {
+			line = line[loc.StartColumn:]
+		} else {
+			// Dedent by however far the definition itself is indented.
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			line = line[min(indent, loc.StartColumn):]
+		}
+		b.WriteString(line)
+		if i != loc.EndLine {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
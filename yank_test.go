@@ -0,0 +1,198 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"go.vanburen.xyz/ok"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestYankOptions(t *testing.T) {
+	t.Parallel()
+
+	texts := func(options []yankOption) map[string]string {
+		texts := make(map[string]string)
+		for _, option := range options {
+			texts[option.key] = option.text
+		}
+		return texts
+	}
+
+	commit := &modulev1.Commit{
+		Id:     "abc123def456",
+		Digest: &modulev1.Digest{Type: modulev1.DigestType_DIGEST_TYPE_B5, Value: []byte{0xab, 0xcd}},
+	}
+	got := texts(yankOptions(yankTarget{
+		url:    "https://buf.build/acme/petapis/commits/abc123def456",
+		remote: "buf.build",
+		owner:  "acme",
+		module: "petapis",
+		ref:    "main",
+		commit: commit,
+	}))
+	ok.DeepEqual(t, got, map[string]string{
+		"u": "buf.build/acme/petapis/commits/abc123def456",
+		"r": "acme/petapis:main",
+		"d": "- buf.build/acme/petapis:main",
+		"l": "  - name: buf.build/acme/petapis\n    commit: abc123def456\n    digest: b5:abcd",
+		"g": "go get buf.build/gen/go/acme/petapis/protocolbuffers/go@abc123def456",
+		"n": "npm install @buf/acme_petapis.bufbuild_es@commit-abc123def456",
+	})
+
+	// A module on another remote, with no commit, references its remote and
+	// the latest SDKs, and has no lock entry to offer.
+	got = texts(yankOptions(yankTarget{remote: "buf.example.com", owner: "acme", module: "petapis"}))
+	ok.Equal(t, got["r"], "buf.example.com/acme/petapis")
+	ok.Equal(t, got["g"], "go get buf.example.com/gen/go/acme/petapis/protocolbuffers/go@latest")
+	ok.Equal(t, got["n"], "npm install @buf/acme_petapis.bufbuild_es@latest")
+	_, hasLock := got["l"]
+	ok.True(t, !hasLock)
+
+	got = texts(yankOptions(yankTarget{
+		remote:  "buf.build",
+		owner:   "acme",
+		module:  "petapis",
+		commit:  &modulev1.Commit{Id: "abc123def456"},
+		file:    &modulev1.File{Path: "pet.proto", Content: []byte("syntax = \"proto3\";\n")},
+		message: "message Pet {}",
+	}))
+	ok.Equal(t, got["r"], "acme/petapis:abc123def456")
+	ok.Equal(t, got["c"], "syntax = \"proto3\";\n")
+	ok.Equal(t, got["p"], "message Pet {}")
+
+	// In the file viewer, yy copies the file's contents.
+	file := &modulev1.File{Path: "pet.proto", Content: []byte("syntax = \"proto3\";\n")}
+	options := yankOptions(yankTarget{url: "https://buf.build/acme/petapis/file/main:pet.proto", file: file})
+	ok.Equal(t, options[0].key, "u")
+	options = yankOptions(yankTarget{url: "https://buf.build/acme/petapis/file/main:pet.proto", file: file, contentsFirst: true})
+	ok.Equal(t, options[0].key, "c")
+}
+
+// yankTestFile is a file with a nested message, and the descriptor for it
+// with the source info protocompile would give it.
+func yankTestFile(t *testing.T) (*modulev1.File, protoreflect.FileDescriptor) {
+	t.Helper()
+	content := strings.Join([]string{
+		`syntax = "proto3";`,
+		``,
+		`package acme;`,
+		``,
+		`// Outer does things.`,
+		`message Outer {`,
+		`  // Inner is nested.`,
+		`  message Inner {`,
+		`    string name = 1;`,
+		`  }`,
+		`}`,
+		``,
+	}, "\n")
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    new("acme.proto"),
+		Syntax:  new("proto3"),
+		Package: new("acme"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: new("Outer"),
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: new("Inner"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: new("name"), Number: new(int32(1)), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), JsonName: new("name")},
+				},
+			}},
+		}},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+			{Path: []int32{4, 0}, Span: []int32{5, 0, 10, 1}, LeadingComments: new(" Outer does things.\n")},
+			{Path: []int32{4, 0, 3, 0}, Span: []int32{7, 2, 9, 3}, LeadingComments: new(" Inner is nested.\n")},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	ok.MustNoError(t, err)
+	return &modulev1.File{Path: "acme.proto", Content: []byte(content)}, fd
+}
+
+func TestMessageSource(t *testing.T) {
+	t.Parallel()
+
+	file, fd := yankTestFile(t)
+	outer := fd.Messages().ByName("Outer")
+	ok.Equal(t, messageSource(outer.Messages().ByName("Inner"), []*modulev1.File{file}),
+		"// Inner is nested.\nmessage Inner {\n  string name = 1;\n}")
+	ok.Equal(t, messageSource(outer, []*modulev1.File{file}),
+		"// Outer does things.\nmessage Outer {\n  // Inner is nested.\n  message Inner {\n    string name = 1;\n  }\n}")
+	// Without the file there's nothing to copy.
+	ok.Equal(t, messageSource(outer, nil), "")
+}
+
+func TestDocsMessageAt(t *testing.T) {
+	t.Parallel()
+
+	_, fd := yankTestFile(t)
+	pkg := &docsPackage{messages: []protoreflect.MessageDescriptor{fd.Messages().ByName("Outer")}}
+	page := strings.Join([]string{
+		`syntax = "proto3";`,
+		``,
+		`Outer`,
+		`─────`,
+		`Outer does things.`,
+		``,
+		`Outer.Inner  [deprecated]`,
+		`─────────────────────────`,
+		`  name  string`,
+		``,
+		`Status`,
+		`──────`,
+		`  STATUS_UNSPECIFIED = 0`,
+	}, "\n")
+	name := func(line int) string {
+		if msg := docsMessageAt(pkg, page, line); msg != nil {
+			return string(msg.FullName())
+		}
+		return ""
+	}
+	ok.Equal(t, name(0), "")
+	ok.Equal(t, name(2), "acme.Outer")
+	ok.Equal(t, name(5), "acme.Outer")
+	ok.Equal(t, name(8), "acme.Outer.Inner")
+	// Status is an enum, not a message.
+	ok.Equal(t, name(12), "")
+}
+
+// TestYankMenu verifies y opens the yank menu on the selected commit, a key
+// picks what to copy, and anything else closes it without copying.
+func TestYankMenu(t *testing.T) {
+	t.Parallel()
+
+	c := startFakeServer(t)
+	m := newTestModel(c)
+	m.resize(120, 40)
+	m.currentOwner = "bufbuild"
+	m.currentModule = "registry"
+	updated, _ := m.Update(c.listCommits(m.currentOwner, m.currentModule)())
+	m = updated.(model)
+	press := func(code rune, text string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyPressMsg{Code: code, Text: text})
+		m = updated.(model)
+		return cmd
+	}
+
+	press('y', "y")
+	ok.True(t, m.yankOptions != nil)
+	footer := ansi.Strip(m.footerView())
+	ok.True(t, strings.Contains(footer, "u URL") && strings.Contains(footer, "r reference"), ok.Sprintf("expected the options in the footer:\n%s", footer))
+
+	// Moving on closes the menu rather than moving the selection.
+	ok.True(t, press('j', "j") == nil)
+	ok.True(t, m.yankOptions == nil)
+	ok.Equal(t, m.commitList.Index(), 0)
+
+	press('y', "y")
+	ok.True(t, press('r', "r") != nil)
+	ok.True(t, m.yankOptions == nil)
+	ok.True(t, strings.Contains(ansi.Strip(m.commitList.View()), "copied reference"))
+}